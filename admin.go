@@ -0,0 +1,230 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// RunningQuery is a query running in the graph service, as shown by SHOW QUERIES
+type RunningQuery struct {
+	SessionID int64
+	PlanID    int64
+	User      string
+	// The graph host executing the query
+	Host      string
+	StartTime time.Time
+	Duration  time.Duration
+	Status    string
+	Query     string
+}
+
+// ShowQueries returns the queries running in the graph services of the cluster,
+// or only in the graph service the session is connected to if local is true.
+func (session *Session) ShowQueries(ctx context.Context, local bool) ([]RunningQuery, error) {
+	stmt := "SHOW QUERIES"
+	if local {
+		stmt = "SHOW LOCAL QUERIES"
+	}
+	resp, err := session.executeAdmin(ctx, stmt, "show queries")
+	if err != nil {
+		return nil, err
+	}
+	return parseRunningQueries(resp)
+}
+
+func parseRunningQueries(resp *ResultSet) ([]RunningQuery, error) {
+	queries := make([]RunningQuery, 0, resp.GetRowSize())
+	for i := 0; i < resp.GetRowSize(); i++ {
+		record, err := resp.GetRowValuesByIndex(i)
+		if err != nil {
+			return nil, err
+		}
+		var q RunningQuery
+		var durationUs int64
+		r := recordReader{record: record}
+		r.int("SessionID", &q.SessionID)
+		r.int("ExecutionPlanID", &q.PlanID)
+		r.string("User", &q.User)
+		r.string("Host", &q.Host)
+		r.time("StartTime", &q.StartTime)
+		r.int("DurationInUSec", &durationUs)
+		r.string("Status", &q.Status)
+		r.string("Query", &q.Query)
+		if r.err != nil {
+			return nil, fmt.Errorf("failed to show queries: %s", r.err.Error())
+		}
+		q.Duration = time.Duration(durationUs) * time.Microsecond
+		queries = append(queries, q)
+	}
+	return queries, nil
+}
+
+// KillQuery kills the query executing the plan in the session
+func (session *Session) KillQuery(ctx context.Context, sessionID, planID int64) error {
+	_, err := session.executeAdmin(ctx,
+		fmt.Sprintf("KILL QUERY (session=%d, plan=%d)", sessionID, planID), "kill query")
+	return err
+}
+
+// ServerSession is a session of the graph service, as shown by SHOW SESSIONS
+type ServerSession struct {
+	ID         int64
+	User       string
+	Space      string
+	CreateTime time.Time
+	// The last time the session executed a statement
+	UpdateTime time.Time
+	// The address of the graph host the session belongs to
+	GraphAddr string
+	Timezone  int64
+	ClientIP  string
+}
+
+// ShowSessions returns the sessions of the cluster
+func (session *Session) ShowSessions(ctx context.Context) ([]ServerSession, error) {
+	resp, err := session.executeAdmin(ctx, "SHOW SESSIONS", "show sessions")
+	if err != nil {
+		return nil, err
+	}
+	return parseServerSessions(resp)
+}
+
+func parseServerSessions(resp *ResultSet) ([]ServerSession, error) {
+	sessions := make([]ServerSession, 0, resp.GetRowSize())
+	for i := 0; i < resp.GetRowSize(); i++ {
+		record, err := resp.GetRowValuesByIndex(i)
+		if err != nil {
+			return nil, err
+		}
+		var s ServerSession
+		r := recordReader{record: record}
+		r.int("SessionId", &s.ID)
+		r.string("UserName", &s.User)
+		r.string("SpaceName", &s.Space)
+		r.time("CreateTime", &s.CreateTime)
+		r.time("UpdateTime", &s.UpdateTime)
+		r.string("GraphAddr", &s.GraphAddr)
+		r.int("Timezone", &s.Timezone)
+		r.string("ClientIp", &s.ClientIP)
+		if r.err != nil {
+			return nil, fmt.Errorf("failed to show sessions: %s", r.err.Error())
+		}
+		sessions = append(sessions, s)
+	}
+	return sessions, nil
+}
+
+// KillSession kills the session of the graph service with the given ID, it requires a graph service 3.4 or later
+func (session *Session) KillSession(ctx context.Context, sessionID int64) error {
+	if version := session.ServerVersion(); version.IsKnown() && !version.AtLeast(3, 4, 0) {
+		return fmt.Errorf("failed to kill session: not supported by the graph service %s", version)
+	}
+	_, err := session.executeAdmin(ctx, fmt.Sprintf("KILL SESSION %d", sessionID), "kill session")
+	return err
+}
+
+// CleanupStaleSessions kills the sessions of the cluster which have not executed a statement
+// for longer than olderThan, according to the clock of the pool, and returns their IDs.
+// The session used to run the cleanup is never killed.
+// It stops at the first session which can not be killed and returns the sessions killed so far with the error.
+func (session *Session) CleanupStaleSessions(ctx context.Context, olderThan time.Duration) ([]int64, error) {
+	sessions, err := session.ShowSessions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var killed []int64
+	for _, s := range staleSessions(sessions, session.GetSessionID(), session.connPool.conf.Clock.Now().Add(-olderThan)) {
+		if err := session.KillSession(ctx, s.ID); err != nil {
+			return killed, err
+		}
+		killed = append(killed, s.ID)
+	}
+	return killed, nil
+}
+
+// staleSessions returns the sessions other than self which were last updated before the given time
+func staleSessions(sessions []ServerSession, self int64, before time.Time) []ServerSession {
+	var stale []ServerSession
+	for _, s := range sessions {
+		if s.ID != self && s.UpdateTime.Before(before) {
+			stale = append(stale, s)
+		}
+	}
+	return stale
+}
+
+// executeAdmin executes an administrative statement and returns an error if it did not succeed.
+// The statement must either not depend on a space or start with USE, as it is not subject to ExplicitSpace.
+func (session *Session) executeAdmin(ctx context.Context, stmt, what string) (*ResultSet, error) {
+	return session.executeChecked(withExplicitSpace(ctx), stmt, what)
+}
+
+// executeChecked executes the statement in the space of the session and returns an error if it did not succeed
+func (session *Session) executeChecked(ctx context.Context, stmt, what string) (*ResultSet, error) {
+	resp, err := session.ExecuteWithContext(ctx, stmt, nil)
+	if err != nil {
+		return nil, err
+	}
+	if !resp.IsSucceed() {
+		return nil, fmt.Errorf("failed to %s: %s", what, resp.GetErrorMsg())
+	}
+	return resp, nil
+}
+
+// recordReader reads the columns of a record and keeps the first error
+type recordReader struct {
+	record *Record
+	err    error
+}
+
+func (r *recordReader) value(col string) *ValueWrapper {
+	if r.err != nil {
+		return nil
+	}
+	val, err := r.record.GetValueByColName(col)
+	if err != nil {
+		r.err = err
+		return nil
+	}
+	if val.IsNull() || val.IsEmpty() {
+		return nil
+	}
+	return val
+}
+
+func (r *recordReader) string(col string, dst *string) {
+	if val := r.value(col); val != nil {
+		*dst, r.err = val.AsString()
+	}
+}
+
+func (r *recordReader) int(col string, dst *int64) {
+	if val := r.value(col); val != nil {
+		*dst, r.err = val.AsInt()
+	}
+}
+
+// time reads a datetime column as a time in UTC
+func (r *recordReader) time(col string, dst *time.Time) {
+	val := r.value(col)
+	if val == nil {
+		return
+	}
+	dt, err := val.AsDateTime()
+	if err != nil {
+		r.err = err
+		return
+	}
+	raw := dt.getRawDateTime()
+	*dst = time.Date(int(raw.Year), time.Month(raw.Month), int(raw.Day),
+		int(raw.Hour), int(raw.Minute), int(raw.Sec), int(raw.Microsec)*1000, time.UTC)
+}
@@ -0,0 +1,311 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vesoft-inc/nebula-go/v3/nebula"
+	"github.com/vesoft-inc/nebula-go/v3/nebula/graph"
+)
+
+// newTestResultSet returns a succeeded result set with the given columns and rows of go values
+func newTestResultSet(t *testing.T, cols []string, rows ...[]interface{}) *ResultSet {
+	dataset := &nebula.DataSet{}
+	for _, col := range cols {
+		dataset.ColumnNames = append(dataset.ColumnNames, []byte(col))
+	}
+	for _, row := range rows {
+		values := make([]*nebula.Value, len(row))
+		for i, v := range row {
+			nv, err := value2Nvalue(v)
+			if err != nil {
+				t.Fatal(err)
+			}
+			values[i] = nv
+		}
+		dataset.Rows = append(dataset.Rows, &nebula.Row{Values: values})
+	}
+	resp := &graph.ExecutionResponse{ErrorCode: nebula.ErrorCode_SUCCEEDED, Data: dataset}
+	resultSet, err := genResultSet(resp, testTimezone)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resultSet
+}
+
+func TestParseRunningQueries(t *testing.T) {
+	resp := newTestResultSet(t,
+		[]string{"SessionID", "ExecutionPlanID", "User", "Host", "StartTime", "DurationInUSec", "Status", "Query"},
+		[]interface{}{1625463842921750, 46, "root", "\"192.168.x.x\":9669",
+			nebula.DateTime{Year: 2021, Month: 7, Day: 5, Hour: 5, Minute: 44, Sec: 23, Microsec: 1500},
+			2500, "RUNNING", "GO 3 STEPS FROM \"player100\" OVER follow"})
+
+	queries, err := parseRunningQueries(resp)
+	assert.Nil(t, err)
+	assert.Equal(t, []RunningQuery{{
+		SessionID: 1625463842921750,
+		PlanID:    46,
+		User:      "root",
+		Host:      "\"192.168.x.x\":9669",
+		StartTime: time.Date(2021, 7, 5, 5, 44, 23, 1500000, time.UTC),
+		Duration:  2500 * time.Microsecond,
+		Status:    "RUNNING",
+		Query:     "GO 3 STEPS FROM \"player100\" OVER follow",
+	}}, queries)
+
+	_, err = parseRunningQueries(newTestResultSet(t, []string{"SessionID"}, []interface{}{"1"}))
+	assert.NotNil(t, err)
+}
+
+func TestParseServerSessions(t *testing.T) {
+	resp := newTestResultSet(t,
+		[]string{"SessionId", "UserName", "SpaceName", "CreateTime", "UpdateTime", "GraphAddr", "Timezone", "ClientIp"},
+		[]interface{}{1651220858102296, "root", "basketballplayer",
+			nebula.DateTime{Year: 2022, Month: 4, Day: 29, Hour: 8, Minute: 27, Sec: 38},
+			nebula.DateTime{Year: 2022, Month: 4, Day: 29, Hour: 8, Minute: 50, Sec: 46},
+			"127.0.0.1:9669", 0, "127.0.0.1"},
+		[]interface{}{1651199330300991, "root", "",
+			nebula.DateTime{Year: 2022, Month: 4, Day: 29, Hour: 2, Minute: 28, Sec: 50},
+			nebula.DateTime{Year: 2022, Month: 4, Day: 29, Hour: 2, Minute: 28, Sec: 50},
+			"127.0.0.1:9669", 0, "127.0.0.1"})
+
+	sessions, err := parseServerSessions(resp)
+	assert.Nil(t, err)
+	assert.Len(t, sessions, 2)
+	assert.Equal(t, ServerSession{
+		ID:         1651220858102296,
+		User:       "root",
+		Space:      "basketballplayer",
+		CreateTime: time.Date(2022, 4, 29, 8, 27, 38, 0, time.UTC),
+		UpdateTime: time.Date(2022, 4, 29, 8, 50, 46, 0, time.UTC),
+		GraphAddr:  "127.0.0.1:9669",
+		ClientIP:   "127.0.0.1",
+	}, sessions[0])
+
+	before := time.Date(2022, 4, 29, 8, 0, 0, 0, time.UTC)
+	assert.Equal(t, []ServerSession{sessions[1]}, staleSessions(sessions, 0, before))
+	assert.Empty(t, staleSessions(sessions, sessions[1].ID, before))
+}
+
+func TestParseRoleAssignments(t *testing.T) {
+	resp := newTestResultSet(t, []string{"role", "space"},
+		[]interface{}{"ADMIN", "basketballplayer"},
+		[]interface{}{"guest", "test"})
+	roles, err := parseRoleAssignments(resp)
+	assert.Nil(t, err)
+	assert.Equal(t, []RoleAssignment{
+		{Role: RoleAdmin, Space: "basketballplayer"},
+		{Role: RoleGuest, Space: "test"},
+	}, roles)
+}
+
+func TestGrantRoleRejectsUnknownRole(t *testing.T) {
+	var calls int
+	conf := NewPoolConf(WithInterceptors(func(ctx context.Context, stmt string, p map[string]interface{}, invoker Invoker) (*ResultSet, error) {
+		calls++
+		return &ResultSet{}, nil
+	}))
+	session := &Session{connPool: &ConnectionPool{conf: conf}}
+	err := session.GrantRole(context.Background(), Role("ADMIN ON s TO u; DROP SPACE s"), "test", "user")
+	assert.EqualError(t, err, `failed to grant role: unknown role "ADMIN ON s TO u; DROP SPACE s"`)
+	err = session.RevokeRole(context.Background(), Role("admin"), "test", "user")
+	assert.EqualError(t, err, `failed to revoke role: unknown role "admin"`)
+	assert.Equal(t, 0, calls)
+}
+
+func TestParseSnapshots(t *testing.T) {
+	resp := newTestResultSet(t, []string{"Name", "Status", "Hosts"},
+		[]interface{}{"SNAPSHOT_2021_03_09_08_43_12", "VALID", "127.0.0.1:9779, 127.0.0.2:9779"})
+	snapshots, err := parseSnapshots(resp)
+	assert.Nil(t, err)
+	assert.Equal(t, []Snapshot{{
+		Name:   "SNAPSHOT_2021_03_09_08_43_12",
+		Status: "VALID",
+		Hosts:  []string{"127.0.0.1:9779", "127.0.0.2:9779"},
+	}}, snapshots)
+}
+
+func TestDropSnapshotQuotesName(t *testing.T) {
+	var stmts []string
+	conf := NewPoolConf(WithInterceptors(func(ctx context.Context, stmt string, p map[string]interface{}, invoker Invoker) (*ResultSet, error) {
+		stmts = append(stmts, stmt)
+		return newTestResultSet(t, nil), nil
+	}))
+	session := &Session{connPool: &ConnectionPool{conf: conf}}
+	assert.Nil(t, session.DropSnapshot(context.Background(), "SNAPSHOT_1; DROP SPACE test"))
+	assert.Equal(t, []string{"DROP SNAPSHOT `SNAPSHOT_1; DROP SPACE test`"}, stmts)
+}
+
+func TestCloneSpaceNotRetried(t *testing.T) {
+	var stmts []string
+	conf := NewPoolConf(WithInterceptors(func(ctx context.Context, stmt string, p map[string]interface{}, invoker Invoker) (*ResultSet, error) {
+		stmts = append(stmts, stmt)
+		resp := newTestResultSet(t, nil)
+		resp.resp.ErrorCode = nebula.ErrorCode_E_LEADER_CHANGED
+		resp.resp.ErrorMsg = []byte("Leader changed")
+		return resp, nil
+	}))
+	conf.Clock = realClock{}
+	session := &Session{connPool: &ConnectionPool{conf: conf}}
+	err := session.CloneSpace(context.Background(), "prod", "staging", time.Millisecond)
+	assert.EqualError(t, err, "failed to clone space prod to staging: Leader changed")
+	assert.Equal(t, []string{"CREATE SPACE `staging` AS `prod`"}, stmts)
+}
+
+func TestAddListenerRejectsUnknownType(t *testing.T) {
+	var calls int
+	conf := NewPoolConf(WithInterceptors(func(ctx context.Context, stmt string, p map[string]interface{}, invoker Invoker) (*ResultSet, error) {
+		calls++
+		return &ResultSet{}, nil
+	}))
+	session := &Session{connPool: &ConnectionPool{conf: conf}}
+	err := session.AddListener(context.Background(), "test", ListenerType("ELASTICSEARCH \"h\":1; DROP SPACE s"),
+		HostAddress{Host: "h", Port: 1})
+	assert.EqualError(t, err, `failed to add listener: unknown listener type "ELASTICSEARCH \"h\":1; DROP SPACE s"`)
+	err = session.RemoveListener(context.Background(), "test", ListenerType("elasticsearch"))
+	assert.EqualError(t, err, `failed to remove listener: unknown listener type "elasticsearch"`)
+	assert.Equal(t, 0, calls)
+}
+
+func TestParseListeners(t *testing.T) {
+	resp := newTestResultSet(t, []string{"PartId", "Type", "Host", "Host Status"},
+		[]interface{}{1, "ELASTICSEARCH", "\"192.168.8.5\":9789", "ONLINE"})
+	listeners, err := parseListeners(resp)
+	assert.Nil(t, err)
+	assert.Equal(t, []Listener{{
+		PartID: 1,
+		Type:   ListenerElasticsearch,
+		Host:   HostAddress{Host: "192.168.8.5", Port: 9789},
+		Status: "ONLINE",
+	}}, listeners)
+
+	resp = newTestResultSet(t, []string{"Host", "Status"}, []interface{}{"192.168.8.6:9889", "OFFLINE"})
+	drainers, err := parseDrainers(resp)
+	assert.Nil(t, err)
+	assert.Equal(t, []Drainer{{Host: HostAddress{Host: "192.168.8.6", Port: 9889}, Status: "OFFLINE"}}, drainers)
+
+	assert.Equal(t, `"192.168.8.5":9789, "192.168.8.6":9789`, formatHosts([]HostAddress{
+		{Host: "192.168.8.5", Port: 9789}, {Host: "192.168.8.6", Port: 9789}}))
+}
+
+func TestServiceConfigs(t *testing.T) {
+	resp := newTestResultSet(t, []string{"module", "name", "type", "mode", "value"},
+		[]interface{}{"GRAPH", "v", "int", "MUTABLE", 0},
+		[]interface{}{"STORAGE", "wal_ttl", "int", "MUTABLE", 14400})
+	configs, err := parseServiceConfigs(resp)
+	assert.Nil(t, err)
+	assert.Len(t, configs, 2)
+	assert.Equal(t, ConfigStorage, configs[1].Component)
+	assert.Equal(t, "wal_ttl", configs[1].Name)
+	assert.Equal(t, "MUTABLE", configs[1].Mode)
+	val, err := configs[1].Value.AsInt()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(14400), val)
+
+	stmt, err := updateConfigStatement(ConfigStorage, "wal_ttl", 3600)
+	assert.Nil(t, err)
+	assert.Equal(t, "UPDATE CONFIGS storage:wal_ttl = 3600", stmt)
+	_, err = updateConfigStatement(ConfigAll, "wal_ttl", 3600)
+	assert.NotNil(t, err)
+	_, err = updateConfigStatement(ConfigGraph, "v = 1; DROP SPACE x", 3)
+	assert.NotNil(t, err)
+
+	assert.True(t, configAllowed(nil, "wal_ttl"))
+	assert.True(t, configAllowed([]string{"v", "wal_ttl"}, "wal_ttl"))
+	assert.False(t, configAllowed([]string{}, "wal_ttl"))
+
+	conf := NewPoolConf(WithConfigUpdateAllowlist("v"))
+	assert.Equal(t, []string{"v"}, conf.ConfigUpdateAllowlist)
+}
+
+func TestParseServiceHealth(t *testing.T) {
+	resp := newTestResultSet(t,
+		[]string{"Host", "Port", "Status", "Leader count", "Leader distribution", "Partition distribution", "Version"},
+		[]interface{}{"storaged0", 9779, "ONLINE", 8, "basketballplayer:5, test:3", "basketballplayer:10, test:5", "3.1.0"},
+		[]interface{}{"storaged1", 9779, "ONLINE", 2, "basketballplayer:2", "basketballplayer:10, test:5", "3.1.0"},
+		[]interface{}{"storaged2", 9779, "OFFLINE", 0, "No valid partition", "No valid partition", "3.1.0"})
+	storage, err := parseServiceHealth(resp)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, storage.Online)
+	assert.Equal(t, 1, storage.Offline)
+	assert.Equal(t, HostAddress{Host: "storaged0", Port: 9779}, storage.Hosts[0].Host)
+	assert.Equal(t, "3.1.0", storage.Hosts[0].Version)
+	assert.Equal(t, map[string]int64{"basketballplayer": 5, "test": 3}, storage.Hosts[0].Leaders)
+	assert.Empty(t, storage.Hosts[2].Partitions)
+
+	resp = newTestResultSet(t, []string{"Host", "Port", "Status", "Git Info Sha", "Version"},
+		[]interface{}{"graphd", 9669, "ONLINE", "2e5a17a", "3.1.0"})
+	graph, err := parseServiceHealth(resp)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, graph.Online)
+
+	health := ClusterHealth{
+		Graph:              graph,
+		Meta:               graph,
+		Storage:            storage,
+		LeaderImbalance:    imbalance(storage.Hosts, func(h HostStatus) map[string]int64 { return h.Leaders }),
+		PartitionImbalance: imbalance(storage.Hosts, func(h HostStatus) map[string]int64 { return h.Partitions }),
+	}
+	assert.Equal(t, map[string]int64{"basketballplayer": 3, "test": 3}, health.LeaderImbalance)
+	assert.Equal(t, map[string]int64{"basketballplayer": 0, "test": 0}, health.PartitionImbalance)
+	assert.False(t, health.Healthy())
+	assert.False(t, health.Balanced())
+}
+
+func TestServerLimits(t *testing.T) {
+	resp := newTestResultSet(t, []string{"module", "name", "type", "mode", "value"},
+		[]interface{}{"GRAPH", "max_sessions_per_ip_per_user", "int", "MUTABLE", 300},
+		[]interface{}{"GRAPH", "max_allowed_connections", "int", "MUTABLE", 100},
+		[]interface{}{"GRAPH", "v", "int", "MUTABLE", 0})
+	configs, err := parseServiceConfigs(resp)
+	assert.Nil(t, err)
+	limits := serverLimits(configs)
+	assert.Equal(t, ServerLimits{MaxSessionsPerIPPerUser: 300, MaxAllowedConnections: 100}, limits)
+	assert.Equal(t, 100, limits.MaxPoolSize())
+	assert.Equal(t, 300, ServerLimits{MaxSessionsPerIPPerUser: 300}.MaxPoolSize())
+	assert.Equal(t, 0, ServerLimits{}.MaxPoolSize())
+}
+
+func TestCapPoolSize(t *testing.T) {
+	host := HostAddress{Host: "10.0.0.1", Port: 9669}
+	pool := &ConnectionPool{addresses: []HostAddress{host}, conf: PoolConfig{MaxConnPoolSize: 10, MinConnPoolSize: 4}}
+	for i := 0; i < 3; i++ {
+		pool.idleConnectionQueue.PushBack(newDrainTestConn(host))
+	}
+	pool.activeConnectionQueue.PushBack(newDrainTestConn(host))
+
+	pool.capPoolSize(2)
+	assert.Equal(t, 2, pool.conf.MaxConnPoolSize)
+	assert.Equal(t, 2, pool.conf.MinConnPoolSize)
+	assert.Equal(t, 1, pool.idleConnectionQueue.Len())
+	assert.Equal(t, 1, pool.activeConnectionQueue.Len())
+}
+
+func TestIsTransientDDLError(t *testing.T) {
+	for _, c := range []struct {
+		code     nebula.ErrorCode
+		msg      string
+		expected bool
+	}{
+		{nebula.ErrorCode_E_LEADER_CHANGED, "", true},
+		{nebula.ErrorCode_E_BALANCER_RUNNING, "", true},
+		{nebula.ErrorCode_E_EXECUTION_ERROR, "Leader changed!", true},
+		{nebula.ErrorCode_E_EXECUTION_ERROR, "Create tag failed: E_BALANCER_RUNNING", true},
+		{nebula.ErrorCode_E_EXECUTION_ERROR, "Existed!", false},
+		{nebula.ErrorCode_E_SYNTAX_ERROR, "syntax error near `TAG'", false},
+	} {
+		resp, err := genResultSet(&graph.ExecutionResponse{ErrorCode: c.code, ErrorMsg: []byte(c.msg)}, testTimezone)
+		assert.Nil(t, err)
+		assert.Equal(t, c.expected, isTransientDDLError(resp), c.msg)
+	}
+}
@@ -0,0 +1,198 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"fmt"
+	"time"
+)
+
+// AutoscaleConfig is the config of the autoscaling of a pool, whose capacity grows from MinConnPoolSize
+// up to MaxConnPoolSize while it is under pressure and shrinks back while it is underused.
+// The capacity changes only after the same decision has been taken for Stable consecutive evaluations,
+// and the thresholds of the utilization are apart, so that the capacity does not flap.
+// The zero values are replaced by the defaults of DefaultAutoscaleConfig.
+type AutoscaleConfig struct {
+	// The interval between the evaluations
+	Interval time.Duration
+	// The pool is under pressure above this ratio of active connections to capacity, e.g. 0.8
+	ScaleUpUtilization float64
+	// The pool is underused below this ratio of active connections to capacity, e.g. 0.3
+	ScaleDownUtilization float64
+	// The pool is under pressure if an acquire waited longer since the last evaluation
+	ScaleUpWait time.Duration
+	// The number of consecutive evaluations required to change the capacity
+	Stable int
+}
+
+// DefaultAutoscaleConfig returns the default autoscaling config
+func DefaultAutoscaleConfig() AutoscaleConfig {
+	return AutoscaleConfig{
+		Interval:             10 * time.Second,
+		ScaleUpUtilization:   0.8,
+		ScaleDownUtilization: 0.3,
+		ScaleUpWait:          10 * time.Millisecond,
+		Stable:               3,
+	}
+}
+
+// WithAutoscale enables the autoscaling of the capacity of the pool between MinConnPoolSize and MaxConnPoolSize
+func WithAutoscale(autoscale AutoscaleConfig) PoolConfOption {
+	return func(conf *PoolConfig) {
+		conf.Autoscale = &autoscale
+	}
+}
+
+// withDefaults replaces the zero values by the defaults
+func (c AutoscaleConfig) withDefaults() AutoscaleConfig {
+	d := DefaultAutoscaleConfig()
+	if c.Interval <= 0 {
+		c.Interval = d.Interval
+	}
+	if c.ScaleUpUtilization <= 0 {
+		c.ScaleUpUtilization = d.ScaleUpUtilization
+	}
+	if c.ScaleDownUtilization <= 0 {
+		c.ScaleDownUtilization = d.ScaleDownUtilization
+	}
+	if c.ScaleUpWait <= 0 {
+		c.ScaleUpWait = d.ScaleUpWait
+	}
+	if c.Stable <= 0 {
+		c.Stable = d.Stable
+	}
+	return c
+}
+
+// autoscaler is the state of the autoscaling of a pool, protected by the lock of the pool
+type autoscaler struct {
+	conf     AutoscaleConfig
+	capacity int
+	upStreak int
+	// the number of consecutive underused evaluations
+	downStreak int
+	stopCh     chan struct{}
+	stopped    bool
+}
+
+// capacityLocked returns the max number of connections, the caller must hold the lock
+func (pool *ConnectionPool) capacityLocked() int {
+	if pool.autoscaler != nil {
+		return pool.autoscaler.capacity
+	}
+	return pool.conf.MaxConnPoolSize
+}
+
+// minCapacity returns the capacity of an idle pool
+func (pool *ConnectionPool) minCapacity() int {
+	if pool.conf.MinConnPoolSize > 1 {
+		return pool.conf.MinConnPoolSize
+	}
+	return 1
+}
+
+// startAutoscaler starts the evaluation of the capacity if autoscaling is enabled
+func (pool *ConnectionPool) startAutoscaler() {
+	if pool.conf.Autoscale == nil {
+		return
+	}
+	a := &autoscaler{
+		conf:     pool.conf.Autoscale.withDefaults(),
+		capacity: pool.minCapacity(),
+		stopCh:   make(chan struct{}),
+	}
+	pool.rwLock.Lock()
+	pool.autoscaler = a
+	pool.rwLock.Unlock()
+	stopCh := a.stopCh
+	go func() {
+		t := pool.conf.Clock.NewTimer(a.conf.Interval)
+		defer t.Stop()
+		for {
+			select {
+			case <-t.C():
+			case <-stopCh:
+				return
+			}
+			pool.autoscale()
+			t.Reset(a.conf.Interval)
+		}
+	}()
+}
+
+// stopAutoscalerLocked stops the evaluation of the capacity, the caller must hold the lock
+func (pool *ConnectionPool) stopAutoscalerLocked() {
+	if pool.autoscaler != nil && !pool.autoscaler.stopped {
+		close(pool.autoscaler.stopCh)
+		pool.autoscaler.stopped = true
+	}
+}
+
+// autoscale evaluates the pressure on the pool and changes its capacity once the decision is stable
+func (pool *ConnectionPool) autoscale() {
+	maxWait, failures := pool.acquireStats.resetWindow()
+	waiting, _ := pool.acquireStats.snapshot()
+
+	pool.rwLock.Lock()
+	a := pool.autoscaler
+	if pool.closed || a == nil {
+		pool.rwLock.Unlock()
+		return
+	}
+	utilization := float64(pool.activeConnectionQueue.Len()) / float64(a.capacity)
+	waited := maxWait >= a.conf.ScaleUpWait || failures > 0 || waiting > 0
+	switch {
+	case utilization >= a.conf.ScaleUpUtilization || waited:
+		a.upStreak++
+		a.downStreak = 0
+	case utilization <= a.conf.ScaleDownUtilization:
+		a.downStreak++
+		a.upStreak = 0
+	default:
+		a.upStreak, a.downStreak = 0, 0
+	}
+
+	var closing []*connection
+	switch {
+	case a.upStreak >= a.conf.Stable && a.capacity < pool.conf.MaxConnPoolSize:
+		a.upStreak = 0
+		a.capacity += maxInt(1, a.capacity/2)
+		if a.capacity > pool.conf.MaxConnPoolSize {
+			a.capacity = pool.conf.MaxConnPoolSize
+		}
+		pool.log.Info(fmt.Sprintf("Autoscaling: the capacity of the pool grows to %d", a.capacity))
+		// wake up the callers waiting for a free connection
+		if pool.releasedCh != nil {
+			close(pool.releasedCh)
+			pool.releasedCh = nil
+		}
+	case a.downStreak >= a.conf.Stable && a.capacity > pool.minCapacity():
+		a.downStreak = 0
+		a.capacity -= maxInt(1, a.capacity/4)
+		if a.capacity < pool.minCapacity() {
+			a.capacity = pool.minCapacity()
+		}
+		pool.log.Info(fmt.Sprintf("Autoscaling: the capacity of the pool shrinks to %d", a.capacity))
+		for pool.idleConnectionQueue.Len() > 0 &&
+			pool.idleConnectionQueue.Len()+pool.activeConnectionQueue.Len() > a.capacity {
+			closing = append(closing, pool.idleConnectionQueue.Remove(pool.idleConnectionQueue.Front()).(*connection))
+		}
+	}
+	pool.rwLock.Unlock()
+	for _, c := range closing {
+		c.close()
+	}
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,61 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAutoscale(t *testing.T) {
+	// nothing listens on port 1
+	conf := NewPoolConf(WithLazyInit(), WithAutoscale(AutoscaleConfig{Interval: time.Hour, Stable: 2}))
+	conf.MinConnPoolSize = 2
+	conf.MaxConnPoolSize = 4
+	pool, err := NewConnectionPool([]HostAddress{{"127.0.0.1", 1}}, conf, DefaultLogger{})
+	assert.Nil(t, err)
+	defer pool.Close()
+	assert.Equal(t, 2, pool.Stats().Capacity)
+
+	// fully utilized, the capacity grows after Stable evaluations
+	pool.activeConnectionQueue.PushBack(&connection{})
+	pool.activeConnectionQueue.PushBack(&connection{})
+	pool.autoscale()
+	assert.Equal(t, 2, pool.Stats().Capacity)
+	pool.autoscale()
+	assert.Equal(t, 3, pool.Stats().Capacity)
+
+	// 2 of 3 is between the thresholds, the streak is reset
+	pool.autoscale()
+	pool.acquireStats.startWait("test")
+	pool.acquireStats.endWait("test", time.Time{}, 20*time.Millisecond, true, false)
+	pool.autoscale()
+	assert.Equal(t, 3, pool.Stats().Capacity)
+	pool.acquireStats.startWait("test")
+	pool.acquireStats.endWait("test", time.Time{}, 0, false, true)
+	pool.autoscale()
+	assert.Equal(t, 4, pool.Stats().Capacity)
+	pool.autoscale()
+	pool.autoscale()
+	assert.Equal(t, 4, pool.Stats().Capacity)
+
+	// idle, the capacity shrinks down to MinConnPoolSize
+	pool.activeConnectionQueue.Init()
+	for i := 0; i < 6; i++ {
+		pool.autoscale()
+	}
+	assert.Equal(t, 2, pool.Stats().Capacity)
+
+	conf.Autoscale = &AutoscaleConfig{ScaleUpUtilization: 0.2}
+	assert.NotNil(t, conf.Validate())
+	conf.Autoscale = &AutoscaleConfig{}
+	assert.Nil(t, conf.Validate())
+}
@@ -0,0 +1,134 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Statement is a statement of ExecuteBatch and its parameters
+type Statement struct {
+	Stmt   string
+	Params map[string]interface{}
+}
+
+// Result is the outcome of a statement of ExecuteBatch
+type Result struct {
+	// The result of the statement, it is set with Err if the statement failed on the graph service
+	ResultSet *ResultSet
+	// The error of the statement, or of the acquire of its session
+	Err error
+}
+
+// WithBatchParallelism sets the max number of sessions used at once by ExecuteBatch,
+// 0 value means half of MaxConnPoolSize, so that a batch leaves connections to the other callers
+func WithBatchParallelism(n int) PoolConfOption {
+	return func(conf *PoolConfig) {
+		conf.BatchParallelism = n
+	}
+}
+
+// ExecuteBatch executes the statements concurrently on sessions acquired from the pool,
+// at most BatchParallelism or half of MaxConnPoolSize at once, and returns their results in the order of the statements.
+// A statement which fails, including on the graph service, does not stop the others:
+// its error is reported in its result. The statements not started when the context is done fail with its error.
+// In dry-run mode, see WithDryRun, the statements are rendered in their order and no session is acquired.
+func (pool *ConnectionPool) ExecuteBatch(ctx context.Context, stmts []Statement) []Result {
+	if hook := pool.dryRunHook(ctx); hook != nil {
+		// a single worker renders the statements in their order
+		return executeBatch(ctx, stmts, 1, func(ctx context.Context) (Executor, func(), error) {
+			return dryRunExecutor{hook: hook}, func() {}, nil
+		})
+	}
+	parallelism := pool.conf.BatchParallelism
+	if parallelism == 0 {
+		parallelism = defaultBatchParallelism(pool.conf.MaxConnPoolSize)
+	}
+	return executeBatch(ctx, stmts, parallelism, func(ctx context.Context) (Executor, func(), error) {
+		session, err := pool.Acquire(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		return session, session.Release, nil
+	})
+}
+
+// defaultBatchParallelism returns half of the pool size, at least 1
+func defaultBatchParallelism(maxConnPoolSize int) int {
+	if maxConnPoolSize < 2 {
+		return 1
+	}
+	return maxConnPoolSize / 2
+}
+
+// executeBatch runs the statements on the given number of workers, each one executing on its own executor
+func executeBatch(ctx context.Context, stmts []Statement, workers int,
+	acquire func(ctx context.Context) (Executor, func(), error)) []Result {
+	results := make([]Result, len(stmts))
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > len(stmts) {
+		workers = len(stmts)
+	}
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var executor Executor
+			var release func()
+			defer func() {
+				if release != nil {
+					release()
+				}
+			}()
+			for i := range next {
+				if executor == nil {
+					var err error
+					if executor, release, err = acquire(ctx); err != nil {
+						results[i].Err = err
+						continue
+					}
+				}
+				results[i] = executeBatchStatement(ctx, executor, stmts[i])
+			}
+		}()
+	}
+	i := 0
+dispatch:
+	// a done context is checked first, as select picks one of several ready cases at random
+	for ; i < len(stmts) && ctx.Err() == nil; i++ {
+		select {
+		case next <- i:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(next)
+	wg.Wait()
+	for ; i < len(stmts); i++ {
+		results[i].Err = fmt.Errorf("failed to execute %s: %s", stmts[i].Stmt, ctx.Err().Error())
+	}
+	return results
+}
+
+func executeBatchStatement(ctx context.Context, executor Executor, stmt Statement) Result {
+	resp, err := executor.ExecuteWithContext(ctx, stmt.Stmt, stmt.Params)
+	if err != nil {
+		return Result{Err: err}
+	}
+	if !resp.IsSucceed() {
+		return Result{ResultSet: resp, Err: fmt.Errorf("failed to execute %s: %s", stmt.Stmt, resp.GetErrorMsg())}
+	}
+	return Result{ResultSet: resp}
+}
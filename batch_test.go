@@ -0,0 +1,103 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vesoft-inc/nebula-go/v3/nebula"
+)
+
+type fakeBatchExecutor struct {
+	t *testing.T
+}
+
+func (e fakeBatchExecutor) Execute(stmt string, opts ...ExecOption) (*ResultSet, error) {
+	return e.ExecuteWithContext(context.Background(), stmt, nil)
+}
+
+func (e fakeBatchExecutor) ExecuteWithParameter(stmt string, params map[string]interface{}, opts ...ExecOption) (*ResultSet, error) {
+	return e.ExecuteWithContext(context.Background(), stmt, params)
+}
+
+func (e fakeBatchExecutor) ExecuteWithContext(ctx context.Context, stmt string, params map[string]interface{},
+	opts ...ExecOption) (*ResultSet, error) {
+	switch stmt {
+	case "broken":
+		return nil, fmt.Errorf("connection closed")
+	case "invalid":
+		resp := newTestResultSet(e.t, nil)
+		resp.resp.ErrorCode = nebula.ErrorCode_E_SYNTAX_ERROR
+		resp.resp.ErrorMsg = []byte("syntax error")
+		return resp, nil
+	}
+	return newTestResultSet(e.t, []string{"stmt"}), nil
+}
+
+func TestExecuteBatch(t *testing.T) {
+	var acquired, released int32
+	var mu sync.Mutex
+	acquire := func(ctx context.Context) (Executor, func(), error) {
+		atomic.AddInt32(&acquired, 1)
+		return fakeBatchExecutor{t: t}, func() {
+			mu.Lock()
+			released++
+			mu.Unlock()
+		}, nil
+	}
+	results := executeBatch(context.Background(), []Statement{
+		{Stmt: "ok"}, {Stmt: "broken"}, {Stmt: "invalid"}, {Stmt: "ok"}, {Stmt: "ok"},
+	}, 2, acquire)
+
+	if assert.Len(t, results, 5) {
+		assert.Nil(t, results[0].Err)
+		assert.Equal(t, []string{"stmt"}, results[0].ResultSet.GetColNames())
+		assert.EqualError(t, results[1].Err, "connection closed")
+		assert.Nil(t, results[1].ResultSet)
+		assert.EqualError(t, results[2].Err, "failed to execute invalid: syntax error")
+		assert.NotNil(t, results[2].ResultSet)
+		assert.Nil(t, results[4].Err)
+	}
+	assert.True(t, acquired <= 2)
+	assert.Equal(t, acquired, released)
+}
+
+func TestExecuteBatchAcquireFailure(t *testing.T) {
+	acquire := func(ctx context.Context) (Executor, func(), error) {
+		return nil, nil, fmt.Errorf("pool closed")
+	}
+	results := executeBatch(context.Background(), []Statement{{Stmt: "ok"}, {Stmt: "ok"}}, 1, acquire)
+	for _, r := range results {
+		assert.EqualError(t, r.Err, "pool closed")
+	}
+}
+
+func TestExecuteBatchCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	acquire := func(ctx context.Context) (Executor, func(), error) {
+		return fakeBatchExecutor{t: t}, func() {}, nil
+	}
+	results := executeBatch(ctx, []Statement{{Stmt: "ok"}, {Stmt: "ok"}}, 2, acquire)
+	for _, r := range results {
+		assert.EqualError(t, r.Err, "failed to execute ok: context canceled")
+	}
+}
+
+func TestDefaultBatchParallelism(t *testing.T) {
+	assert.Equal(t, 1, defaultBatchParallelism(1))
+	assert.Equal(t, 1, defaultBatchParallelism(2))
+	assert.Equal(t, 5, defaultBatchParallelism(10))
+	assert.Equal(t, 5, defaultBatchParallelism(11))
+}
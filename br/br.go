@@ -0,0 +1,198 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+// Package br orchestrates backups and restores of a cluster with the Nebula BR tool.
+//
+// Backups and restores can not be driven with nGQL statements: they copy the data files of the
+// storage and meta hosts, which is done by the br binary. The client runs the binary, reports
+// its output as progress and parses the result into typed values.
+package br
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Client runs the br binary against a cluster
+type Client struct {
+	// The path of the br binary, "br" is looked up in the PATH if empty
+	Binary string
+	// The address of a meta service of the cluster, e.g. "192.168.8.129:9559"
+	MetaAddr string
+	// The storage of the backups, e.g. "local:///home/nebula/backup" or "s3://bucket/path"
+	Storage string
+	// Extra flags passed to every command, e.g. the credentials of the storage
+	ExtraArgs []string
+}
+
+// Progress is a line of the output of the br binary
+type Progress struct {
+	// The command being run, e.g. "backup"
+	Command string
+	Line    string
+}
+
+// ProgressFunc is called with each line of the output of the br binary
+type ProgressFunc func(Progress)
+
+// BackupOptions are the options of a backup
+type BackupOptions struct {
+	// The spaces to back up, all the spaces are backed up if empty
+	Spaces []string
+}
+
+// BackupInfo is a backup of the storage, as shown by br show
+type BackupInfo struct {
+	Name       string
+	CreateTime time.Time
+	Spaces     []string
+	Full       bool
+	AllSpaces  bool
+}
+
+var backupNameRegex = regexp.MustCompile(`BACKUP_\d{4}_\d{2}_\d{2}_\d{2}_\d{2}_\d{2}`)
+
+// Backup takes a full backup of the cluster and returns its name
+func (c *Client) Backup(ctx context.Context, opts BackupOptions, progress ProgressFunc) (string, error) {
+	args := []string{"backup", "full"}
+	if len(opts.Spaces) > 0 {
+		args = append(args, "--spaces", strings.Join(opts.Spaces, ","))
+	}
+	out, err := c.run(ctx, "backup", c.withCluster(args), progress)
+	if err != nil {
+		return "", err
+	}
+	names := backupNameRegex.FindAllString(out, -1)
+	if len(names) == 0 {
+		return "", fmt.Errorf("failed to backup: the name of the backup was not found in the output of br")
+	}
+	return names[len(names)-1], nil
+}
+
+// Restore restores the cluster from the named backup
+func (c *Client) Restore(ctx context.Context, name string, progress ProgressFunc) error {
+	_, err := c.run(ctx, "restore", c.withCluster([]string{"restore", "full", "--name", name}), progress)
+	return err
+}
+
+// ListBackups returns the backups of the storage
+func (c *Client) ListBackups(ctx context.Context) ([]BackupInfo, error) {
+	out, err := c.run(ctx, "show", append([]string{"show", "--storage", c.Storage}, c.ExtraArgs...), nil)
+	if err != nil {
+		return nil, err
+	}
+	return parseBackups(out)
+}
+
+// Cleanup removes the temporary files left in the cluster by a failed backup
+func (c *Client) Cleanup(ctx context.Context, name string, progress ProgressFunc) error {
+	_, err := c.run(ctx, "cleanup", append([]string{"cleanup", "--meta", c.MetaAddr, "--name", name}, c.ExtraArgs...), progress)
+	return err
+}
+
+func (c *Client) withCluster(args []string) []string {
+	args = append(args, "--meta", c.MetaAddr, "--storage", c.Storage)
+	return append(args, c.ExtraArgs...)
+}
+
+// run runs the br binary, passes each line of its output to progress and returns the whole output
+func (c *Client) run(ctx context.Context, command string, args []string, progress ProgressFunc) (string, error) {
+	binary := c.Binary
+	if binary == "" {
+		binary = "br"
+	}
+	cmd := exec.CommandContext(ctx, binary, args...)
+	pr, pw := io.Pipe()
+	cmd.Stdout = pw
+	cmd.Stderr = pw
+
+	var out bytes.Buffer
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		scanner := bufio.NewScanner(pr)
+		for scanner.Scan() {
+			line := scanner.Text()
+			out.WriteString(line)
+			out.WriteByte('\n')
+			if progress != nil {
+				progress(Progress{Command: command, Line: line})
+			}
+		}
+		// drain the output if a line is too long for the scanner
+		io.Copy(&out, pr)
+	}()
+
+	err := cmd.Run()
+	pw.Close()
+	wg.Wait()
+	if err != nil {
+		if ctx.Err() != nil {
+			return out.String(), ctx.Err()
+		}
+		return out.String(), fmt.Errorf("failed to run br %s: %s: %s", command, err.Error(), lastLine(out.String()))
+	}
+	return out.String(), nil
+}
+
+func lastLine(s string) string {
+	s = strings.TrimSpace(s)
+	if i := strings.LastIndexByte(s, '\n'); i >= 0 {
+		return s[i+1:]
+	}
+	return s
+}
+
+// parseBackups parses the table printed by br show
+func parseBackups(out string) ([]BackupInfo, error) {
+	var backups []BackupInfo
+	header := true
+	for _, line := range strings.Split(out, "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "|") {
+			continue
+		}
+		cells := strings.Split(strings.Trim(line, "|"), "|")
+		for i := range cells {
+			cells[i] = strings.TrimSpace(cells[i])
+		}
+		if header {
+			header = false
+			continue
+		}
+		if len(cells) < 5 {
+			return nil, fmt.Errorf("failed to parse backups: unexpected row %s", line)
+		}
+		created, err := time.Parse("2006-01-02 15:04:05", cells[1])
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse backups: %s", err.Error())
+		}
+		info := BackupInfo{
+			Name:       cells[0],
+			CreateTime: created,
+			Full:       cells[3] == "true",
+			AllSpaces:  cells[4] == "true",
+		}
+		for _, space := range strings.Split(cells[2], ",") {
+			if space = strings.TrimSpace(space); space != "" {
+				info.Spaces = append(info.Spaces, space)
+			}
+		}
+		backups = append(backups, info)
+	}
+	return backups, nil
+}
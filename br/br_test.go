@@ -0,0 +1,76 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package br
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseBackups(t *testing.T) {
+	out := `+----------------------------+---------------------+------------------------+-------------+------------+
+|            NAME            |     CREATE TIME     |         SPACES         | FULL BACKUP | ALL SPACES |
++----------------------------+---------------------+------------------------+-------------+------------+
+| BACKUP_2022_02_10_07_40_41 | 2022-02-10 07:40:41 | basketballplayer       | true        | true       |
+| BACKUP_2022_02_11_08_18_42 | 2022-02-11 08:18:42 | basketballplayer,test  | true        | false      |
++----------------------------+---------------------+------------------------+-------------+------------+
+`
+	backups, err := parseBackups(out)
+	assert.Nil(t, err)
+	assert.Equal(t, []BackupInfo{
+		{
+			Name:       "BACKUP_2022_02_10_07_40_41",
+			CreateTime: time.Date(2022, 2, 10, 7, 40, 41, 0, time.UTC),
+			Spaces:     []string{"basketballplayer"},
+			Full:       true,
+			AllSpaces:  true,
+		},
+		{
+			Name:       "BACKUP_2022_02_11_08_18_42",
+			CreateTime: time.Date(2022, 2, 11, 8, 18, 42, 0, time.UTC),
+			Spaces:     []string{"basketballplayer", "test"},
+			Full:       true,
+		},
+	}, backups)
+}
+
+func TestBackup(t *testing.T) {
+	dir, err := ioutil.TempDir("", "br")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	// a fake br binary printing its arguments and a backup name
+	binary := filepath.Join(dir, "br")
+	script := "#!/bin/sh\necho \"$@\"\necho 'backup BACKUP_2022_02_10_07_40_41 succeeded'\n"
+	assert.Nil(t, ioutil.WriteFile(binary, []byte(script), 0700))
+
+	c := &Client{Binary: binary, MetaAddr: "127.0.0.1:9559", Storage: "local:///tmp/backup"}
+	var lines []string
+	name, err := c.Backup(context.Background(), BackupOptions{Spaces: []string{"a", "b"}}, func(p Progress) {
+		assert.Equal(t, "backup", p.Command)
+		lines = append(lines, p.Line)
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "BACKUP_2022_02_10_07_40_41", name)
+	assert.Equal(t, []string{
+		"backup full --spaces a,b --meta 127.0.0.1:9559 --storage local:///tmp/backup",
+		"backup BACKUP_2022_02_10_07_40_41 succeeded",
+	}, lines)
+
+	script = "#!/bin/sh\necho 'failed to connect to meta'\nexit 1\n"
+	assert.Nil(t, ioutil.WriteFile(binary, []byte(script), 0700))
+	err = c.Restore(context.Background(), name, nil)
+	assert.EqualError(t, err, "failed to run br restore: exit status 1: failed to connect to meta")
+}
@@ -0,0 +1,100 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// WithPinnedCertificates pins the certificates of the graph services: the TLS handshake fails unless the SHA-256
+// of the leaf certificate, or of its subject public key info, is one of the pins. The pins are checked
+// in addition to the verification of the TLS config, and even if it skips the verification of the CA chain.
+// A pin is the hex encoding of the hash, optionally with colons, or its base64 encoding prefixed with "sha256/",
+// e.g. the output of
+//
+//	openssl x509 -in cert.pem -pubkey -noout | openssl pkey -pubin -outform der | openssl dgst -sha256 -binary | base64
+func WithPinnedCertificates(sha256 ...string) PoolConfOption {
+	return func(conf *PoolConfig) {
+		conf.PinnedCertificates = append(conf.PinnedCertificates, sha256...)
+	}
+}
+
+// parsePin decodes a pin of WithPinnedCertificates
+func parsePin(pin string) ([]byte, error) {
+	var hash []byte
+	var err error
+	if strings.HasPrefix(pin, "sha256/") {
+		hash, err = base64.StdEncoding.DecodeString(strings.TrimPrefix(pin, "sha256/"))
+	} else {
+		hash, err = hex.DecodeString(strings.Replace(pin, ":", "", -1))
+	}
+	if err != nil || len(hash) != sha256.Size {
+		return nil, fmt.Errorf("invalid pinned certificate %q: not a SHA-256 hash", pin)
+	}
+	return hash, nil
+}
+
+// withPinnedCertificates returns a copy of the config verifying the pins, or the config if there is no pin.
+// It fails if there are pins and TLS is disabled, i.e. the config is nil.
+func withPinnedCertificates(config *tls.Config, pins []string) (*tls.Config, error) {
+	if len(pins) == 0 {
+		return config, nil
+	}
+	if config == nil {
+		return nil, fmt.Errorf("pinned certificates require TLS")
+	}
+	hashes := make([][]byte, len(pins))
+	for i, pin := range pins {
+		hash, err := parsePin(pin)
+		if err != nil {
+			return nil, err
+		}
+		hashes[i] = hash
+	}
+	config = config.Clone()
+	// VerifyPeerCertificate is not called on resumed sessions, which must not bypass the pins
+	config.SessionTicketsDisabled = true
+	config.ClientSessionCache = nil
+	verify := config.VerifyPeerCertificate
+	config.VerifyPeerCertificate = func(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error {
+		if verify != nil {
+			if err := verify(rawCerts, verifiedChains); err != nil {
+				return err
+			}
+		}
+		return verifyPins(rawCerts, hashes)
+	}
+	return config, nil
+}
+
+// verifyPins checks the hashes of the leaf certificate against the pins
+func verifyPins(rawCerts [][]byte, pins [][]byte) error {
+	if len(rawCerts) == 0 {
+		return fmt.Errorf("failed to verify pinned certificates: no certificate")
+	}
+	leaf, err := x509.ParseCertificate(rawCerts[0])
+	if err != nil {
+		return fmt.Errorf("failed to verify pinned certificates: %s", err.Error())
+	}
+	certHash := sha256.Sum256(leaf.Raw)
+	spkiHash := sha256.Sum256(leaf.RawSubjectPublicKeyInfo)
+	for _, pin := range pins {
+		if bytes.Equal(pin, certHash[:]) || bytes.Equal(pin, spkiHash[:]) {
+			return nil
+		}
+	}
+	return fmt.Errorf("failed to verify pinned certificates: the certificate of %s matches no pin", leaf.Subject)
+}
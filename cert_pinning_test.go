@@ -0,0 +1,92 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPinnedCertificates(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "graphd"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.Nil(t, err)
+	cert, err := x509.ParseCertificate(der)
+	assert.Nil(t, err)
+	certHash := sha256.Sum256(der)
+	spkiHash := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	otherHash := sha256.Sum256([]byte("other"))
+
+	for _, pins := range [][]string{
+		{hex.EncodeToString(certHash[:])},
+		{hex.EncodeToString(otherHash[:]), "sha256/" + base64.StdEncoding.EncodeToString(spkiHash[:])},
+	} {
+		config, err := withPinnedCertificates(&tls.Config{InsecureSkipVerify: true}, pins)
+		assert.Nil(t, err)
+		assert.Nil(t, config.VerifyPeerCertificate([][]byte{der}, nil))
+	}
+
+	colons := ""
+	for i, b := range otherHash {
+		if i > 0 {
+			colons += ":"
+		}
+		colons += fmt.Sprintf("%02X", b)
+	}
+	config, err := withPinnedCertificates(&tls.Config{}, []string{colons})
+	assert.Nil(t, err)
+	err = config.VerifyPeerCertificate([][]byte{der}, nil)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "matches no pin")
+	}
+	assert.NotNil(t, config.VerifyPeerCertificate(nil, nil))
+
+	_, err = withPinnedCertificates(&tls.Config{}, []string{"abcd"})
+	assert.NotNil(t, err)
+	_, err = withPinnedCertificates(nil, []string{hex.EncodeToString(certHash[:])})
+	assert.EqualError(t, err, "pinned certificates require TLS")
+	plain, err := withPinnedCertificates(nil, nil)
+	assert.Nil(t, err)
+	assert.Nil(t, plain)
+	assert.True(t, config.SessionTicketsDisabled)
+
+	pool := &ConnectionPool{conf: NewPoolConf(WithPinnedCertificates(hex.EncodeToString(certHash[:])))}
+	assert.EqualError(t, pool.prepareTLS(), "failed to prepare the TLS config of the pool: pinned certificates require TLS")
+	direct := HostAddress{Host: "graphd", Port: 9669}
+	pool = &ConnectionPool{
+		conf:      NewPoolConf(WithPinnedCertificates(hex.EncodeToString(certHash[:])), WithHostTLS(direct, nil)),
+		sslConfig: &tls.Config{},
+	}
+	pool.hostTLS = resolveHostTLS(pool.conf.HostTLS, []HostAddress{direct}, []HostAddress{direct})
+	assert.EqualError(t, pool.prepareTLS(),
+		"failed to prepare the TLS config of host graphd:9669: pinned certificates require TLS")
+	assert.EqualError(t, pool.conf.Validate(),
+		"invalid config, 1 problem(s): pinned certificates require TLS, which HostTLS disables for host graphd:9669")
+	assert.NotNil(t, NewPoolConf(WithPinnedCertificates("sha256/abc")).Validate())
+}
@@ -0,0 +1,59 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import "strings"
+
+// clientComment returns the comment identifying the client prepended to every statement,
+// e.g. "/* client_name=myservice client_version=1.2.3 */ ", or an empty string if the client has no identity.
+// The comment is visible in SHOW QUERIES and in the slow query logs of the graph service.
+func clientComment(name, version string) string {
+	var parts []string
+	if name = sanitizeComment(name); name != "" {
+		parts = append(parts, "client_name="+name)
+	}
+	if version = sanitizeComment(version); version != "" {
+		parts = append(parts, "client_version="+version)
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return "/* " + strings.Join(parts, " ") + " */ "
+}
+
+// sanitizeComment removes the spaces and the characters which could end the comment or the statement
+func sanitizeComment(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r <= ' ' || r == '*' || r == ';' {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// clientLogger prefixes the messages of a pool with the identity of the client
+type clientLogger struct {
+	Logger
+	prefix string
+}
+
+// clientLoggerFor returns the logger labelling the messages with the identity of the client,
+// e.g. "[client_name=myservice client_version=1.2.3] ", log itself if the client has no identity
+func clientLoggerFor(log Logger, name, version string) Logger {
+	comment := clientComment(name, version)
+	if comment == "" || log == nil {
+		return log
+	}
+	return clientLogger{Logger: log, prefix: "[" + strings.TrimSuffix(strings.TrimPrefix(comment, "/* "), " */ ") + "] "}
+}
+
+func (l clientLogger) Info(msg string)  { l.Logger.Info(l.prefix + msg) }
+func (l clientLogger) Warn(msg string)  { l.Logger.Warn(l.prefix + msg) }
+func (l clientLogger) Error(msg string) { l.Logger.Error(l.prefix + msg) }
+func (l clientLogger) Fatal(msg string) { l.Logger.Fatal(l.prefix + msg) }
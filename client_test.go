@@ -11,6 +11,7 @@
 package nebula_go
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -676,6 +677,53 @@ func TestMultiThreads(t *testing.T) {
 	assert.Equal(t, 666, pool.getIdleConnCount(), "Total number of idle connections should be 666")
 }
 
+func TestPool_AcquireN(t *testing.T) {
+	hostList := poolAddress
+
+	testPoolConfig := PoolConfig{
+		TimeOut:         0 * time.Millisecond,
+		IdleTime:        0 * time.Millisecond,
+		MaxConnPoolSize: 4,
+		MinConnPoolSize: 1,
+	}
+
+	pool, err := NewConnectionPool(hostList, testPoolConfig, nebulaLog)
+	if err != nil {
+		t.Fatalf("fail to initialize the connection pool, host: %s, port: %d, %s", address, port, err.Error())
+	}
+	defer pool.Close()
+
+	sessions, err := pool.AcquireN(context.Background(), username, password, 3)
+	if err != nil {
+		t.Fatalf("fail to acquire sessions, %s", err.Error())
+	}
+	assert.Equal(t, 3, len(sessions))
+	assert.Equal(t, 3, pool.getActiveConnCount())
+
+	// Only 1 connection left, the acquire must wait until the context is done
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err = pool.AcquireN(ctx, username, password, 2)
+	assert.NotNil(t, err)
+	assert.Equal(t, 3, pool.getActiveConnCount())
+
+	// Releasing a session wakes up the waiting caller
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		sessions[0].Release()
+	}()
+	others, err := pool.AcquireN(context.Background(), username, password, 2)
+	if err != nil {
+		t.Fatalf("fail to acquire sessions, %s", err.Error())
+	}
+	assert.Equal(t, 4, pool.getActiveConnCount())
+
+	for _, s := range append(sessions[1:], others...) {
+		s.Release()
+	}
+	assert.Equal(t, 4, pool.getIdleConnCount())
+}
+
 func TestLoadbalancer(t *testing.T) {
 	hostList := poolAddress
 	var loadPerHost = make(map[HostAddress]int)
@@ -1324,3 +1372,29 @@ func dropSpace(t *testing.T, session *Session, spaceName string) {
 	}
 	checkResultSet(t, query, resultSet)
 }
+
+func TestClient_Query(t *testing.T) {
+	client, err := Open(fmt.Sprintf("nebula://%s:%s@%s:%d", username, password, address, port))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	rs, err := client.Query(context.Background(), "YIELD $n + 1 AS n", map[string]interface{}{"n": 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	record, err := rs.GetRowValuesByIndex(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n, err := record.GetValueByIndex(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, _ := n.AsInt()
+	assert.Equal(t, int64(2), v)
+
+	assert.NotNil(t, client.Exec(context.Background(), "SYNTAX ERROR", nil))
+	assert.Equal(t, 0, client.Pool().getActiveConnCount())
+}
@@ -0,0 +1,174 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Clock is the source of time used by the time based behaviors of the pool,
+// such as the idle connection cleaner and the acquire statistics.
+// It can be replaced with a ManualClock in tests to advance time deterministically.
+type Clock interface {
+	Now() time.Time
+	NewTimer(d time.Duration) Timer
+}
+
+// Timer is the timer created by a Clock
+type Timer interface {
+	C() <-chan time.Time
+	Reset(d time.Duration) bool
+	Stop() bool
+}
+
+// realClock is the Clock backed by the time package
+type realClock struct{}
+
+// RealClock returns the Clock backed by the time package, which is used when no clock is set
+func RealClock() Clock {
+	return realClock{}
+}
+
+// Clock returns the clock of the pool config, e.g. to drive the timers of the helpers built on the pool
+func (pool *ConnectionPool) Clock() Clock {
+	if pool.conf.Clock == nil {
+		return realClock{}
+	}
+	return pool.conf.Clock
+}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) NewTimer(d time.Duration) Timer {
+	return realTimer{time.NewTimer(d)}
+}
+
+type realTimer struct {
+	*time.Timer
+}
+
+func (t realTimer) C() <-chan time.Time {
+	return t.Timer.C
+}
+
+// ManualClock is a Clock which only moves forward when Advance or Set is called
+type ManualClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*manualTimer
+}
+
+// NewManualClock returns a ManualClock starting at the given time
+func NewManualClock(now time.Time) *ManualClock {
+	return &ManualClock{now: now}
+}
+
+// Now returns the current time of the clock
+func (c *ManualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// NewTimer returns a timer which fires once the clock has been advanced by d
+func (c *ManualClock) NewTimer(d time.Duration) Timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t := &manualTimer{clock: c, ch: make(chan time.Time, 1)}
+	t.resetLocked(d)
+	return t
+}
+
+// Advance moves the clock forward by d and fires the timers which expired
+func (c *ManualClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	c.setLocked(c.now.Add(d))
+	c.mu.Unlock()
+}
+
+// Set moves the clock to the given time and fires the timers which expired
+func (c *ManualClock) Set(now time.Time) {
+	c.mu.Lock()
+	c.setLocked(now)
+	c.mu.Unlock()
+}
+
+func (c *ManualClock) setLocked(now time.Time) {
+	c.now = now
+	sort.Slice(c.timers, func(i, j int) bool { return c.timers[i].deadline.Before(c.timers[j].deadline) })
+	var pending []*manualTimer
+	for _, t := range c.timers {
+		if t.deadline.After(now) {
+			pending = append(pending, t)
+			continue
+		}
+		t.active = false
+		select {
+		case t.ch <- now:
+		default:
+		}
+	}
+	c.timers = pending
+}
+
+type manualTimer struct {
+	clock    *ManualClock
+	ch       chan time.Time
+	deadline time.Time
+	active   bool
+}
+
+func (t *manualTimer) C() <-chan time.Time {
+	return t.ch
+}
+
+func (t *manualTimer) Reset(d time.Duration) bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	active := t.stopLocked()
+	t.resetLocked(d)
+	return active
+}
+
+func (t *manualTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	return t.stopLocked()
+}
+
+func (t *manualTimer) resetLocked(d time.Duration) {
+	t.deadline = t.clock.now.Add(d)
+	if d <= 0 {
+		select {
+		case t.ch <- t.clock.now:
+		default:
+		}
+		return
+	}
+	t.active = true
+	t.clock.timers = append(t.clock.timers, t)
+}
+
+func (t *manualTimer) stopLocked() bool {
+	if !t.active {
+		return false
+	}
+	t.active = false
+	for i, timer := range t.clock.timers {
+		if timer == t {
+			t.clock.timers = append(t.clock.timers[:i], t.clock.timers[i+1:]...)
+			break
+		}
+	}
+	return true
+}
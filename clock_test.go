@@ -0,0 +1,66 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManualClock(t *testing.T) {
+	start := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := NewManualClock(start)
+	timer := clock.NewTimer(time.Minute)
+
+	clock.Advance(30 * time.Second)
+	select {
+	case <-timer.C():
+		t.Fatal("timer fired too early")
+	default:
+	}
+
+	clock.Advance(30 * time.Second)
+	select {
+	case now := <-timer.C():
+		assert.Equal(t, start.Add(time.Minute), now)
+	default:
+		t.Fatal("timer did not fire")
+	}
+
+	assert.False(t, timer.Reset(time.Minute))
+	assert.True(t, timer.Stop())
+	clock.Advance(time.Hour)
+	select {
+	case <-timer.C():
+		t.Fatal("stopped timer fired")
+	default:
+	}
+}
+
+func TestTimeoutConnectionList(t *testing.T) {
+	clock := NewManualClock(time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC))
+	conf := NewPoolConf(WithClock(clock))
+	conf.IdleTime = time.Minute
+	conf.validateConf(DefaultLogger{})
+	pool := &ConnectionPool{conf: conf, log: DefaultLogger{}}
+
+	old := newConnectionWithClock(HostAddress{}, clock)
+	clock.Advance(50 * time.Second)
+	recent := newConnectionWithClock(HostAddress{}, clock)
+	pool.idleConnectionQueue.PushBack(old)
+	pool.idleConnectionQueue.PushBack(recent)
+
+	assert.Empty(t, pool.timeoutConnectionList())
+
+	clock.Advance(20 * time.Second)
+	assert.Equal(t, []*connection{old}, pool.timeoutConnectionList())
+	assert.Equal(t, 1, pool.getIdleConnCount())
+}
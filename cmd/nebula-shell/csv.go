@@ -0,0 +1,56 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package main
+
+import (
+	"encoding/csv"
+	"io"
+
+	nebula "github.com/vesoft-inc/nebula-go/v3"
+)
+
+// writeCSV writes the columns and the rows of the result as CSV.
+// Strings are written unquoted, nulls as empty cells and the other values with ValueWrapper.String.
+func writeCSV(w io.Writer, resp *nebula.ResultSet) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(resp.GetColNames()); err != nil {
+		return err
+	}
+	for i := 0; i < resp.GetRowSize(); i++ {
+		record, err := resp.GetRowValuesByIndex(i)
+		if err != nil {
+			return err
+		}
+		row := make([]string, resp.GetColSize())
+		for j := range row {
+			val, err := record.GetValueByIndex(j)
+			if err != nil {
+				return err
+			}
+			row[j] = csvCell(val)
+		}
+		if err = cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func csvCell(val *nebula.ValueWrapper) string {
+	switch {
+	case val.IsNull() || val.IsEmpty():
+		return ""
+	case val.IsString():
+		s, _ := val.AsString()
+		return s
+	default:
+		return val.String()
+	}
+}
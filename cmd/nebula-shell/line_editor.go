@@ -0,0 +1,225 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+// lineReader reads the input of the shell line by line
+type lineReader interface {
+	// readLine prints the prompt and returns the next line, io.EOF once the input is closed
+	readLine(prompt string) (string, error)
+	// addHistory adds a statement to the lines recalled by the up arrow
+	addHistory(line string)
+}
+
+// scannerReader reads the lines of an input which is not a terminal, e.g. a script piped to the shell
+type scannerReader struct {
+	scanner *bufio.Scanner
+	out     io.Writer
+}
+
+func newScannerReader(in io.Reader, out io.Writer) *scannerReader {
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 64<<10), 16<<20)
+	return &scannerReader{scanner: scanner, out: out}
+}
+
+func (r *scannerReader) readLine(prompt string) (string, error) {
+	fmt.Fprint(r.out, prompt)
+	if !r.scanner.Scan() {
+		fmt.Fprintln(r.out)
+		if err := r.scanner.Err(); err != nil {
+			return "", err
+		}
+		return "", io.EOF
+	}
+	return r.scanner.Text(), nil
+}
+
+func (r *scannerReader) addHistory(line string) {}
+
+const (
+	keyCtrlA     = 1
+	keyCtrlB     = 2
+	keyCtrlC     = 3
+	keyCtrlD     = 4
+	keyCtrlE     = 5
+	keyCtrlF     = 6
+	keyCtrlH     = 8
+	keyCtrlK     = 11
+	keyCtrlN     = 14
+	keyCtrlP     = 16
+	keyCtrlU     = 21
+	keyEscape    = 27
+	keyBackspace = 127
+	// not a character, the delete key is reported by an escape sequence
+	keyDelete = -1
+)
+
+// lineEditor reads the lines typed in a terminal, which is switched to raw mode while a line is edited.
+// It supports the arrow keys, home, end, delete and the emacs bindings of readline:
+// ctrl-a, ctrl-e, ctrl-b, ctrl-f, ctrl-k, ctrl-u, ctrl-p and ctrl-n.
+type lineEditor struct {
+	in  *bufio.Reader
+	out io.Writer
+	// raw switches the terminal to raw mode and returns the function restoring its mode
+	raw     func() (func(), error)
+	history []string
+}
+
+func (e *lineEditor) addHistory(line string) {
+	if n := len(e.history); n == 0 || e.history[n-1] != line {
+		e.history = append(e.history, line)
+	}
+}
+
+func (e *lineEditor) readLine(prompt string) (string, error) {
+	restore, err := e.raw()
+	if err != nil {
+		return "", err
+	}
+	defer restore()
+
+	var line, typed []rune
+	pos, recalled := 0, len(e.history)
+	recall := func(i int) {
+		if recalled == len(e.history) {
+			typed = line
+		}
+		recalled = i
+		if i == len(e.history) {
+			line = typed
+		} else {
+			line = []rune(e.history[i])
+		}
+		pos = len(line)
+	}
+	fmt.Fprint(e.out, prompt)
+	for {
+		r, _, err := e.in.ReadRune()
+		if err != nil {
+			return "", err
+		}
+		if r == keyEscape {
+			if r, err = e.readEscape(); err != nil {
+				return "", err
+			}
+		}
+		switch r {
+		case '\r', '\n':
+			fmt.Fprint(e.out, "\r\n")
+			return string(line), nil
+		case keyCtrlC:
+			fmt.Fprint(e.out, "^C\r\n")
+			return "", nil
+		case keyCtrlD:
+			if len(line) == 0 {
+				fmt.Fprint(e.out, "\r\n")
+				return "", io.EOF
+			}
+			line = deleteRune(line, pos)
+		case keyDelete:
+			line = deleteRune(line, pos)
+		case keyBackspace, keyCtrlH:
+			if pos > 0 {
+				pos--
+				line = deleteRune(line, pos)
+			}
+		case keyCtrlA:
+			pos = 0
+		case keyCtrlE:
+			pos = len(line)
+		case keyCtrlB:
+			if pos > 0 {
+				pos--
+			}
+		case keyCtrlF:
+			if pos < len(line) {
+				pos++
+			}
+		case keyCtrlK:
+			line = line[:pos:pos]
+		case keyCtrlU:
+			line, pos = append([]rune(nil), line[pos:]...), 0
+		case keyCtrlP:
+			if recalled > 0 {
+				recall(recalled - 1)
+			}
+		case keyCtrlN:
+			if recalled < len(e.history) {
+				recall(recalled + 1)
+			}
+		default:
+			if r < ' ' {
+				continue
+			}
+			line = append(line[:pos], append([]rune{r}, line[pos:]...)...)
+			pos++
+		}
+		fmt.Fprintf(e.out, "\r%s%s\x1b[K", prompt, string(line))
+		if back := len(line) - pos; back > 0 {
+			fmt.Fprintf(e.out, "\x1b[%dD", back)
+		}
+	}
+}
+
+// deleteRune returns the line without the character at pos, if any
+func deleteRune(line []rune, pos int) []rune {
+	if pos >= len(line) {
+		return line
+	}
+	return append(line[:pos:pos], line[pos+1:]...)
+}
+
+// readEscape reads the rest of an escape sequence and returns the key of the same action,
+// or 0 for the sequences which are not supported
+func (e *lineEditor) readEscape() (rune, error) {
+	r, _, err := e.in.ReadRune()
+	if err != nil || (r != '[' && r != 'O') {
+		return 0, err
+	}
+	var param []rune
+	for {
+		if r, _, err = e.in.ReadRune(); err != nil {
+			return 0, err
+		}
+		if r >= 0x40 && r <= 0x7e {
+			break
+		}
+		param = append(param, r)
+	}
+	switch r {
+	case 'A':
+		return keyCtrlP, nil
+	case 'B':
+		return keyCtrlN, nil
+	case 'C':
+		return keyCtrlF, nil
+	case 'D':
+		return keyCtrlB, nil
+	case 'H':
+		return keyCtrlA, nil
+	case 'F':
+		return keyCtrlE, nil
+	case '~':
+		switch string(param) {
+		case "1", "7":
+			return keyCtrlA, nil
+		case "4", "8":
+			return keyCtrlE, nil
+		case "3":
+			return keyDelete, nil
+		}
+	}
+	return 0, nil
+}
@@ -0,0 +1,86 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package main
+
+import (
+	"bufio"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestEditor(input string, history ...string) (*lineEditor, *int) {
+	raw := 0
+	return &lineEditor{
+		in:  bufio.NewReader(strings.NewReader(input)),
+		out: ioutil.Discard,
+		raw: func() (func(), error) {
+			raw++
+			return func() { raw-- }, nil
+		},
+		history: history,
+	}, &raw
+}
+
+func TestLineEditor(t *testing.T) {
+	for _, c := range []struct {
+		input string
+		want  string
+	}{
+		{"SHOW HOSTS\r", "SHOW HOSTS"},
+		{"SHOW HSTS\x1b[D\x1b[D\x1b[DO\r", "SHOW HOSTS"},
+		{"SHOW HOSTSS\x7f\r", "SHOW HOSTS"},
+		{"HOSTS\x01SHOW \r", "SHOW HOSTS"},
+		{"SHOW HOSTS storage\x02\x02\x02\x02\x02\x02\x02\x02\x0b\r", "SHOW HOSTS"},
+		{"DROP SPACE x\x15SHOW HOSTS\r", "SHOW HOSTS"},
+		{"XSHOW HOSTS\x1b[H\x1b[3~\x1b[F\r", "SHOW HOSTS"},
+		{"SHOW HOSTS\x1bOA\x03", ""},
+		{"SHOW\x1b[5~ HOSTS\x07\r", "SHOW HOSTS"},
+		{"SHOW HÖSTS\r", "SHOW HÖSTS"},
+	} {
+		editor, raw := newTestEditor(c.input)
+		line, err := editor.readLine("> ")
+		assert.Nil(t, err, c.input)
+		assert.Equal(t, c.want, line, c.input)
+		assert.Equal(t, 0, *raw, "the mode of the terminal must be restored")
+	}
+}
+
+func TestLineEditorHistory(t *testing.T) {
+	editor, _ := newTestEditor("\x1b[A\x1b[A\r"+"SHOW\x1b[A\x1b[B\r"+"\x10\x10\x10\x0e\r", "SHOW SPACES", "SHOW HOSTS")
+	line, err := editor.readLine("> ")
+	assert.Nil(t, err)
+	assert.Equal(t, "SHOW SPACES", line)
+	// the line being typed is restored after browsing the history
+	line, err = editor.readLine("> ")
+	assert.Nil(t, err)
+	assert.Equal(t, "SHOW", line)
+	line, err = editor.readLine("> ")
+	assert.Nil(t, err)
+	assert.Equal(t, "SHOW HOSTS", line)
+
+	editor.addHistory("YIELD 1")
+	editor.addHistory("YIELD 1")
+	assert.Equal(t, []string{"SHOW SPACES", "SHOW HOSTS", "YIELD 1"}, editor.history)
+}
+
+func TestLineEditorEOF(t *testing.T) {
+	editor, _ := newTestEditor("SHOW\x04\x04\x04\x04\x04")
+	_, err := editor.readLine("> ")
+	assert.Equal(t, io.EOF, err)
+	editor, _ = newTestEditor("SHOW\x01\x04\x04\x04\x04\x04")
+	_, err = editor.readLine("> ")
+	assert.Equal(t, io.EOF, err)
+	editor, _ = newTestEditor("\x04")
+	_, err = editor.readLine("> ")
+	assert.Equal(t, io.EOF, err)
+}
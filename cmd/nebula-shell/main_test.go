@@ -0,0 +1,97 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	nebula "github.com/vesoft-inc/nebula-go/v3"
+)
+
+// failingExecutor records the statements and fails them, a ResultSet can not be built outside of the driver
+type failingExecutor struct {
+	stmts []string
+}
+
+func (e *failingExecutor) Execute(stmt string, opts ...nebula.ExecOption) (*nebula.ResultSet, error) {
+	e.stmts = append(e.stmts, stmt)
+	return nil, fmt.Errorf("connection refused")
+}
+
+func (e *failingExecutor) ExecuteWithParameter(stmt string, params map[string]interface{},
+	opts ...nebula.ExecOption) (*nebula.ResultSet, error) {
+	return e.Execute(stmt, opts...)
+}
+
+func (e *failingExecutor) ExecuteWithContext(ctx context.Context, stmt string, params map[string]interface{},
+	opts ...nebula.ExecOption) (*nebula.ResultSet, error) {
+	return e.Execute(stmt, opts...)
+}
+
+func TestShellRun(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nebula-shell")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	history, err := os.OpenFile(filepath.Join(dir, "history"), os.O_CREATE|os.O_APPEND|os.O_RDWR, 0600)
+	assert.Nil(t, err)
+	defer history.Close()
+
+	var out bytes.Buffer
+	executor := &failingExecutor{}
+	input := strings.Join([]string{
+		"SHOW SPACES",
+		`GO FROM "p1" \`,
+		"OVER follow YIELD dst(edge)",
+		"",
+		":format vertical",
+		":format json",
+		":csv out.csv",
+		":bogus",
+		":history",
+		":exit",
+		"SHOW HOSTS",
+	}, "\n")
+	sh := &shell{session: executor, out: &out, lines: newScannerReader(strings.NewReader(input), &out), history: history}
+	sh.run()
+
+	assert.Equal(t, []string{"SHOW SPACES", "GO FROM \"p1\" \nOVER follow YIELD dst(edge)"}, executor.stmts)
+	assert.Equal(t, nebula.FormatVertical, sh.format)
+	assert.Equal(t, "(nebula) > [ERROR]: connection refused\n"+
+		"(nebula) >          > [ERROR]: connection refused\n"+
+		"(nebula) > "+
+		"(nebula) > "+
+		"(nebula) > [ERROR]: unknown format json\n"+
+		"(nebula) > [ERROR]: no result to export\n"+
+		"(nebula) > [ERROR]: unknown command :bogus\n"+
+		"(nebula) >     1  SHOW SPACES\n"+
+		"    2  GO FROM \"p1\"  OVER follow YIELD dst(edge)\n"+
+		"(nebula) > ", out.String())
+
+	// the history is read back by the next shell
+	_, err = history.Seek(0, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"SHOW SPACES", "GO FROM \"p1\"  OVER follow YIELD dst(edge)"}, readHistory(history))
+}
+
+func TestShellRunEOF(t *testing.T) {
+	var out bytes.Buffer
+	executor := &failingExecutor{}
+	sh := &shell{session: executor, out: &out, lines: newScannerReader(strings.NewReader("SHOW SPACES"), &out)}
+	sh.run()
+	assert.Equal(t, []string{"SHOW SPACES"}, executor.stmts)
+	assert.Equal(t, "(nebula) > [ERROR]: connection refused\n(nebula) > \n", out.String())
+}
@@ -0,0 +1,23 @@
+//go:build !linux
+// +build !linux
+
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package main
+
+import "fmt"
+
+// isTerminal returns false, the line editing is only supported on linux
+func isTerminal(fd int) bool {
+	return false
+}
+
+func makeRaw(fd int) (func(), error) {
+	return nil, fmt.Errorf("raw terminal mode is not supported")
+}
@@ -0,0 +1,203 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultCoalesceWindow is the time a FetchCoalescer waits for more point reads before fetching a batch
+	DefaultCoalesceWindow = 2 * time.Millisecond
+	// DefaultCoalesceTimeout is the deadline of the fetch of a batch of a FetchCoalescer
+	DefaultCoalesceTimeout = 30 * time.Second
+)
+
+// WithCoalesceTimeout sets the deadline of the fetch of a batch of the fetch coalescers of the pool,
+// 0 value means DefaultCoalesceTimeout
+func WithCoalesceTimeout(timeout time.Duration) PoolConfOption {
+	return func(conf *PoolConfig) {
+		conf.CoalesceTimeout = timeout
+	}
+}
+
+// FetchCoalescer coalesces the point reads of vertices and edges which arrive within a short window
+// into a single FETCH statement, with FetchVertices and FetchEdges, and hands every caller its own result.
+// It trades a few milliseconds of latency for far fewer round trips when many goroutines read single ids.
+// Batches are fetched with sessions acquired from the pool, in the space of the pool config,
+// within the deadline set with WithCoalesceTimeout.
+type FetchCoalescer struct {
+	clock    Clock
+	window   time.Duration
+	maxBatch int
+	timeout  time.Duration
+	fetch    func(ctx context.Context, b *fetchBatch) error
+
+	mu      sync.Mutex
+	pending map[fetchTarget]*fetchBatch
+	closed  bool
+}
+
+// fetchTarget is the tag or edge type of a batch
+type fetchTarget struct {
+	edge bool
+	name string
+}
+
+type fetchBatch struct {
+	target fetchTarget
+	vids   []VID
+	keys   []EdgeKey
+	nodes  map[VID]*Node
+	edges  map[EdgeKey]*Relationship
+	err    error
+	done   chan struct{}
+}
+
+// NewFetchCoalescer returns a FetchCoalescer fetching with the sessions of the pool. A batch is fetched
+// once window has elapsed since its first read, or as soon as it holds maxBatch ids.
+// Zero values default to DefaultCoalesceWindow and DefaultFetchBatchSize.
+func NewFetchCoalescer(pool *ConnectionPool, window time.Duration, maxBatch int) *FetchCoalescer {
+	return newFetchCoalescer(pool.conf.Clock, window, maxBatch, pool.conf.CoalesceTimeout, func(ctx context.Context, b *fetchBatch) error {
+		return pool.WithSession(ctx, func(session *Session) (err error) {
+			if b.target.edge {
+				b.edges, _, err = session.FetchEdges(ctx, b.target.name, b.keys)
+			} else {
+				b.nodes, _, err = session.FetchVertices(ctx, b.target.name, b.vids)
+			}
+			return err
+		})
+	})
+}
+
+func newFetchCoalescer(clock Clock, window time.Duration, maxBatch int, timeout time.Duration,
+	fetch func(context.Context, *fetchBatch) error) *FetchCoalescer {
+	if window <= 0 {
+		window = DefaultCoalesceWindow
+	}
+	if maxBatch <= 0 {
+		maxBatch = DefaultFetchBatchSize
+	}
+	if timeout <= 0 {
+		timeout = DefaultCoalesceTimeout
+	}
+	return &FetchCoalescer{
+		clock:    clock,
+		window:   window,
+		maxBatch: maxBatch,
+		timeout:  timeout,
+		fetch:    fetch,
+		pending:  make(map[fetchTarget]*fetchBatch),
+	}
+}
+
+// FetchVertex fetches the vertex of the tag, or of any tag if tag is "*", within the next batch.
+// It returns false if the vertex does not exist. The context only bounds the wait of the caller,
+// the batch is fetched for the other callers even if it is canceled.
+func (c *FetchCoalescer) FetchVertex(ctx context.Context, tag string, vid VID) (*Node, bool, error) {
+	b, err := c.add(fetchTarget{name: tag}, func(b *fetchBatch) int {
+		b.vids = append(b.vids, vid)
+		return len(b.vids)
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	if err = c.wait(ctx, b); err != nil {
+		return nil, false, err
+	}
+	node, ok := b.nodes[vid]
+	return node, ok, nil
+}
+
+// FetchEdge fetches the edge of the edge type within the next batch. It returns false if the edge does not exist.
+func (c *FetchCoalescer) FetchEdge(ctx context.Context, edge string, key EdgeKey) (*Relationship, bool, error) {
+	b, err := c.add(fetchTarget{edge: true, name: edge}, func(b *fetchBatch) int {
+		b.keys = append(b.keys, key)
+		return len(b.keys)
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	if err = c.wait(ctx, b); err != nil {
+		return nil, false, err
+	}
+	relationship, ok := b.edges[key]
+	return relationship, ok, nil
+}
+
+// Close fetches the pending batches immediately, later reads fail
+func (c *FetchCoalescer) Close() {
+	c.mu.Lock()
+	c.closed = true
+	pending := c.pending
+	c.pending = make(map[fetchTarget]*fetchBatch)
+	c.mu.Unlock()
+	for _, b := range pending {
+		go c.run(b)
+	}
+}
+
+// add appends an id to the pending batch of the target, starting a new batch if there is none
+func (c *FetchCoalescer) add(target fetchTarget, appendID func(*fetchBatch) int) (*fetchBatch, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return nil, fmt.Errorf("failed to fetch: the coalescer has been closed")
+	}
+	b, ok := c.pending[target]
+	if !ok {
+		b = &fetchBatch{target: target, done: make(chan struct{})}
+		c.pending[target] = b
+		timer := c.clock.NewTimer(c.window)
+		go func() {
+			select {
+			case <-timer.C():
+				c.flush(b)
+			case <-b.done:
+				timer.Stop()
+			}
+		}()
+	}
+	if appendID(b) >= c.maxBatch {
+		delete(c.pending, target)
+		go c.run(b)
+	}
+	return b, nil
+}
+
+// flush fetches the batch if it is still pending
+func (c *FetchCoalescer) flush(b *fetchBatch) {
+	c.mu.Lock()
+	if c.pending[b.target] != b {
+		c.mu.Unlock()
+		return
+	}
+	delete(c.pending, b.target)
+	c.mu.Unlock()
+	c.run(b)
+}
+
+func (c *FetchCoalescer) run(b *fetchBatch) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+	b.err = c.fetch(ctx, b)
+	close(b.done)
+}
+
+func (c *FetchCoalescer) wait(ctx context.Context, b *fetchBatch) error {
+	select {
+	case <-b.done:
+		return b.err
+	case <-ctx.Done():
+		return fmt.Errorf("failed to fetch: %s", ctx.Err().Error())
+	}
+}
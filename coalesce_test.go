@@ -0,0 +1,113 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFetchCoalescer(t *testing.T) {
+	clock := NewManualClock(time.Now())
+	var mu sync.Mutex
+	var batches [][]VID
+	c := newFetchCoalescer(clock, time.Millisecond, 3, 0, func(ctx context.Context, b *fetchBatch) error {
+		mu.Lock()
+		batches = append(batches, b.vids)
+		mu.Unlock()
+		if b.target.name == "bad" {
+			return fmt.Errorf("failed to fetch vertices")
+		}
+		b.nodes = make(map[VID]*Node)
+		for _, vid := range b.vids {
+			if vid != StringVID("missing") {
+				b.nodes[vid] = &Node{}
+			}
+		}
+		return nil
+	})
+	pendingVIDs := func(tag string) int {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if b, ok := c.pending[fetchTarget{name: tag}]; ok {
+			return len(b.vids)
+		}
+		return 0
+	}
+
+	var wg sync.WaitGroup
+	found := make([]bool, 2)
+	for i, vid := range []VID{StringVID("a"), StringVID("missing")} {
+		wg.Add(1)
+		go func(i int, vid VID) {
+			defer wg.Done()
+			_, ok, err := c.FetchVertex(context.Background(), "player", vid)
+			assert.Nil(t, err)
+			found[i] = ok
+		}(i, vid)
+	}
+	for pendingVIDs("player") < 2 {
+		time.Sleep(time.Millisecond)
+	}
+	clock.Advance(time.Millisecond)
+	wg.Wait()
+	assert.Equal(t, []bool{true, false}, found)
+	assert.Equal(t, 1, len(batches))
+	assert.ElementsMatch(t, []VID{StringVID("a"), StringVID("missing")}, batches[0])
+
+	// a full batch is fetched without waiting for the window
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, ok, err := c.FetchVertex(context.Background(), "player", IntVID(int64(i)))
+			assert.Nil(t, err)
+			assert.True(t, ok)
+		}(i)
+	}
+	wg.Wait()
+	assert.Equal(t, 2, len(batches))
+
+	errCh := make(chan error)
+	go func() {
+		_, _, err := c.FetchVertex(context.Background(), "bad", StringVID("a"))
+		errCh <- err
+	}()
+	for pendingVIDs("bad") < 1 {
+		time.Sleep(time.Millisecond)
+	}
+	clock.Advance(time.Millisecond)
+	assert.NotNil(t, <-errCh)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, _, err := c.FetchVertex(ctx, "player", StringVID("b"))
+	assert.NotNil(t, err)
+
+	c.Close()
+	_, _, err = c.FetchEdge(context.Background(), "follow", EdgeKey{Src: IntVID(1), Dst: IntVID(2)})
+	assert.NotNil(t, err)
+}
+
+func TestFetchCoalescerTimeout(t *testing.T) {
+	var deadline time.Time
+	c := newFetchCoalescer(realClock{}, time.Millisecond, 10, time.Hour, func(ctx context.Context, b *fetchBatch) error {
+		deadline, _ = ctx.Deadline()
+		return nil
+	})
+	defer c.Close()
+	_, _, err := c.FetchVertex(context.Background(), "player", IntVID(1))
+	assert.Nil(t, err)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), deadline, time.Minute)
+}
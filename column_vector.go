@@ -0,0 +1,123 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"fmt"
+)
+
+// ColumnVector holds the values of a column of a ResultSet in a typed slice, depending on the type of the column:
+// Ints for int columns, Floats for float columns and for columns mixing ints and floats, Strings for string
+// columns, Bools for bool columns, and Values for the columns of any other type. Null and empty values are
+// zero values in the typed slices and are flagged in Nulls.
+type ColumnVector struct {
+	Name string
+	// The type of the column, as in Column.Type, "float" for the columns mixing ints and floats
+	Type    string
+	Ints    []int64
+	Floats  []float64
+	Strings []string
+	Bools   []bool
+	Values  []*ValueWrapper
+	// Nulls[i] is true if the value of the row i is null or empty
+	Nulls []bool
+}
+
+// Len returns the number of values
+func (v *ColumnVector) Len() int {
+	return len(v.Nulls)
+}
+
+// Float64s returns the values of an int or float column as floats, e.g. for gonum/stat
+func (v *ColumnVector) Float64s() ([]float64, error) {
+	switch {
+	case v.Floats != nil:
+		return v.Floats, nil
+	case v.Ints != nil:
+		floats := make([]float64, len(v.Ints))
+		for i, n := range v.Ints {
+			floats[i] = float64(n)
+		}
+		return floats, nil
+	}
+	return nil, fmt.Errorf("failed to convert column %s of type %s to floats", v.Name, v.Type)
+}
+
+// Columns returns the values of the result set column by column, keyed by the column name.
+// It fails if two columns have the same name.
+func (res ResultSet) Columns() (map[string]*ColumnVector, error) {
+	columns := res.ColumnsTyped()
+	rows := res.GetRows()
+	vectors := make(map[string]*ColumnVector, len(columns))
+	ordered := make([]*ColumnVector, len(columns))
+	for i, c := range columns {
+		if _, ok := vectors[c.Name]; ok {
+			return nil, fmt.Errorf("failed to get columns: duplicate column name %s", c.Name)
+		}
+		v := &ColumnVector{Name: c.Name, Type: c.Type, Nulls: make([]bool, len(rows))}
+		switch c.Type {
+		case "int":
+			v.Ints = make([]int64, len(rows))
+		case "float":
+			v.Floats = make([]float64, len(rows))
+		case "string":
+			v.Strings = make([]string, len(rows))
+		case "bool":
+			v.Bools = make([]bool, len(rows))
+		case ColumnTypeMixed:
+			if len(c.Types) == 2 && c.Types[0] == "float" && c.Types[1] == "int" {
+				v.Type = "float"
+				v.Floats = make([]float64, len(rows))
+			} else {
+				v.Values = make([]*ValueWrapper, len(rows))
+			}
+		default:
+			v.Values = make([]*ValueWrapper, len(rows))
+		}
+		vectors[c.Name] = v
+		ordered[i] = v
+	}
+
+	for r, row := range rows {
+		values := row.GetValues()
+		for i, v := range ordered {
+			if i >= len(values) || values[i] == nil {
+				v.Nulls[r] = true
+				continue
+			}
+			value := values[i]
+			if v.Values != nil {
+				v.Values[r] = &ValueWrapper{value, res.timezoneInfo}
+			}
+			switch {
+			case value.IsSetIVal():
+				if v.Ints != nil {
+					v.Ints[r] = value.GetIVal()
+				} else if v.Floats != nil {
+					v.Floats[r] = float64(value.GetIVal())
+				}
+			case value.IsSetFVal():
+				if v.Floats != nil {
+					v.Floats[r] = value.GetFVal()
+				}
+			case value.IsSetSVal():
+				if v.Strings != nil {
+					v.Strings[r] = string(value.GetSVal())
+				}
+			case value.IsSetBVal():
+				if v.Bools != nil {
+					v.Bools[r] = value.GetBVal()
+				}
+			case value.IsSetNVal(), ValueWrapper{value, res.timezoneInfo}.GetType() == "empty":
+				v.Nulls[r] = true
+			}
+		}
+	}
+	return vectors, nil
+}
@@ -0,0 +1,56 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResultSetColumns(t *testing.T) {
+	rs := newTestResultSet(t, []string{"i", "f", "s", "b", "l", "n"},
+		[]interface{}{1, 1.5, "a", true, []interface{}{1}, nil},
+		[]interface{}{nil, 2, "b", false, "x", nil},
+		[]interface{}{3, 2.5, nil, nil, nil, nil},
+	)
+	columns, err := rs.Columns()
+	assert.Nil(t, err)
+	assert.Len(t, columns, 6)
+
+	i := columns["i"]
+	assert.Equal(t, "int", i.Type)
+	assert.Equal(t, []int64{1, 0, 3}, i.Ints)
+	assert.Equal(t, []bool{false, true, false}, i.Nulls)
+	assert.Equal(t, 3, i.Len())
+	floats, err := i.Float64s()
+	assert.Nil(t, err)
+	assert.Equal(t, []float64{1, 0, 3}, floats)
+
+	f := columns["f"]
+	assert.Equal(t, "float", f.Type)
+	assert.Equal(t, []float64{1.5, 2, 2.5}, f.Floats)
+
+	assert.Equal(t, []string{"a", "b", ""}, columns["s"].Strings)
+	assert.Equal(t, []bool{false, false, true}, columns["s"].Nulls)
+	assert.Equal(t, []bool{true, false, false}, columns["b"].Bools)
+
+	l := columns["l"]
+	assert.Equal(t, ColumnTypeMixed, l.Type)
+	assert.Equal(t, "[1]", l.Values[0].String())
+	assert.True(t, l.Nulls[2])
+	_, err = l.Float64s()
+	assert.NotNil(t, err)
+
+	assert.Equal(t, "null", columns["n"].Type)
+	assert.Equal(t, []bool{true, true, true}, columns["n"].Nulls)
+
+	_, err = newTestResultSet(t, []string{"a", "a"}, []interface{}{1, 2}).Columns()
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,71 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"sort"
+)
+
+// ColumnTypeMixed is the type of the columns whose values have different types
+const ColumnTypeMixed = "mixed"
+
+// Column is the metadata of a column of a ResultSet, inferred from its values
+// since the graph service only returns the names of the columns
+type Column struct {
+	Name string
+	// The type of the values which are neither null nor empty, as returned by ValueWrapper.GetType,
+	// ColumnTypeMixed if they have different types, or "null" if there is no such value
+	Type string
+	// The distinct types of the values which are neither null nor empty, sorted
+	Types []string
+	// At least one value is null or empty
+	Nullable bool
+}
+
+// ColumnsTyped returns the metadata of the columns, inferred by scanning every row
+func (res ResultSet) ColumnsTyped() []Column {
+	columns := make([]Column, len(res.columnNames))
+	types := make([]map[string]bool, len(res.columnNames))
+	for i, name := range res.columnNames {
+		columns[i].Name = name
+		types[i] = make(map[string]bool)
+	}
+	for _, row := range res.GetRows() {
+		for i, value := range row.GetValues() {
+			if i >= len(columns) {
+				break
+			}
+			if value == nil || value.IsSetNVal() {
+				columns[i].Nullable = true
+				continue
+			}
+			t := ValueWrapper{value, res.timezoneInfo}.GetType()
+			if t == "empty" {
+				columns[i].Nullable = true
+				continue
+			}
+			types[i][t] = true
+		}
+	}
+	for i := range columns {
+		for t := range types[i] {
+			columns[i].Types = append(columns[i].Types, t)
+		}
+		sort.Strings(columns[i].Types)
+		switch len(columns[i].Types) {
+		case 0:
+			columns[i].Type = "null"
+		case 1:
+			columns[i].Type = columns[i].Types[0]
+		default:
+			columns[i].Type = ColumnTypeMixed
+		}
+	}
+	return columns
+}
@@ -0,0 +1,30 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestColumnsTyped(t *testing.T) {
+	resp := newTestResultSet(t, []string{"name", "age", "score", "none"},
+		[]interface{}{"Bob", 30, 1, nil},
+		[]interface{}{"Tom", nil, 2.5, nil})
+	assert.Equal(t, []Column{
+		{Name: "name", Type: "string", Types: []string{"string"}},
+		{Name: "age", Type: "int", Types: []string{"int"}, Nullable: true},
+		{Name: "score", Type: ColumnTypeMixed, Types: []string{"float", "int"}},
+		{Name: "none", Type: "null", Nullable: true},
+	}, resp.ColumnsTyped())
+
+	empty := newTestResultSet(t, []string{"v"})
+	assert.Equal(t, []Column{{Name: "v", Type: "null"}}, empty.ColumnsTyped())
+}
@@ -12,8 +12,11 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"fmt"
+	"io"
 	"io/ioutil"
+	"math/rand"
 	"os"
+	"strconv"
 	"time"
 )
 
@@ -29,6 +32,134 @@ type PoolConfig struct {
 	MaxConnPoolSize int
 	// The min connections in pool for all addresses
 	MinConnPoolSize int
+	// The username and password of the sessions acquired without explicit credentials
+	Username string
+	Password string
+	// The timezone applied to every session acquired from the pool, e.g. "Asia/Shanghai" or "+08:00"
+	// Empty value means the timezone returned by the graph service is used
+	TimeZone string
+	// The charset every session acquired from the pool must be able to use, e.g. "utf8"
+	// It is checked against SHOW CHARSET when the session is acquired
+	// Empty value means no check is performed
+	Charset string
+	// Acquires waiting longer than StarvationThreshold are counted as starvations in the pool stats
+	// 0 value means only the acquires which gave up waiting are counted
+	StarvationThreshold time.Duration
+	// The clock used by the time based behaviors of the pool
+	// nil value means the system clock is used
+	Clock Clock
+	// The random source used for load balancing decisions and retry jitter
+	// It may be shared by several pools but must not be used by the caller while they are open
+	// nil value means a source seeded with the current time is used
+	Rand *rand.Rand
+	// Dump the bytes exchanged with the graph service, for debugging protocol issues only
+	WireDump bool
+	// The writer of the wire dump, nil value means the dump is written to the logger of the pool
+	WireDumpWriter io.Writer
+	// The max number of bytes dumped per frame, 0 value means DefaultWireDumpMaxBytes is used
+	WireDumpMaxBytes int
+	// Discover the version of the graph service of each connection with SHOW HOSTS GRAPH,
+	// so that optional features not supported by older versions are rejected by the client
+	NegotiateServerVersion bool
+	// The version of the graph service, e.g. "3.1.0", which overrides the negotiated version
+	ServerVersion string
+	// The interceptors of the statements executed by the sessions, the first one is the outermost
+	Interceptors []Interceptor
+	// The names of the configs which can be changed with Session.UpdateConfig
+	// nil value means every config can be changed
+	ConfigUpdateAllowlist []string
+	// The space every session acquired from the pool switches to
+	// Empty value means the sessions do not use a space until USE is executed
+	Space string
+	// The name and version of the application, recorded as a comment of every statement
+	// so that the load of the graph service can be attributed to its clients
+	ClientName    string
+	ClientVersion string
+	// Cap MaxConnPoolSize to the limits of the graph service read with SHOW CONFIGS GRAPH when the pool is created
+	// The credentials of the pool config are required
+	AdaptivePoolSize bool
+	// The statements executed once per connection, by the first session acquired on it
+	// Unlike the session settings above, they are not applied again when the connection is reused
+	OnConnectStmts []string
+	// Create the pool without connecting to the graph service, the connections are opened on first use
+	// so that the pool can be created while the cluster is unreachable. MinConnPoolSize and
+	// AdaptivePoolSize are not applied when the pool is created.
+	LazyInit bool
+	// Kill the query of a session with KILL QUERY, on a separate session acquired with the credentials
+	// of the pool config, when the context of its execution is done, so that the graph service stops working on it
+	KillOnCancel bool
+	// The function opening the network connections, e.g. through a local proxy
+	// nil value means the hosts are dialed directly, over TCP or over unix domain sockets
+	Dialer DialFunc
+	// The hook called with the panics recovered while executing statements and decoding their responses,
+	// which are returned as a *PanicError instead of crashing the process
+	PanicHook PanicHook
+	// Require the space of every statement to be given with Session.ExecuteIn, Space is then ignored
+	ExplicitSpace bool
+	// Reject the executions while the result sets and the in-flight decodes of the pool hold more bytes,
+	// as estimated from the size of the responses. 0 value means no limit
+	MemorySoftLimit int64
+	// Grow and shrink the capacity of the pool between MinConnPoolSize and MaxConnPoolSize
+	// depending on its utilization and on the wait time of the acquires, nil value means a fixed capacity
+	Autoscale *AutoscaleConfig
+	// Switch the sessions back to Space before a statement if a previous statement moved them to another space
+	ValidateSpace bool
+	// The time the vid types and the properties of the tags and edge types are cached, 0 disables the cache
+	SchemaCacheTTL time.Duration
+	// The TLS configs overriding the TLS config of the pool per host, a nil config disables TLS for the host
+	HostTLS map[HostAddress]*tls.Config
+	// The server name verified against the certificates of the graph services, see WithTLSServerName
+	TLSServerName string
+	// The SHA-256 hashes of the certificates of the graph services, see WithPinnedCertificates
+	PinnedCertificates []string
+	// The provider of the password of the sessions acquired without explicit credentials, see WithPasswordProvider
+	PasswordProvider SecretProvider
+	// The provider of the username of the sessions acquired without explicit credentials, see WithUsernameProvider
+	UsernameProvider SecretProvider
+	// The minimum TLS version, e.g. tls.VersionTLS12, 0 keeps the minimum of the TLS configs
+	TLSMinVersion uint16
+	// The cipher suites of TLS 1.2 and lower, nil keeps the cipher suites of the TLS configs
+	TLSCipherSuites []uint16
+	// The max number of statement fingerprints whose latency histograms are tracked, 0 disables them
+	LatencyHistograms int
+	// The max number of connections used at once by the acquires of a workload, see WithWorkloadPartition
+	WorkloadPartitions map[string]int
+	// The max number of sessions used at once by ExecuteBatch, 0 means half of MaxConnPoolSize
+	BatchParallelism int
+	// The extractor of the tenant labelling the executions, see WithTenantFromContext
+	TenantFromContext TenantExtractor
+	// Recreate the sessions whose connection was reset, see WithSessionRepin
+	SessionRepin bool
+	// Execute the read-only statements again once their session has been recreated, see WithSessionRepin
+	ReplayReadOnly bool
+	// The hook called when a session is created, recreated or released, see WithSessionHook
+	SessionHook SessionHook
+	// The hook rendering the writes instead of executing them, see WithDryRunHook
+	DryRun DryRunHook
+	// The number of statements kept by every session to give context to its errors, see WithQueryHistory
+	QueryHistory int
+	// The deadline of the execution of a batch of a WriteScheduler, see WithWriteBatchTimeout
+	WriteBatchTimeout time.Duration
+	// The deadline of the fetch of a batch of a FetchCoalescer, see WithCoalesceTimeout
+	CoalesceTimeout time.Duration
+}
+
+// PoolConfOption is an option applied to a PoolConfig
+type PoolConfOption func(*PoolConfig)
+
+// WithCredentials sets the username and password of the sessions acquired without explicit credentials
+func WithCredentials(username, password string) PoolConfOption {
+	return func(conf *PoolConfig) {
+		conf.Username = username
+		conf.Password = password
+	}
+}
+
+// WithClock sets the clock used by the time based behaviors of the pool
+func WithClock(clock Clock) PoolConfOption {
+	return func(conf *PoolConfig) {
+		conf.Clock = clock
+	}
 }
 
 // validateConf validates config
@@ -49,6 +180,108 @@ func (conf *PoolConfig) validateConf(log Logger) {
 		conf.MinConnPoolSize = 0
 		log.Warn("Invalid MinConnPoolSize value, the default value of 0 has been applied")
 	}
+	if conf.Clock == nil {
+		conf.Clock = realClock{}
+	}
+	conf.PasswordProvider = withClock(conf.PasswordProvider, conf.Clock)
+	conf.UsernameProvider = withClock(conf.UsernameProvider, conf.Clock)
+	if conf.Rand == nil {
+		conf.Rand = rand.New(rand.NewSource(conf.Clock.Now().UnixNano()))
+	}
+	if conf.StarvationThreshold < 0 {
+		conf.StarvationThreshold = 0
+		log.Warn("Invalid StarvationThreshold value, the default value of 0 second has been applied")
+	}
+	if conf.ServerVersion != "" {
+		if _, err := ParseServerVersion(conf.ServerVersion); err != nil {
+			conf.ServerVersion = ""
+			log.Warn(fmt.Sprintf("Invalid ServerVersion value, the server version will be negotiated: %s", err.Error()))
+			conf.NegotiateServerVersion = true
+		}
+	}
+	if conf.TimeZone != "" {
+		if _, err := loadTimeZone(conf.TimeZone); err != nil {
+			conf.TimeZone = ""
+			log.Warn(fmt.Sprintf("Invalid TimeZone value, the timezone of the graph service will be used: %s", err.Error()))
+		}
+	}
+}
+
+// loadTimeZone returns the location of an IANA timezone name, or of a fixed offset like "+08:00"
+func loadTimeZone(name string) (*time.Location, error) {
+	if len(name) == len("+08:00") && (name[0] == '+' || name[0] == '-') && name[3] == ':' {
+		hours, errH := strconv.Atoi(name[1:3])
+		minutes, errM := strconv.Atoi(name[4:])
+		if errH == nil && errM == nil && hours <= 14 && minutes < 60 {
+			offset := hours*3600 + minutes*60
+			if name[0] == '-' {
+				offset = -offset
+			}
+			return time.FixedZone(name, offset), nil
+		}
+	}
+	return time.LoadLocation(name)
+}
+
+// WithRand sets the random source used for load balancing decisions and retry jitter.
+// It may be shared by several pools but must not be used by the caller while they are open.
+func WithRand(r *rand.Rand) PoolConfOption {
+	return func(conf *PoolConfig) {
+		conf.Rand = r
+	}
+}
+
+// WithRandSeed sets the random source used for load balancing decisions and retry jitter
+// to a new source with the given seed, so that the pool behavior is reproducible
+func WithRandSeed(seed int64) PoolConfOption {
+	return WithRand(rand.New(rand.NewSource(seed)))
+}
+
+// WithWireDump enables the wire dump of the bytes exchanged with the graph service.
+// The dump is written to w, or to the logger of the pool if w is nil,
+// and at most maxBytes bytes of each frame are dumped.
+func WithWireDump(w io.Writer, maxBytes int) PoolConfOption {
+	return func(conf *PoolConfig) {
+		conf.WireDump = true
+		conf.WireDumpWriter = w
+		conf.WireDumpMaxBytes = maxBytes
+	}
+}
+
+// WithSpace sets the space every session acquired from the pool switches to
+func WithSpace(space string) PoolConfOption {
+	return func(conf *PoolConfig) {
+		conf.Space = space
+	}
+}
+
+// WithClientIdentity sets the name and version of the application recorded with every statement
+func WithClientIdentity(name, version string) PoolConfOption {
+	return func(conf *PoolConfig) {
+		conf.ClientName = name
+		conf.ClientVersion = version
+	}
+}
+
+// WithOnConnectStmt appends statements executed once per connection, by the first session acquired on it
+func WithOnConnectStmt(stmts ...string) PoolConfOption {
+	return func(conf *PoolConfig) {
+		conf.OnConnectStmts = append(conf.OnConnectStmts, stmts...)
+	}
+}
+
+// WithLazyInit creates the pool without connecting to the graph service, the connections are opened on first use
+func WithLazyInit() PoolConfOption {
+	return func(conf *PoolConfig) {
+		conf.LazyInit = true
+	}
+}
+
+// WithKillOnCancel kills the queries whose context is done before they finish
+func WithKillOnCancel() PoolConfOption {
+	return func(conf *PoolConfig) {
+		conf.KillOnCancel = true
+	}
 }
 
 // GetDefaultConf returns the default config
@@ -61,6 +294,15 @@ func GetDefaultConf() PoolConfig {
 	}
 }
 
+// NewPoolConf returns the default config with the given options applied
+func NewPoolConf(opts ...PoolConfOption) PoolConfig {
+	conf := GetDefaultConf()
+	for _, opt := range opts {
+		opt(&conf)
+	}
+	return conf
+}
+
 // GetDefaultSSLConfig reads the files in the given path and returns a tls.Config object
 func GetDefaultSSLConfig(rootCAPath, certPath, privateKeyPath string) (*tls.Config, error) {
 	rootCA, err := openAndReadFile(rootCAPath)
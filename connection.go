@@ -25,15 +25,36 @@ type connection struct {
 	returnedAt   time.Time // the connection was created or returned.
 	sslConfig    *tls.Config
 	graph        *graph.GraphServiceClient
+	clock        Clock
+	wireDumper   *wireDumper
+	// the version of the graph service, negotiated when the first session is acquired
+	serverVersion ServerVersion
+	// the on-connect statements of the pool config have been executed
+	onConnectDone bool
+	// the function opening the network connection, nil value means the default thrift sockets
+	dial DialFunc
+	// counts the bytes of the responses
+	counter *countingTransport
+	// the workload partition the connection is used by, see WithWorkloadPartition
+	workload string
+	// the server session using the connection, 0 if none
+	sessionID int64
+	// a panic interrupted the read of a response, so the next response can not be read
+	broken bool
 }
 
 func newConnection(severAddress HostAddress) *connection {
+	return newConnectionWithClock(severAddress, realClock{})
+}
+
+func newConnectionWithClock(severAddress HostAddress, clock Clock) *connection {
 	return &connection{
 		severAddress: severAddress,
 		timeout:      0 * time.Millisecond,
-		returnedAt:   time.Now(),
+		returnedAt:   clock.Now(),
 		sslConfig:    nil,
 		graph:        nil,
+		clock:        clock,
 	}
 }
 
@@ -49,7 +70,9 @@ func (cn *connection) open(hostAddress HostAddress, timeout time.Duration, sslCo
 
 	var err error
 	var sock thrift.Transport
-	if sslConfig != nil {
+	if cn.dial != nil || hostAddress.IsUnixSocket() {
+		sock = newDialedSocket(hostAddress, timeout, sslConfig, cn.dial)
+	} else if sslConfig != nil {
 		sock, err = thrift.NewSSLSocketTimeout(newAdd, sslConfig, timeout)
 	} else {
 		sock, err = thrift.NewSocket(thrift.SocketAddr(newAdd), thrift.SocketTimeout(timeout))
@@ -57,6 +80,11 @@ func (cn *connection) open(hostAddress HostAddress, timeout time.Duration, sslCo
 	if err != nil {
 		return fmt.Errorf("failed to create a net.Conn-backed Transport,: %s", err.Error())
 	}
+	cn.counter = &countingTransport{Transport: sock}
+	sock = cn.counter
+	if cn.wireDumper != nil {
+		sock = &wireDumpTransport{Transport: sock, dumper: cn.wireDumper, address: hostAddress}
+	}
 
 	// Set transport buffer
 	bufferedTranFactory := thrift.NewBufferedTransportFactory(bufferSize)
@@ -105,11 +133,20 @@ func (cn *connection) authenticate(username, password string) (*graph.AuthRespon
 		return nil, err
 	}
 	if resp.ErrorCode != nebula.ErrorCode_SUCCEEDED {
-		return nil, fmt.Errorf("fail to authenticate, error: %s", resp.ErrorMsg)
+		return nil, &authError{msg: string(resp.ErrorMsg)}
 	}
 	return resp, err
 }
 
+// authError is the error of an authentication rejected by the graph service
+type authError struct {
+	msg string
+}
+
+func (e *authError) Error() string {
+	return fmt.Sprintf("fail to authenticate, error: %s", e.msg)
+}
+
 func (cn *connection) execute(sessionID int64, stmt string) (*graph.ExecutionResponse, error) {
 	return cn.executeWithParameter(sessionID, stmt, map[string]*nebula.Value{})
 }
@@ -174,7 +211,7 @@ func (cn *connection) signOut(sessionID int64) error {
 
 // Update returnedAt for cleaner
 func (cn *connection) release() {
-	cn.returnedAt = time.Now()
+	cn.returnedAt = cn.clock.Now()
 }
 
 // Close transport
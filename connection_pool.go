@@ -10,6 +10,7 @@ package nebula_go
 
 import (
 	"container/list"
+	"context"
 	"crypto/tls"
 	"fmt"
 	"sync"
@@ -19,9 +20,13 @@ import (
 )
 
 type ConnectionPool struct {
+	// first for the alignment of its 64-bit atomic counters on 32-bit platforms
+	memory                memoryStats
+	spaceCorrections      int64
 	idleConnectionQueue   list.List
 	activeConnectionQueue list.List
 	addresses             []HostAddress
+	configuredAddresses   []HostAddress //addresses as passed to the pool, before their names are resolved
 	conf                  PoolConfig
 	hostIndex             int
 	log                   Logger
@@ -29,6 +34,18 @@ type ConnectionPool struct {
 	cleanerChan           chan struct{} //notify when pool is close
 	closed                bool
 	sslConfig             *tls.Config
+	hostTLS               map[HostAddress]*tls.Config
+	releasedCh            chan struct{} //notify when a connection is released
+	acquireStats          acquireStats
+	wireDumper            *wireDumper
+	stmtPrefix            string //comment prepended to every statement
+	name                  string //name in the registry of OpenNamed
+	autoscaler            *autoscaler
+	schema                schemaCache
+	latencyHistograms     latencyHistograms
+	errorCounters         errorCounters
+	workloadConns         map[string]int //connections used by each workload partition
+	drained               map[HostAddress]bool
 }
 
 // NewConnectionPool constructs a new connection pool using the given addresses and configs
@@ -38,10 +55,20 @@ func NewConnectionPool(addresses []HostAddress, conf PoolConfig, log Logger) (*C
 
 // NewConnectionPool constructs a new SSL connection pool using the given addresses and configs
 func NewSslConnectionPool(addresses []HostAddress, conf PoolConfig, sslConfig *tls.Config, log Logger) (*ConnectionPool, error) {
-	// Process domain to IP
-	convAddress, err := DomainToIP(addresses)
-	if err != nil {
-		return nil, fmt.Errorf("failed to find IP, error: %s ", err.Error())
+	log = clientLoggerFor(log, conf.ClientName, conf.ClientVersion)
+	// Process domain to IP, unless the connections are opened by a dialer, e.g. through a proxy
+	// which resolves the names itself and may be the only one able to
+	convAddress := addresses
+	var err error
+	if conf.Dialer == nil {
+		if convAddress, err = DomainToIP(addresses); err != nil {
+			if !conf.LazyInit {
+				return nil, fmt.Errorf("failed to find IP, error: %s ", err.Error())
+			}
+			// the names are resolved again when the connections are opened
+			log.Warn(fmt.Sprintf("Failed to find IP, the hosts will be resolved on first use: %s", err.Error()))
+			convAddress = addresses
+		}
 	}
 
 	// Check input
@@ -53,18 +80,40 @@ func NewSslConnectionPool(addresses []HostAddress, conf PoolConfig, sslConfig *t
 	conf.validateConf(log)
 
 	newPool := &ConnectionPool{
-		conf:      conf,
-		log:       log,
-		addresses: convAddress,
-		hostIndex: 0,
-		sslConfig: sslConfig,
+		conf:                conf,
+		log:                 log,
+		addresses:           convAddress,
+		configuredAddresses: addresses,
+		sslConfig:           sslConfig,
+		hostTLS:             resolveHostTLS(conf.HostTLS, addresses, convAddress),
+	}
+	if err = newPool.prepareTLS(); err != nil {
+		return nil, err
+	}
+	newPool.wireDumper = newWireDumper(newPool.conf, log)
+	newPool.stmtPrefix = clientComment(newPool.conf.ClientName, newPool.conf.ClientVersion)
+	// Start the round-robin from a random host so that pools spread their load
+	newPool.hostIndex = newPool.randIntn(len(convAddress))
+
+	// Init pool with SSL socket, unless the connections are opened on first use
+	if newPool.conf.LazyInit {
+		if newPool.conf.AdaptivePoolSize {
+			log.Warn("AdaptivePoolSize is ignored by lazily initialized pools")
+		}
+		newPool.startCleaner()
+		newPool.startAutoscaler()
+		return newPool, nil
 	}
-
-	// Init pool with SSL socket
 	if err = newPool.initPool(); err != nil {
 		return nil, err
 	}
+	if newPool.conf.AdaptivePoolSize {
+		ctx, cancel := context.WithTimeout(context.Background(), adaptPoolSizeTimeout(newPool.conf.TimeOut))
+		newPool.adaptPoolSize(ctx)
+		cancel()
+	}
 	newPool.startCleaner()
+	newPool.startAutoscaler()
 	return newPool, nil
 }
 
@@ -76,10 +125,10 @@ func (pool *ConnectionPool) initPool() error {
 
 	for i := 0; i < pool.conf.MinConnPoolSize; i++ {
 		// Simple round-robin
-		newConn := newConnection(pool.addresses[i%len(pool.addresses)])
+		newConn := pool.newConnection(pool.addresses[(pool.hostIndex+i)%len(pool.addresses)])
 
 		// Open connection to host
-		if err := newConn.open(newConn.severAddress, pool.conf.TimeOut, pool.sslConfig); err != nil {
+		if err := newConn.open(newConn.severAddress, pool.conf.TimeOut, pool.tlsConfigFor(newConn.severAddress)); err != nil {
 			// If initialization failed, clean idle queue
 			idleLen := pool.idleConnectionQueue.Len()
 			for i := 0; i < idleLen; i++ {
@@ -97,31 +146,185 @@ func (pool *ConnectionPool) initPool() error {
 // GetSession authenticates the username and password.
 // It returns a session if the authentication succeed.
 func (pool *ConnectionPool) GetSession(username, password string) (*Session, error) {
+	return pool.GetSessionWithContext(context.Background(), username, password)
+}
+
+// GetSessionWithContext authenticates the username and password like GetSession,
+// recording the acquire statistics under the caller label of the context, see WithCallerLabel.
+func (pool *ConnectionPool) GetSessionWithContext(ctx context.Context, username, password string) (*Session, error) {
 	// Get valid and usable connection
 	var conn *connection = nil
 	var err error = nil
 	const retryTimes = 3
+	label := callerLabel(ctx)
+	start := pool.conf.Clock.Now()
+	pool.acquireStats.startWait(label)
 	for i := 0; i < retryTimes; i++ {
 		conn, err = pool.getIdleConn()
 		if err == nil {
 			break
 		}
 	}
+	now := pool.conf.Clock.Now()
+	wait := now.Sub(start)
+	pool.acquireStats.endWait(label, now, wait, conn != nil, pool.isStarved(wait))
 	if conn == nil {
 		return nil, err
 	}
+	return pool.newSession(conn, username, password)
+}
+
+// Acquire authenticates a session using the Username or UsernameProvider and the Password or PasswordProvider
+// of the pool config, waiting until the pool has a free connection or the context is done.
+// The providers are called on every acquire, so that rotated credentials are used by the new sessions.
+// If the graph service rejects cached credentials, they are fetched again and the authentication is retried once.
+func (pool *ConnectionPool) Acquire(ctx context.Context) (*Session, error) {
+	session, err := pool.acquire(ctx)
+	if _, ok := err.(*authError); ok && pool.invalidateCredentials() {
+		session, err = pool.acquire(ctx)
+	}
+	return session, err
+}
+
+func (pool *ConnectionPool) acquire(ctx context.Context) (*Session, error) {
+	username, err := pool.username(ctx)
+	if err != nil {
+		return nil, err
+	}
+	password, err := pool.password(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sessions, err := pool.AcquireN(ctx, username, password, 1)
+	if err != nil {
+		return nil, err
+	}
+	return sessions[0], nil
+}
+
+// AcquireN authenticates n sessions at once using the username and password.
+// Either all n sessions are returned or none: the connections of the sessions are reserved
+// together, waiting until the pool has enough free capacity or the context is done,
+// so that workers needing several sessions can not deadlock each other.
+func (pool *ConnectionPool) AcquireN(ctx context.Context, username, password string, n int) ([]*Session, error) {
+	if n <= 0 {
+		return nil, fmt.Errorf("failed to acquire sessions: invalid number of sessions %d", n)
+	}
+	if n > pool.conf.MaxConnPoolSize {
+		return nil, fmt.Errorf("failed to acquire %d sessions: the pool capacity is %d", n, pool.conf.MaxConnPoolSize)
+	}
+	workload := workloadFrom(ctx)
+	if size, ok := pool.conf.WorkloadPartitions[workload]; ok && n > size {
+		return nil, fmt.Errorf("failed to acquire %d sessions: the partition of workload %s is %d", n, workload, size)
+	}
+
+	label := callerLabel(ctx)
+	start := pool.conf.Clock.Now()
+	pool.acquireStats.startWait(label)
+	var conns []*connection
+	for {
+		var err error
+		var released <-chan struct{}
+		conns, released, err = pool.reserveConns(n, workload)
+		if err != nil {
+			now := pool.conf.Clock.Now()
+			pool.acquireStats.endWait(label, now, now.Sub(start), false, false)
+			return nil, err
+		}
+		if conns != nil {
+			break
+		}
+		select {
+		case <-released:
+		case <-ctx.Done():
+			now := pool.conf.Clock.Now()
+			pool.acquireStats.endWait(label, now, now.Sub(start), false, true)
+			return nil, fmt.Errorf("failed to acquire %d sessions: %s", n, ctx.Err().Error())
+		}
+	}
+	now := pool.conf.Clock.Now()
+	wait := now.Sub(start)
+	pool.acquireStats.endWait(label, now, wait, true, pool.isStarved(wait))
+
+	sessions := make([]*Session, 0, n)
+	for i, conn := range conns {
+		session, err := pool.newSession(conn, username, password)
+		if err != nil {
+			for _, s := range sessions {
+				s.Release()
+			}
+			for _, c := range conns[i+1:] {
+				pool.release(c)
+			}
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+	return sessions, nil
+}
+
+// reserveConns takes n connections from the pool for the workload if it has enough capacity.
+// Otherwise it returns a channel which is closed when a connection is released to the pool.
+func (pool *ConnectionPool) reserveConns(n int, workload string) ([]*connection, <-chan struct{}, error) {
+	pool.rwLock.Lock()
+	defer pool.rwLock.Unlock()
+
+	if pool.closed {
+		return nil, nil, fmt.Errorf("failed to get connection: pool has been closed")
+	}
+	totalConn := pool.idleConnectionQueue.Len() + pool.activeConnectionQueue.Len()
+	if pool.idleConnectionQueue.Len()+pool.capacityLocked()-totalConn < n || pool.workloadFullLocked(workload, n) {
+		return nil, pool.releasedChan(), nil
+	}
+
+	conns := make([]*connection, 0, n)
+	for i := 0; i < n; i++ {
+		conn, err := pool.getIdleConnLocked()
+		if err != nil {
+			for _, c := range conns {
+				removeFromList(&pool.activeConnectionQueue, c)
+				pool.idleConnectionQueue.PushBack(c)
+			}
+			return nil, nil, err
+		}
+		conns = append(conns, conn)
+	}
+	for _, conn := range conns {
+		pool.assignWorkloadLocked(conn, workload)
+	}
+	return conns, nil, nil
+}
+
+// isStarved returns true if an acquire waiting for the given duration is a starvation
+func (pool *ConnectionPool) isStarved(wait time.Duration) bool {
+	return pool.conf.StarvationThreshold > 0 && wait > pool.conf.StarvationThreshold
+}
+
+// releasedChan returns a channel which is closed the next time a connection is released.
+// The caller must hold the lock.
+func (pool *ConnectionPool) releasedChan() <-chan struct{} {
+	if pool.releasedCh == nil {
+		pool.releasedCh = make(chan struct{})
+	}
+	return pool.releasedCh
+}
+
+// newSession authenticates on the given connection and creates a new session.
+// If the authentication fails, the connection is put back into the pool.
+func (pool *ConnectionPool) newSession(conn *connection, username, password string) (*Session, error) {
 	// Authenticate
 	resp, err := conn.authenticate(username, password)
 	if err != nil || resp.GetErrorCode() != nebula.ErrorCode_SUCCEEDED {
 		// if authentication failed, put connection back
-		pool.rwLock.Lock()
-		defer pool.rwLock.Unlock()
-		removeFromList(&pool.activeConnectionQueue, conn)
-		pool.idleConnectionQueue.PushBack(conn)
+		pool.release(conn)
 		return nil, err
 	}
 
 	sessID := resp.GetSessionID()
+	pool.rwLock.Lock()
+	pool.bindSessionLocked(conn, sessID)
+	pool.rwLock.Unlock()
+	pool.sessionEvent(SessionEvent{Kind: SessionCreated, SessionID: sessID, Host: conn.severAddress})
 	timezoneOffset := resp.GetTimeZoneOffsetSeconds()
 	timezoneName := resp.GetTimeZoneName()
 	// Create new session
@@ -131,6 +334,12 @@ func (pool *ConnectionPool) GetSession(username, password string) (*Session, err
 		connPool:     pool,
 		log:          pool.log,
 		timezoneInfo: timezoneInfo{timezoneOffset, timezoneName},
+		username:     username,
+		password:     password,
+	}
+	if err = newSession.onAcquire(pool.conf); err != nil {
+		newSession.Release()
+		return nil, fmt.Errorf("failed to initialize session, error: %s", err.Error())
 	}
 
 	return &newSession, nil
@@ -139,13 +348,20 @@ func (pool *ConnectionPool) GetSession(username, password string) (*Session, err
 func (pool *ConnectionPool) getIdleConn() (*connection, error) {
 	pool.rwLock.Lock()
 	defer pool.rwLock.Unlock()
+	return pool.getIdleConnLocked()
+}
 
+// getIdleConnLocked is getIdleConn without locking, the caller must hold the lock
+func (pool *ConnectionPool) getIdleConnLocked() (*connection, error) {
 	// Take an idle valid connection if possible
 	if pool.idleConnectionQueue.Len() > 0 {
 		var newConn *connection = nil
 		var newEle *list.Element = nil
 		for ele := pool.idleConnectionQueue.Front(); ele != nil; ele = ele.Next() {
 			// Check if connection is valid
+			if pool.drained[ele.Value.(*connection).severAddress] {
+				continue
+			}
 			if res := ele.Value.(*connection).ping(); res {
 				newConn = ele.Value.(*connection)
 				newEle = ele
@@ -173,15 +389,26 @@ func (pool *ConnectionPool) release(conn *connection) {
 	defer pool.rwLock.Unlock()
 	// Remove connection from active queue and add into idle queue
 	removeFromList(&pool.activeConnectionQueue, conn)
+	pool.unassignWorkloadLocked(conn)
+	pool.bindSessionLocked(conn, 0)
 	conn.release()
-	pool.idleConnectionQueue.PushBack(conn)
+	if pool.drained[conn.severAddress] || conn.broken {
+		conn.close()
+	} else {
+		pool.idleConnectionQueue.PushBack(conn)
+	}
+	// Wake up the callers waiting for a free connection
+	if pool.releasedCh != nil {
+		close(pool.releasedCh)
+		pool.releasedCh = nil
+	}
 }
 
 // Ping checks avaliability of host
 func (pool *ConnectionPool) Ping(host HostAddress, timeout time.Duration) error {
-	newConn := newConnection(host)
+	newConn := pool.newConnection(host)
 	// Open connection to host
-	if err := newConn.open(newConn.severAddress, timeout, pool.sslConfig); err != nil {
+	if err := newConn.open(newConn.severAddress, timeout, pool.tlsConfigFor(newConn.severAddress)); err != nil {
 		return err
 	}
 	newConn.close()
@@ -205,9 +432,21 @@ func (pool *ConnectionPool) Close() {
 	}
 
 	pool.closed = true
+	pool.stopAutoscalerLocked()
 	if pool.cleanerChan != nil {
 		close(pool.cleanerChan)
 	}
+	if pool.releasedCh != nil {
+		close(pool.releasedCh)
+		pool.releasedCh = nil
+	}
+}
+
+// IsClosed returns true if the pool has been closed
+func (pool *ConnectionPool) IsClosed() bool {
+	pool.rwLock.RLock()
+	defer pool.rwLock.RUnlock()
+	return pool.closed
 }
 
 func (pool *ConnectionPool) getActiveConnCount() int {
@@ -218,13 +457,38 @@ func (pool *ConnectionPool) getIdleConnCount() int {
 	return pool.idleConnectionQueue.Len()
 }
 
+// newConnection creates a connection to the given host using the settings of the pool
+func (pool *ConnectionPool) newConnection(host HostAddress) *connection {
+	conn := newConnectionWithClock(host, pool.conf.Clock)
+	conn.wireDumper = pool.wireDumper
+	conn.dial = pool.conf.Dialer
+	return conn
+}
+
+// randMu guards the random sources of the pools, a *rand.Rand given to WithRand may be shared by several pools
+var randMu sync.Mutex
+
+// randIntn returns a random number in [0, n) using the random source of the pool
+func (pool *ConnectionPool) randIntn(n int) int {
+	randMu.Lock()
+	defer randMu.Unlock()
+	return pool.conf.Rand.Intn(n)
+}
+
 // Get a valid host (round robin)
 func (pool *ConnectionPool) getHost() HostAddress {
-	if pool.hostIndex == len(pool.addresses) {
-		pool.hostIndex = 0
+	var host HostAddress
+	// skip the drained hosts, DrainHost keeps at least one host
+	for i := 0; i < len(pool.addresses); i++ {
+		if pool.hostIndex == len(pool.addresses) {
+			pool.hostIndex = 0
+		}
+		host = pool.addresses[pool.hostIndex]
+		pool.hostIndex++
+		if !pool.drained[host] {
+			break
+		}
 	}
-	host := pool.addresses[pool.hostIndex]
-	pool.hostIndex++
 	return host
 }
 
@@ -232,9 +496,13 @@ func (pool *ConnectionPool) getHost() HostAddress {
 func (pool *ConnectionPool) newConnToHost() (*connection, error) {
 	// Get a valid host (round robin)
 	host := pool.getHost()
-	newConn := newConnection(host)
+	newConn := pool.newConnection(host)
 	// Open connection to host
-	if err := newConn.open(newConn.severAddress, pool.conf.TimeOut, pool.sslConfig); err != nil {
+	if err := newConn.open(newConn.severAddress, pool.conf.TimeOut, pool.tlsConfigFor(newConn.severAddress)); err != nil {
+		if pool.conf.LazyInit {
+			return nil, fmt.Errorf("failed to open connection to %s:%d of lazily initialized pool, error: %s",
+				host.Host, host.Port, err.Error())
+		}
 		return nil, err
 	}
 	// Add connection to active queue
@@ -256,7 +524,7 @@ func removeFromList(l *list.List, conn *connection) {
 func (pool *ConnectionPool) createConnection() (*connection, error) {
 	totalConn := pool.idleConnectionQueue.Len() + pool.activeConnectionQueue.Len()
 	// If no idle avaliable and the number of total connection reaches the max pool size, return error/wait for timeout
-	if totalConn >= pool.conf.MaxConnPoolSize {
+	if totalConn >= pool.capacityLocked() {
 		return nil, fmt.Errorf("failed to get connection: No valid connection" +
 			" in the idle queue and connection number has reached the pool capacity")
 	}
@@ -285,11 +553,11 @@ func (pool *ConnectionPool) connectionCleaner() {
 	if d < minInterval {
 		d = minInterval
 	}
-	t := time.NewTimer(d)
+	t := pool.conf.Clock.NewTimer(d)
 
 	for {
 		select {
-		case <-t.C:
+		case <-t.C():
 		case <-pool.cleanerChan: // pool was closed.
 		}
 
@@ -314,7 +582,7 @@ func (pool *ConnectionPool) connectionCleaner() {
 func (pool *ConnectionPool) timeoutConnectionList() (closing []*connection) {
 
 	if pool.conf.IdleTime > 0 {
-		expiredSince := time.Now().Add(-pool.conf.IdleTime)
+		expiredSince := pool.conf.Clock.Now().Add(-pool.conf.IdleTime)
 		var newEle *list.Element = nil
 
 		maxCleanSize := pool.idleConnectionQueue.Len() + pool.activeConnectionQueue.Len() - pool.conf.MinConnPoolSize
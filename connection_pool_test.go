@@ -0,0 +1,77 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRandSeedHostSelection(t *testing.T) {
+	addresses := []HostAddress{{"127.0.0.1", 3699}, {"127.0.0.1", 3700}, {"127.0.0.1", 3701}}
+	newPool := func() *ConnectionPool {
+		conf := NewPoolConf(WithRandSeed(42))
+		conf.validateConf(DefaultLogger{})
+		pool := &ConnectionPool{conf: conf, addresses: addresses}
+		pool.hostIndex = pool.randIntn(len(addresses))
+		return pool
+	}
+	pool1, pool2 := newPool(), newPool()
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, pool1.getHost(), pool2.getHost())
+		assert.Equal(t, pool1.randIntn(100), pool2.randIntn(100))
+	}
+}
+
+func TestRandSharedByPools(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+	newPool := func() *ConnectionPool {
+		conf := NewPoolConf(WithRand(r))
+		conf.validateConf(DefaultLogger{})
+		return &ConnectionPool{conf: conf}
+	}
+	pools := []*ConnectionPool{newPool(), newPool()}
+	var wg sync.WaitGroup
+	for _, pool := range pools {
+		wg.Add(1)
+		go func(pool *ConnectionPool) {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				if n := pool.randIntn(10); n < 0 || n >= 10 {
+					t.Errorf("random number %d out of range", n)
+				}
+			}
+		}(pool)
+	}
+	wg.Wait()
+}
+
+func TestLazyInit(t *testing.T) {
+	// nothing listens on port 1
+	addresses := []HostAddress{{"127.0.0.1", 1}}
+	conf := NewPoolConf(WithLazyInit())
+	conf.TimeOut = time.Second
+	conf.MinConnPoolSize = 2
+	pool, err := NewConnectionPool(addresses, conf, DefaultLogger{})
+	assert.Nil(t, err)
+	defer pool.Close()
+	assert.Equal(t, 0, pool.getIdleConnCount())
+
+	_, err = pool.GetSession("root", "nebula")
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "failed to open connection to 127.0.0.1:1 of lazily initialized pool")
+	}
+
+	_, err = NewConnectionPool(addresses, NewPoolConf(), DefaultLogger{})
+	assert.NotNil(t, err)
+}
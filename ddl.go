@@ -0,0 +1,93 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/vesoft-inc/nebula-go/v3/nebula"
+)
+
+const (
+	// DefaultDDLRetryBackoff is the first backoff of ExecuteDDL, it is doubled after every retry
+	DefaultDDLRetryBackoff = 200 * time.Millisecond
+	// DefaultDDLRetryMaxBackoff is the max backoff of ExecuteDDL
+	DefaultDDLRetryMaxBackoff = 5 * time.Second
+	// DefaultDDLTimeout is the overall deadline of ExecuteDDL when the context has none
+	DefaultDDLTimeout = time.Minute
+)
+
+// transient error messages of the meta service, which are reported by the graph service as execution errors
+var transientDDLMessages = []string{
+	"leader changed",
+	"leaderchanged",
+	"e_leader_changed",
+	"balancer is running",
+	"balance in progress",
+	"e_balancer_running",
+}
+
+// ExecuteDDL executes a schema or administration statement and retries it with an exponential backoff
+// and jitter while it fails because the leader of the meta service changed or a balance is running,
+// which happens during maintenance windows. The retries stop when the context is done,
+// or after DefaultDDLTimeout if the context has no deadline. The statement must be idempotent,
+// e.g. CREATE TAG IF NOT EXISTS, as it may have been applied before the error was reported.
+// The schema cache of the pool is invalidated once the statement succeeds.
+func (session *Session) ExecuteDDL(ctx context.Context, stmt string) (*ResultSet, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, DefaultDDLTimeout)
+		defer cancel()
+	}
+	backoff := DefaultDDLRetryBackoff
+	for {
+		resp, err := session.ExecuteWithContext(ctx, stmt, nil)
+		if err != nil {
+			return nil, err
+		}
+		if resp.IsSucceed() {
+			// the statement may have changed any schema cached by the pool
+			session.connPool.InvalidateSchema("")
+			return resp, nil
+		}
+		if !isTransientDDLError(resp) {
+			return nil, fmt.Errorf("failed to execute %s: %s", stmt, resp.GetErrorMsg())
+		}
+		// full jitter, so that the clients retrying after the same leader change spread their retries
+		wait := time.Duration(session.connPool.randIntn(int(backoff))) + 1
+		timer := session.connPool.conf.Clock.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, fmt.Errorf("failed to execute %s: %s, last error: %s", stmt, ctx.Err().Error(), resp.GetErrorMsg())
+		case <-timer.C():
+		}
+		if backoff *= 2; backoff > DefaultDDLRetryMaxBackoff {
+			backoff = DefaultDDLRetryMaxBackoff
+		}
+	}
+}
+
+// isTransientDDLError returns true if the statement failed because of a leader change or a running balance
+func isTransientDDLError(resp *ResultSet) bool {
+	switch nebula.ErrorCode(resp.GetErrorCode()) {
+	case nebula.ErrorCode_E_LEADER_CHANGED, nebula.ErrorCode_E_BALANCER_RUNNING:
+		return true
+	}
+	msg := strings.ToLower(resp.GetErrorMsg())
+	for _, m := range transientDDLMessages {
+		if strings.Contains(msg, m) {
+			return true
+		}
+	}
+	return false
+}
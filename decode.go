@@ -0,0 +1,173 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// DecodeMode is the way a ResultDecoder converts the values of a result set
+type DecodeMode int
+
+const (
+	// DecodeStrict converts a value only if its type is the expected one
+	DecodeStrict DecodeMode = iota
+	// DecodeLenient also converts strings to numbers and booleans, numbers and booleans to strings,
+	// integral floats to ints, ints to floats and 0 and 1 to booleans
+	DecodeLenient
+)
+
+// maxConversionErrorValue is the max length of the value rendered in a ConversionError
+const maxConversionErrorValue = 64
+
+// ConversionError is a value of a result set which can not be converted to the expected type
+type ConversionError struct {
+	// The expected type, e.g. "int"
+	Expected string
+	// The type of the value, as returned by ValueWrapper.GetType
+	Actual string
+	// The column and the index of the row of the value
+	Column string
+	Row    int
+	// The value, truncated
+	Value string
+}
+
+func (e *ConversionError) Error() string {
+	return fmt.Sprintf("failed to convert column %s of row %d: expected %s, got %s %s",
+		e.Column, e.Row, e.Expected, e.Actual, e.Value)
+}
+
+// ResultDecoder converts the values of a result set, returning a *ConversionError
+// locating the value when it can not be converted
+type ResultDecoder struct {
+	res  ResultSet
+	mode DecodeMode
+}
+
+// Decoder returns a decoder of the values of the result set
+func (res ResultSet) Decoder(mode DecodeMode) *ResultDecoder {
+	return &ResultDecoder{res: res, mode: mode}
+}
+
+// Int returns the value of the column in the row as an int
+func (d *ResultDecoder) Int(row int, col string) (int64, error) {
+	v, err := d.value(row, col)
+	if err != nil {
+		return 0, err
+	}
+	if v.IsInt() {
+		return v.value.GetIVal(), nil
+	}
+	if d.mode == DecodeLenient {
+		switch {
+		case v.IsString():
+			if i, err := strconv.ParseInt(strings.TrimSpace(string(v.value.GetSVal())), 10, 64); err == nil {
+				return i, nil
+			}
+		case v.IsFloat():
+			if f := v.value.GetFVal(); f == math.Trunc(f) && f >= math.MinInt64 && f < math.MaxInt64 {
+				return int64(f), nil
+			}
+		case v.IsBool():
+			if v.value.GetBVal() {
+				return 1, nil
+			}
+			return 0, nil
+		}
+	}
+	return 0, d.conversionError("int", row, col, v)
+}
+
+// Float returns the value of the column in the row as a float
+func (d *ResultDecoder) Float(row int, col string) (float64, error) {
+	v, err := d.value(row, col)
+	if err != nil {
+		return 0, err
+	}
+	if v.IsFloat() {
+		return v.value.GetFVal(), nil
+	}
+	if d.mode == DecodeLenient {
+		switch {
+		case v.IsInt():
+			return float64(v.value.GetIVal()), nil
+		case v.IsString():
+			if f, err := strconv.ParseFloat(strings.TrimSpace(string(v.value.GetSVal())), 64); err == nil {
+				return f, nil
+			}
+		}
+	}
+	return 0, d.conversionError("float", row, col, v)
+}
+
+// String returns the value of the column in the row as a string
+func (d *ResultDecoder) String(row int, col string) (string, error) {
+	v, err := d.value(row, col)
+	if err != nil {
+		return "", err
+	}
+	if v.IsString() {
+		return string(v.value.GetSVal()), nil
+	}
+	if d.mode == DecodeLenient {
+		switch {
+		case v.IsInt():
+			return strconv.FormatInt(v.value.GetIVal(), 10), nil
+		case v.IsFloat():
+			return strconv.FormatFloat(v.value.GetFVal(), 'g', -1, 64), nil
+		case v.IsBool():
+			return strconv.FormatBool(v.value.GetBVal()), nil
+		}
+	}
+	return "", d.conversionError("string", row, col, v)
+}
+
+// Bool returns the value of the column in the row as a bool
+func (d *ResultDecoder) Bool(row int, col string) (bool, error) {
+	v, err := d.value(row, col)
+	if err != nil {
+		return false, err
+	}
+	if v.IsBool() {
+		return v.value.GetBVal(), nil
+	}
+	if d.mode == DecodeLenient {
+		switch {
+		case v.IsString():
+			if b, err := strconv.ParseBool(strings.TrimSpace(string(v.value.GetSVal()))); err == nil {
+				return b, nil
+			}
+		case v.IsInt():
+			if i := v.value.GetIVal(); i == 0 || i == 1 {
+				return i == 1, nil
+			}
+		}
+	}
+	return false, d.conversionError("bool", row, col, v)
+}
+
+func (d *ResultDecoder) value(row int, col string) (*ValueWrapper, error) {
+	record, err := d.res.GetRowValuesByIndex(row)
+	if err != nil {
+		return nil, err
+	}
+	return record.GetValueByColName(col)
+}
+
+func (d *ResultDecoder) conversionError(expected string, row int, col string, v *ValueWrapper) error {
+	value := v.String()
+	if len(value) > maxConversionErrorValue {
+		value = value[:maxConversionErrorValue] + "..."
+	}
+	return &ConversionError{Expected: expected, Actual: v.GetType(), Column: col, Row: row, Value: value}
+}
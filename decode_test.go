@@ -0,0 +1,70 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResultDecoder(t *testing.T) {
+	rs := newTestResultSet(t, []string{"a", "b", "c", "d"},
+		[]interface{}{1, "42", 2.0, "true"},
+		[]interface{}{nil, strings.Repeat("x", 100), 2.5, 1},
+	)
+
+	strict := rs.Decoder(DecodeStrict)
+	i, err := strict.Int(0, "a")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), i)
+	_, err = strict.Int(0, "b")
+	if assert.IsType(t, &ConversionError{}, err) {
+		e := err.(*ConversionError)
+		assert.Equal(t, ConversionError{Expected: "int", Actual: "string", Column: "b", Row: 0, Value: `"42"`}, *e)
+		assert.Equal(t, `failed to convert column b of row 0: expected int, got string "42"`, e.Error())
+	}
+	_, err = strict.Int(1, "a")
+	assert.Contains(t, err.Error(), "expected int, got null")
+	_, err = strict.Int(2, "a")
+	assert.NotNil(t, err)
+	_, err = strict.Int(0, "e")
+	assert.NotNil(t, err)
+	_, err = strict.String(1, "c")
+	assert.Contains(t, err.Error(), "expected string, got float 2.5")
+	_, err = strict.Int(1, "b")
+	assert.Contains(t, err.Error(), "...")
+
+	lenient := rs.Decoder(DecodeLenient)
+	i, err = lenient.Int(0, "b")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(42), i)
+	i, err = lenient.Int(0, "c")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), i)
+	_, err = lenient.Int(1, "c")
+	assert.NotNil(t, err)
+	f, err := lenient.Float(0, "a")
+	assert.Nil(t, err)
+	assert.Equal(t, 1.0, f)
+	s, err := lenient.String(1, "c")
+	assert.Nil(t, err)
+	assert.Equal(t, "2.5", s)
+	b, err := lenient.Bool(0, "d")
+	assert.Nil(t, err)
+	assert.True(t, b)
+	b, err = lenient.Bool(1, "d")
+	assert.Nil(t, err)
+	assert.True(t, b)
+	_, err = lenient.Bool(1, "b")
+	assert.NotNil(t, err)
+	_, err = lenient.String(1, "a")
+	assert.Contains(t, err.Error(), "expected string, got null")
+}
@@ -0,0 +1,106 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/facebook/fbthrift/thrift/lib/go/thrift"
+)
+
+// DialFunc opens the network connections of a pool, e.g. through a local proxy
+type DialFunc func(ctx context.Context, network, address string) (net.Conn, error)
+
+// WithDialer sets the function opening the network connections of the pool
+func WithDialer(dial DialFunc) PoolConfOption {
+	return func(conf *PoolConfig) {
+		conf.Dialer = dial
+	}
+}
+
+// UnixSocketHost returns the address of a graph service listening on a unix domain socket
+func UnixSocketHost(path string) HostAddress {
+	return HostAddress{Host: path}
+}
+
+// IsUnixSocket returns true if the address is the absolute path of a unix domain socket, without port
+func (h HostAddress) IsUnixSocket() bool {
+	return h.Port == 0 && strings.HasPrefix(h.Host, "/")
+}
+
+// dialAddress returns the network and the address to dial
+func (h HostAddress) dialAddress() (string, string) {
+	if h.IsUnixSocket() {
+		return "unix", h.Host
+	}
+	return "tcp", net.JoinHostPort(h.Host, strconv.Itoa(h.Port))
+}
+
+// dialedSocket is a socket whose connection is opened by a DialFunc
+type dialedSocket struct {
+	*thrift.Socket
+	host      HostAddress
+	timeout   time.Duration
+	sslConfig *tls.Config
+	dial      DialFunc
+}
+
+func newDialedSocket(host HostAddress, timeout time.Duration, sslConfig *tls.Config, dial DialFunc) *dialedSocket {
+	if dial == nil {
+		dial = (&net.Dialer{}).DialContext
+	}
+	return &dialedSocket{Socket: &thrift.Socket{}, host: host, timeout: timeout, sslConfig: sslConfig, dial: dial}
+}
+
+// Open dials the host and performs the TLS handshake if TLS is enabled
+func (s *dialedSocket) Open() error {
+	if s.IsOpen() {
+		return thrift.NewTransportException(thrift.ALREADY_OPEN, "Socket already connected.")
+	}
+	ctx := context.Background()
+	if s.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.timeout)
+		defer cancel()
+	}
+	network, address := s.host.dialAddress()
+	conn, err := s.dial(ctx, network, address)
+	if err != nil {
+		return thrift.NewTransportException(thrift.NOT_OPEN, err.Error())
+	}
+	if s.sslConfig != nil {
+		config := s.sslConfig
+		if config.ServerName == "" && !s.host.IsUnixSocket() {
+			config = config.Clone()
+			config.ServerName = s.host.Host
+		}
+		tlsConn := tls.Client(conn, config)
+		if deadline, ok := ctx.Deadline(); ok {
+			tlsConn.SetDeadline(deadline)
+		}
+		if err := tlsConn.Handshake(); err != nil {
+			conn.Close()
+			return thrift.NewTransportException(thrift.NOT_OPEN, err.Error())
+		}
+		tlsConn.SetDeadline(time.Time{})
+		conn = tlsConn
+	}
+	socket, err := thrift.NewSocket(thrift.SocketConn(conn), thrift.SocketTimeout(s.timeout))
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	s.Socket = socket
+	return nil
+}
@@ -0,0 +1,51 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDialer(t *testing.T) {
+	var dialed []string
+	dial := func(ctx context.Context, network, address string) (net.Conn, error) {
+		dialed = append(dialed, network+" "+address)
+		return nil, fmt.Errorf("refused")
+	}
+	conf := GetDefaultConf()
+	WithDialer(dial)(&conf)
+
+	_, err := NewConnectionPool([]HostAddress{UnixSocketHost("/var/run/graphd.sock")}, conf, DefaultLogger{})
+	assert.NotNil(t, err)
+	_, err = NewConnectionPool([]HostAddress{{Host: "127.0.0.1", Port: 9669}}, conf, DefaultLogger{})
+	assert.NotNil(t, err)
+	// the name is passed to the dialer, which may be the only one able to resolve it
+	_, err = NewConnectionPool([]HostAddress{{Host: "graphd.invalid", Port: 9669}}, conf, DefaultLogger{})
+	assert.EqualError(t, err, "failed to open connection, error: failed to open transport, error: refused ")
+	assert.Equal(t, []string{"unix /var/run/graphd.sock", "tcp 127.0.0.1:9669", "tcp graphd.invalid:9669"}, dialed)
+
+	assert.True(t, UnixSocketHost("/var/run/graphd.sock").IsUnixSocket())
+	assert.False(t, UnixSocketHost("graphd.sock").IsUnixSocket())
+	assert.False(t, HostAddress{Host: "/graphd", Port: 9669}.IsUnixSocket())
+}
+
+func TestDialerUnixSocket(t *testing.T) {
+	conf := GetDefaultConf()
+	conf.LazyInit = true
+	pool, err := NewConnectionPool([]HostAddress{UnixSocketHost("/nonexistent/graphd.sock")}, conf, DefaultLogger{})
+	assert.Nil(t, err)
+	defer pool.Close()
+	_, err = pool.GetSession("root", "nebula")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,87 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"container/list"
+	"fmt"
+)
+
+// DrainHost stops opening connections to the host, e.g. before its graph service is patched.
+// Its idle connections are closed at once and its active ones when they are released,
+// the sessions using them are not interrupted. The last host of the pool which is not drained can not be drained.
+// The host may be given as it is passed to the pool or by its resolved address.
+func (pool *ConnectionPool) DrainHost(host HostAddress) error {
+	pool.rwLock.Lock()
+	defer pool.rwLock.Unlock()
+	addr, ok := pool.resolveHostLocked(host)
+	if !ok {
+		return fmt.Errorf("failed to drain %s:%d: not a host of the pool", host.Host, host.Port)
+	}
+	if len(pool.drained) == 0 {
+		pool.drained = make(map[HostAddress]bool)
+	}
+	if !pool.drained[addr] && len(pool.drained) == len(pool.addresses)-1 {
+		return fmt.Errorf("failed to drain %s:%d: it is the last host of the pool which is not drained", host.Host, host.Port)
+	}
+	pool.drained[addr] = true
+
+	var next *list.Element
+	for ele := pool.idleConnectionQueue.Front(); ele != nil; ele = next {
+		next = ele.Next()
+		if conn := ele.Value.(*connection); conn.severAddress == addr {
+			conn.close()
+			pool.idleConnectionQueue.Remove(ele)
+		}
+	}
+	return nil
+}
+
+// UndrainHost lets the pool open connections to a drained host again
+func (pool *ConnectionPool) UndrainHost(host HostAddress) error {
+	pool.rwLock.Lock()
+	defer pool.rwLock.Unlock()
+	addr, ok := pool.resolveHostLocked(host)
+	if !ok {
+		return fmt.Errorf("failed to undrain %s:%d: not a host of the pool", host.Host, host.Port)
+	}
+	delete(pool.drained, addr)
+	return nil
+}
+
+// DrainedHosts returns the resolved addresses of the drained hosts in the order of the addresses of the pool
+func (pool *ConnectionPool) DrainedHosts() []HostAddress {
+	pool.rwLock.RLock()
+	defer pool.rwLock.RUnlock()
+	var hosts []HostAddress
+	for _, host := range pool.addresses {
+		if pool.drained[host] {
+			hosts = append(hosts, host)
+		}
+	}
+	return hosts
+}
+
+// resolveHostLocked returns the address of the pool of the host, given by its resolved address
+// or as it was passed to the pool, like the overrides of WithHostTLS
+func (pool *ConnectionPool) resolveHostLocked(host HostAddress) (HostAddress, bool) {
+	for _, addr := range pool.addresses {
+		if addr == host {
+			return addr, true
+		}
+	}
+	if len(pool.configuredAddresses) == len(pool.addresses) {
+		for i, configured := range pool.configuredAddresses {
+			if configured == host {
+				return pool.addresses[i], true
+			}
+		}
+	}
+	return HostAddress{}, false
+}
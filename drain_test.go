@@ -0,0 +1,77 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"testing"
+
+	"github.com/facebook/fbthrift/thrift/lib/go/thrift"
+	"github.com/stretchr/testify/assert"
+	"github.com/vesoft-inc/nebula-go/v3/nebula/graph"
+)
+
+func newDrainTestConn(host HostAddress) *connection {
+	conn := newConnectionWithClock(host, realClock{})
+	conn.graph = graph.NewGraphServiceClientFactory(thrift.NewMemoryBuffer(), thrift.NewBinaryProtocolFactoryDefault())
+	return conn
+}
+
+func TestDrainHost(t *testing.T) {
+	host1, host2 := HostAddress{Host: "10.0.0.1", Port: 9669}, HostAddress{Host: "10.0.0.2", Port: 9669}
+	pool := &ConnectionPool{addresses: []HostAddress{host1, host2}, conf: PoolConfig{MaxConnPoolSize: 4}}
+	idle1, idle2, active1 := newDrainTestConn(host1), newDrainTestConn(host2), newDrainTestConn(host1)
+	pool.idleConnectionQueue.PushBack(idle1)
+	pool.idleConnectionQueue.PushBack(idle2)
+	pool.activeConnectionQueue.PushBack(active1)
+
+	assert.EqualError(t, pool.DrainHost(HostAddress{Host: "10.0.0.3", Port: 9669}),
+		"failed to drain 10.0.0.3:9669: not a host of the pool")
+	assert.Nil(t, pool.DrainHost(host1))
+	assert.Nil(t, pool.DrainHost(host1))
+	assert.EqualError(t, pool.DrainHost(host2),
+		"failed to drain 10.0.0.2:9669: it is the last host of the pool which is not drained")
+	assert.Equal(t, []HostAddress{host1}, pool.DrainedHosts())
+
+	// the idle connection to the drained host is closed, the active one when it is released
+	assert.Equal(t, 1, pool.getIdleConnCount())
+	assert.Equal(t, idle2, pool.idleConnectionQueue.Front().Value)
+	pool.release(active1)
+	assert.Equal(t, 1, pool.getIdleConnCount())
+	assert.Equal(t, 0, pool.getActiveConnCount())
+
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, host2, pool.getHost())
+	}
+
+	assert.Nil(t, pool.UndrainHost(host1))
+	assert.Empty(t, pool.DrainedHosts())
+	assert.ElementsMatch(t, []HostAddress{host1, host2}, []HostAddress{pool.getHost(), pool.getHost()})
+}
+
+func TestDrainHostByName(t *testing.T) {
+	graphd1, graphd2 := HostAddress{Host: "graphd1", Port: 9669}, HostAddress{Host: "graphd2", Port: 9669}
+	host1, host2 := HostAddress{Host: "10.0.0.1", Port: 9669}, HostAddress{Host: "10.0.0.2", Port: 9669}
+	pool := &ConnectionPool{
+		addresses:           []HostAddress{host1, host2},
+		configuredAddresses: []HostAddress{graphd1, graphd2},
+		conf:                PoolConfig{MaxConnPoolSize: 4},
+	}
+	idle1 := newDrainTestConn(host1)
+	pool.idleConnectionQueue.PushBack(idle1)
+
+	assert.Nil(t, pool.DrainHost(graphd1))
+	assert.Equal(t, []HostAddress{host1}, pool.DrainedHosts())
+	assert.Equal(t, 0, pool.getIdleConnCount())
+	assert.EqualError(t, pool.DrainHost(graphd2),
+		"failed to drain graphd2:9669: it is the last host of the pool which is not drained")
+	assert.Nil(t, pool.UndrainHost(graphd1))
+	assert.Empty(t, pool.DrainedHosts())
+	assert.EqualError(t, pool.DrainHost(HostAddress{Host: "graphd3", Port: 9669}),
+		"failed to drain graphd3:9669: not a host of the pool")
+}
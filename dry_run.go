@@ -0,0 +1,127 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/vesoft-inc/nebula-go/v3/nebula"
+	"github.com/vesoft-inc/nebula-go/v3/nebula/graph"
+)
+
+// DryRunHook receives the write statements rendered in dry-run mode instead of the graph service
+type DryRunHook func(stmt string, params map[string]interface{}) error
+
+type dryRunKey struct{}
+
+// WithDryRun returns a context whose writes are rendered to the hook without being executed,
+// e.g. to preview a migration or an ingest. It applies to ExecuteWrite and to the helpers built on it,
+// such as the Save and Delete of the repositories and WriteScheduler, and to ExecuteBatch.
+// The reads, e.g. of the vid type of a space, and the other executions are not affected.
+func WithDryRun(ctx context.Context, hook DryRunHook) context.Context {
+	return context.WithValue(ctx, dryRunKey{}, hook)
+}
+
+// dryRunFrom returns the dry-run hook of the context, nil if it has none
+func dryRunFrom(ctx context.Context) DryRunHook {
+	if ctx != nil {
+		if hook, ok := ctx.Value(dryRunKey{}).(DryRunHook); ok {
+			return hook
+		}
+	}
+	return nil
+}
+
+// WithDryRunHook renders the writes of the pool to the hook, see WithDryRun for the executions it applies to
+func WithDryRunHook(hook DryRunHook) PoolConfOption {
+	return func(conf *PoolConfig) {
+		conf.DryRun = hook
+	}
+}
+
+// dryRunHook returns the dry-run hook of the context or of the pool config, nil if the writes must be executed
+func (pool *ConnectionPool) dryRunHook(ctx context.Context) DryRunHook {
+	if hook := dryRunFrom(ctx); hook != nil {
+		return hook
+	}
+	return pool.conf.DryRun
+}
+
+// DryRunWriter returns a hook writing every statement to w terminated by ";" and preceded by its parameters
+// as :param commands, so that the output can be reviewed and replayed with nebula-console
+func DryRunWriter(w io.Writer) DryRunHook {
+	var mu sync.Mutex
+	return func(stmt string, params map[string]interface{}) error {
+		names := make([]string, 0, len(params))
+		for name := range params {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		var sb strings.Builder
+		for _, name := range names {
+			value, err := FormatValue(params[name])
+			if err != nil {
+				return fmt.Errorf("failed to render parameter %s: %s", name, err.Error())
+			}
+			fmt.Fprintf(&sb, ":param %s => %s\n", name, value)
+		}
+		sb.WriteString(strings.TrimRight(strings.TrimSpace(stmt), ";"))
+		sb.WriteString(";\n")
+
+		mu.Lock()
+		defer mu.Unlock()
+		if _, err := io.WriteString(w, sb.String()); err != nil {
+			return fmt.Errorf("failed to render statement: %s", err.Error())
+		}
+		return nil
+	}
+}
+
+// ExecuteWrite executes the write statement like ExecuteWithContext, unless the context or the pool config
+// has a dry-run hook: the statement is then rendered to the hook and an empty successful result is returned.
+func (session *Session) ExecuteWrite(ctx context.Context, stmt string, params map[string]interface{},
+	opts ...ExecOption) (*ResultSet, error) {
+	hook := session.connPool.dryRunHook(ctx)
+	if hook == nil {
+		return session.ExecuteWithContext(ctx, stmt, params, opts...)
+	}
+	return dryRun(hook, stmt, params, session.timezoneInfo)
+}
+
+// dryRun renders the statement to the hook and returns an empty successful result
+func dryRun(hook DryRunHook, stmt string, params map[string]interface{}, timezone timezoneInfo) (*ResultSet, error) {
+	if err := hook(stmt, params); err != nil {
+		return nil, err
+	}
+	return genResultSet(&graph.ExecutionResponse{ErrorCode: nebula.ErrorCode_SUCCEEDED}, timezone)
+}
+
+// dryRunExecutor is an Executor rendering the statements to the hook, it needs no session
+type dryRunExecutor struct {
+	hook DryRunHook
+}
+
+func (e dryRunExecutor) Execute(stmt string, opts ...ExecOption) (*ResultSet, error) {
+	return e.ExecuteWithContext(context.Background(), stmt, nil, opts...)
+}
+
+func (e dryRunExecutor) ExecuteWithParameter(stmt string, params map[string]interface{},
+	opts ...ExecOption) (*ResultSet, error) {
+	return e.ExecuteWithContext(context.Background(), stmt, params, opts...)
+}
+
+func (e dryRunExecutor) ExecuteWithContext(ctx context.Context, stmt string, params map[string]interface{},
+	opts ...ExecOption) (*ResultSet, error) {
+	return dryRun(e.hook, stmt, params, timezoneInfo{})
+}
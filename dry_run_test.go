@@ -0,0 +1,99 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDryRunWriter(t *testing.T) {
+	var buf bytes.Buffer
+	hook := DryRunWriter(&buf)
+	assert.Nil(t, hook("INSERT VERTEX player(name, age) VALUES $id:($name, $age);",
+		map[string]interface{}{"id": "p1", "name": "Tim", "age": 42}))
+	assert.Nil(t, hook("DELETE VERTEX 'p2' WITH EDGE", nil))
+	assert.Equal(t, ":param age => 42\n:param id => \"p1\"\n:param name => \"Tim\"\n"+
+		"INSERT VERTEX player(name, age) VALUES $id:($name, $age);\nDELETE VERTEX 'p2' WITH EDGE;\n", buf.String())
+
+	assert.EqualError(t, hook("YIELD $x", map[string]interface{}{"x": struct{}{}}),
+		"failed to render parameter x: failed to format value of type struct {} as a literal")
+}
+
+func TestExecuteWriteDryRun(t *testing.T) {
+	var executed, rendered []string
+	record := func(stmt string, params map[string]interface{}) error {
+		rendered = append(rendered, stmt)
+		return nil
+	}
+	conf := NewPoolConf(WithInterceptors(func(ctx context.Context, stmt string, p map[string]interface{}, invoker Invoker) (*ResultSet, error) {
+		executed = append(executed, stmt)
+		return &ResultSet{}, nil
+	}))
+	session := &Session{connPool: &ConnectionPool{conf: conf}}
+
+	resp, err := session.ExecuteWrite(WithDryRun(context.Background(), record), "INSERT VERTEX player() VALUES 'p1':()", nil)
+	assert.Nil(t, err)
+	assert.True(t, resp.IsSucceed())
+	assert.Equal(t, 0, resp.GetRowSize())
+	_, err = session.ExecuteWrite(context.Background(), "INSERT VERTEX player() VALUES 'p2':()", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"INSERT VERTEX player() VALUES 'p1':()"}, rendered)
+	assert.Equal(t, []string{"INSERT VERTEX player() VALUES 'p2':()"}, executed)
+
+	// the hook of the pool config renders every write, not the other executions
+	session.connPool.conf.DryRun = record
+	_, err = session.ExecuteWrite(context.Background(), "DELETE VERTEX 'p1'", nil)
+	assert.Nil(t, err)
+	_, err = session.ExecuteWithContext(context.Background(), "YIELD 1", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"INSERT VERTEX player() VALUES 'p1':()", "DELETE VERTEX 'p1'"}, rendered)
+	assert.Equal(t, []string{"INSERT VERTEX player() VALUES 'p2':()", "YIELD 1"}, executed)
+}
+
+func TestWriteSchedulerDryRun(t *testing.T) {
+	recorder := &execRecorder{}
+	s := newWriteScheduler(NewManualClock(time.Now()), 1, time.Second, 3, 0, recorder.exec)
+	var buf bytes.Buffer
+	ctx := WithDryRun(context.Background(), DryRunWriter(&buf))
+	assert.Nil(t, s.Write(ctx, StringVID("p1"), "UPDATE VERTEX ON player 'p1' SET age = 42", nil))
+	s.Close()
+	assert.Equal(t, "UPDATE VERTEX ON player 'p1' SET age = 42;\n", buf.String())
+	assert.Empty(t, recorder.batches)
+
+	// the batches are rendered with the hook of the pool config
+	conf := NewPoolConf(WithDryRunHook(DryRunWriter(&buf)))
+	conf.Clock = realClock{}
+	buf.Reset()
+	s = NewWriteScheduler(&ConnectionPool{conf: conf}, 1, time.Millisecond, 2)
+	assert.Nil(t, s.Write(context.Background(), StringVID("p1"), "UPDATE VERTEX ON player 'p1' SET age = 43", nil))
+	s.Close()
+	assert.Equal(t, "UPDATE VERTEX ON player 'p1' SET age = 43;\n", buf.String())
+}
+
+func TestExecuteBatchDryRun(t *testing.T) {
+	var buf bytes.Buffer
+	// the pool has no host, a session acquired from it would fail
+	pool := &ConnectionPool{conf: NewPoolConf(WithBatchParallelism(4))}
+	results := pool.ExecuteBatch(WithDryRun(context.Background(), DryRunWriter(&buf)), []Statement{
+		{Stmt: "INSERT VERTEX player() VALUES 'p1':()"},
+		{Stmt: "INSERT VERTEX player() VALUES $id:()", Params: map[string]interface{}{"id": "p2"}},
+		{Stmt: "INSERT VERTEX player() VALUES 'p3':()"},
+	})
+	for _, result := range results {
+		assert.Nil(t, result.Err)
+		assert.True(t, result.ResultSet.IsSucceed())
+	}
+	assert.Equal(t, "INSERT VERTEX player() VALUES 'p1':();\n:param id => \"p2\"\n"+
+		"INSERT VERTEX player() VALUES $id:();\nINSERT VERTEX player() VALUES 'p3':();\n", buf.String())
+}
@@ -0,0 +1,151 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/vesoft-inc/nebula-go/v3/nebula"
+)
+
+const secondsPerDay = 24 * 3600
+
+// NewDuration returns the duration of the given months plus d, as built by duration() of nGQL
+func NewDuration(months int32, d time.Duration) *nebula.Duration {
+	return &nebula.Duration{
+		Months:       months,
+		Seconds:      int64(d / time.Second),
+		Microseconds: int32(d % time.Second / time.Microsecond),
+	}
+}
+
+// AddDurationToDate adds the duration to the date like the graph service does:
+// the months are added first and the day is clamped to the last day of the resulting month,
+// e.g. 2022-01-31 + P1M is 2022-02-28, then the whole days of the seconds are added.
+func AddDurationToDate(date nebula.Date, d nebula.Duration) nebula.Date {
+	t := addMonths(int(date.Year), time.Month(date.Month), int(date.Day), 0, 0, 0, 0, d.Months)
+	t = t.AddDate(0, 0, int(d.Seconds/secondsPerDay))
+	return nebula.Date{Year: int16(t.Year()), Month: int8(t.Month()), Day: int8(t.Day())}
+}
+
+// AddDurationToDateTime adds the duration to the datetime like the graph service does:
+// the months are added first and the day is clamped to the last day of the resulting month,
+// then the seconds and the microseconds are added.
+func AddDurationToDateTime(dt nebula.DateTime, d nebula.Duration) nebula.DateTime {
+	t := addMonths(int(dt.Year), time.Month(dt.Month), int(dt.Day),
+		int(dt.Hour), int(dt.Minute), int(dt.Sec), int(dt.Microsec), d.Months)
+	seconds := d.Seconds + int64(d.Microseconds)/1000000
+	// add the days separately, the seconds of long durations overflow time.Duration
+	t = t.AddDate(0, 0, int(seconds/secondsPerDay))
+	t = t.Add(time.Duration(seconds%secondsPerDay)*time.Second + time.Duration(d.Microseconds%1000000)*time.Microsecond)
+	return nebula.DateTime{
+		Year:     int16(t.Year()),
+		Month:    int8(t.Month()),
+		Day:      int8(t.Day()),
+		Hour:     int8(t.Hour()),
+		Minute:   int8(t.Minute()),
+		Sec:      int8(t.Second()),
+		Microsec: int32(t.Nanosecond() / 1000),
+	}
+}
+
+// addMonths adds months to the date and clamps the day to the last day of the resulting month,
+// unlike time.AddDate which normalizes 2022-02-31 to 2022-03-03
+func addMonths(year int, month time.Month, day, hour, minute, sec, microsec int, months int32) time.Time {
+	m := int(month) - 1 + int(months)
+	year += m / 12
+	if m %= 12; m < 0 {
+		m += 12
+		year--
+	}
+	month = time.Month(m + 1)
+	// day 0 of the next month is the last day of the month
+	if last := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day(); day > last {
+		day = last
+	}
+	return time.Date(year, month, day, hour, minute, sec, microsec*1000, time.UTC)
+}
+
+// CompareDurations returns -1, 0 or 1 if a is shorter than, equal to or longer than b.
+// Like the graph service, the months are compared first, as a month has no fixed length.
+func CompareDurations(a, b nebula.Duration) int {
+	aSeconds, aMicros := normalizeDuration(a)
+	bSeconds, bMicros := normalizeDuration(b)
+	switch {
+	case a.Months != b.Months:
+		return compareInt64(int64(a.Months), int64(b.Months))
+	case aSeconds != bSeconds:
+		return compareInt64(aSeconds, bSeconds)
+	default:
+		return compareInt64(aMicros, bMicros)
+	}
+}
+
+// normalizeDuration returns the seconds and the microseconds of the duration, with the microseconds below a second
+func normalizeDuration(d nebula.Duration) (int64, int64) {
+	micros := d.Seconds*1000000 + int64(d.Microseconds)
+	return micros / 1000000, micros % 1000000
+}
+
+func compareInt64(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// DurationToISO8601 formats the duration as an ISO 8601 duration, e.g. P1Y2M3DT4H5M6.5S.
+// The days are the whole days of the seconds, as returned by duration.days of nGQL.
+func DurationToISO8601(d nebula.Duration) string {
+	seconds, micros := normalizeDuration(d)
+	if d.Months == 0 && seconds == 0 && micros == 0 {
+		return "PT0S"
+	}
+	var sb strings.Builder
+	sb.WriteString("P")
+	if years := d.Months / 12; years != 0 {
+		fmt.Fprintf(&sb, "%dY", years)
+	}
+	if months := d.Months % 12; months != 0 {
+		fmt.Fprintf(&sb, "%dM", months)
+	}
+	if days := seconds / secondsPerDay; days != 0 {
+		fmt.Fprintf(&sb, "%dD", days)
+	}
+	seconds %= secondsPerDay
+	if seconds == 0 && micros == 0 {
+		return sb.String()
+	}
+	sb.WriteString("T")
+	if hours := seconds / 3600; hours != 0 {
+		fmt.Fprintf(&sb, "%dH", hours)
+	}
+	if minutes := seconds % 3600 / 60; minutes != 0 {
+		fmt.Fprintf(&sb, "%dM", minutes)
+	}
+	if seconds %= 60; seconds != 0 || micros != 0 {
+		if micros == 0 {
+			fmt.Fprintf(&sb, "%dS", seconds)
+		} else {
+			sign := ""
+			if seconds < 0 || micros < 0 {
+				sign, seconds, micros = "-", -seconds, -micros
+			}
+			fraction := strings.TrimRight(fmt.Sprintf("%06d", micros), "0")
+			fmt.Fprintf(&sb, "%s%d.%sS", sign, seconds, fraction)
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,54 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vesoft-inc/nebula-go/v3/nebula"
+)
+
+func TestAddDuration(t *testing.T) {
+	oneMonth := *NewDuration(1, 0)
+	assert.Equal(t, nebula.Date{Year: 2022, Month: 2, Day: 28},
+		AddDurationToDate(nebula.Date{Year: 2022, Month: 1, Day: 31}, oneMonth))
+	assert.Equal(t, nebula.Date{Year: 2024, Month: 2, Day: 29},
+		AddDurationToDate(nebula.Date{Year: 2024, Month: 1, Day: 31}, oneMonth))
+	assert.Equal(t, nebula.Date{Year: 2021, Month: 11, Day: 30},
+		AddDurationToDate(nebula.Date{Year: 2022, Month: 1, Day: 31}, *NewDuration(-2, 0)))
+	// the days are added after the months, the remaining seconds are ignored
+	assert.Equal(t, nebula.Date{Year: 2022, Month: 3, Day: 2},
+		AddDurationToDate(nebula.Date{Year: 2022, Month: 1, Day: 31}, *NewDuration(1, 49*time.Hour)))
+
+	dt := nebula.DateTime{Year: 2022, Month: 12, Day: 31, Hour: 23, Minute: 59, Sec: 59, Microsec: 999999}
+	assert.Equal(t, nebula.DateTime{Year: 2023, Month: 2, Day: 1, Hour: 0, Minute: 0, Sec: 0, Microsec: 0},
+		AddDurationToDateTime(dt, *NewDuration(1, time.Microsecond)))
+	assert.Equal(t, nebula.DateTime{Year: 2022, Month: 12, Day: 31, Hour: 23, Minute: 59, Sec: 58, Microsec: 999999},
+		AddDurationToDateTime(dt, *NewDuration(0, -time.Second)))
+	assert.Equal(t, nebula.DateTime{Year: 2322, Month: 12, Day: 31, Hour: 23, Minute: 59, Sec: 59, Microsec: 999999},
+		AddDurationToDateTime(dt, nebula.Duration{Seconds: 109572 * secondsPerDay}))
+}
+
+func TestCompareDurations(t *testing.T) {
+	assert.Equal(t, 0, CompareDurations(nebula.Duration{Seconds: 1}, nebula.Duration{Microseconds: 1000000}))
+	assert.Equal(t, 1, CompareDurations(*NewDuration(1, 0), *NewDuration(0, 40*24*time.Hour)))
+	assert.Equal(t, -1, CompareDurations(*NewDuration(0, time.Second), *NewDuration(0, time.Second+time.Microsecond)))
+	assert.Equal(t, -1, CompareDurations(*NewDuration(-1, 0), nebula.Duration{}))
+}
+
+func TestDurationToISO8601(t *testing.T) {
+	assert.Equal(t, "PT0S", DurationToISO8601(nebula.Duration{}))
+	assert.Equal(t, "P1Y2M3DT4H5M6.5S",
+		DurationToISO8601(*NewDuration(14, 3*24*time.Hour+4*time.Hour+5*time.Minute+6500*time.Millisecond)))
+	assert.Equal(t, "P1M", DurationToISO8601(*NewDuration(1, 0)))
+	assert.Equal(t, "PT0.000001S", DurationToISO8601(nebula.Duration{Microseconds: 1}))
+	assert.Equal(t, "P-1MT-1H-0.5S", DurationToISO8601(*NewDuration(-1, -time.Hour-500*time.Millisecond)))
+}
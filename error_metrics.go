@@ -0,0 +1,167 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/vesoft-inc/nebula-go/v3/nebula"
+)
+
+// ErrorClass is a stable classification of the errors of the graph service, to alert on
+// instead of the error messages, which change between versions
+type ErrorClass string
+
+const (
+	// ErrorClassClient is an error reported by the driver, e.g. a broken connection, without an error code
+	ErrorClassClient ErrorClass = "client"
+	// ErrorClassConnection is a failure to reach the graph service or the storage services
+	ErrorClassConnection ErrorClass = "connection"
+	// ErrorClassAuth is a failed authentication or a missing permission
+	ErrorClassAuth ErrorClass = "auth"
+	// ErrorClassSession is an invalid or expired session
+	ErrorClassSession ErrorClass = "session"
+	// ErrorClassStatement is an invalid statement, retrying it fails again
+	ErrorClassStatement ErrorClass = "statement"
+	// ErrorClassNotFound is a missing space, schema or object
+	ErrorClassNotFound ErrorClass = "not_found"
+	// ErrorClassTransient is a leader change, a running balance or an overloaded service, retrying may succeed
+	ErrorClassTransient ErrorClass = "transient"
+	// ErrorClassPartial is a statement which only succeeded on some partitions
+	ErrorClassPartial ErrorClass = "partial"
+	// ErrorClassExecution is any other error of the graph service
+	ErrorClassExecution ErrorClass = "execution"
+)
+
+// the max number of series of the error counters of a pool,
+// the errors of the other statements are counted with the OtherStatementsTemplate
+const maxErrorSeries = 1024
+
+// ClassifyErrorCode returns the class of an error code of the graph service
+func ClassifyErrorCode(code ErrorCode) ErrorClass {
+	switch nebula.ErrorCode(code) {
+	case nebula.ErrorCode_E_DISCONNECTED, nebula.ErrorCode_E_FAIL_TO_CONNECT, nebula.ErrorCode_E_RPC_FAILURE,
+		nebula.ErrorCode_E_NO_HOSTS:
+		return ErrorClassConnection
+	case nebula.ErrorCode_E_BAD_USERNAME_PASSWORD, nebula.ErrorCode_E_BAD_PERMISSION, nebula.ErrorCode_E_INVALID_PASSWORD,
+		nebula.ErrorCode_E_USER_NOT_FOUND:
+		return ErrorClassAuth
+	case nebula.ErrorCode_E_SESSION_INVALID, nebula.ErrorCode_E_SESSION_TIMEOUT, nebula.ErrorCode_E_SESSION_NOT_FOUND:
+		return ErrorClassSession
+	case nebula.ErrorCode_E_SYNTAX_ERROR, nebula.ErrorCode_E_SEMANTIC_ERROR, nebula.ErrorCode_E_STATEMENT_EMPTY,
+		nebula.ErrorCode_E_UNSUPPORTED:
+		return ErrorClassStatement
+	case nebula.ErrorCode_E_LEADER_CHANGED, nebula.ErrorCode_E_BALANCER_RUNNING, nebula.ErrorCode_E_TOO_MANY_CONNECTIONS,
+		nebula.ErrorCode_E_WRITE_STALLED, nebula.ErrorCode_E_CONSENSUS_ERROR, nebula.ErrorCode_E_LEADER_LEASE_FAILED:
+		return ErrorClassTransient
+	case nebula.ErrorCode_E_PARTIAL_SUCCEEDED, nebula.ErrorCode_E_PARTIAL_RESULT:
+		return ErrorClassPartial
+	}
+	if strings.HasSuffix(nebula.ErrorCode(code).String(), "_NOT_FOUND") {
+		return ErrorClassNotFound
+	}
+	return ErrorClassExecution
+}
+
+// ErrorCount is the number of errors of a class, code, host, tenant and statement fingerprint
+type ErrorCount struct {
+	Class ErrorClass
+	// The error code of the graph service, ErrorCode_SUCCEEDED for the errors of ErrorClassClient
+	Code ErrorCode
+	Host HostAddress
+	// The tenant of the statements, see WithTenantFromContext
+	Tenant string
+	StatementFingerprint
+	Count int64
+}
+
+type errorSeries struct {
+	code   ErrorCode
+	host   HostAddress
+	tenant string
+	digest string
+}
+
+// errorCounters are the error counters of a pool
+type errorCounters struct {
+	mu        sync.Mutex
+	counts    map[errorSeries]int64
+	templates map[string]string
+}
+
+func (c *errorCounters) record(code ErrorCode, host HostAddress, tenant, stmt string) {
+	fingerprint := FingerprintStatement(stmt)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.counts == nil {
+		c.counts = make(map[errorSeries]int64)
+		c.templates = make(map[string]string)
+	}
+	series := errorSeries{code: code, host: host, tenant: tenant, digest: fingerprint.Digest}
+	if _, ok := c.counts[series]; !ok && len(c.counts) >= maxErrorSeries {
+		series.digest = ""
+		fingerprint = StatementFingerprint{Template: OtherStatementsTemplate}
+	}
+	c.counts[series]++
+	c.templates[series.digest] = fingerprint.Template
+}
+
+// recordError counts the error of a statement executed on the host, resp is nil for the errors of the driver
+func (pool *ConnectionPool) recordError(host HostAddress, tenant, stmt string, resp *ResultSet) {
+	code := ErrorCode_SUCCEEDED
+	if resp != nil {
+		code = resp.GetErrorCode()
+	}
+	pool.errorCounters.record(code, host, tenant, stmt)
+}
+
+// ErrorCounts returns the number of errors of the statements executed by the sessions of the pool
+// by class, code, host, tenant and statement fingerprint, ordered by class, code, host, tenant and template
+func (pool *ConnectionPool) ErrorCounts() []ErrorCount {
+	c := &pool.errorCounters
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	counts := make([]ErrorCount, 0, len(c.counts))
+	for series, count := range c.counts {
+		class := ErrorClassClient
+		if series.code != ErrorCode_SUCCEEDED {
+			class = ClassifyErrorCode(series.code)
+		}
+		counts = append(counts, ErrorCount{
+			Class:                class,
+			Code:                 series.code,
+			Host:                 series.host,
+			Tenant:               series.tenant,
+			StatementFingerprint: StatementFingerprint{Template: c.templates[series.digest], Digest: series.digest},
+			Count:                count,
+		})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		a, b := counts[i], counts[j]
+		if a.Class != b.Class {
+			return a.Class < b.Class
+		}
+		if a.Code != b.Code {
+			return a.Code < b.Code
+		}
+		if a.Host.Host != b.Host.Host {
+			return a.Host.Host < b.Host.Host
+		}
+		if a.Host.Port != b.Host.Port {
+			return a.Host.Port < b.Host.Port
+		}
+		if a.Tenant != b.Tenant {
+			return a.Tenant < b.Tenant
+		}
+		return a.Template < b.Template
+	})
+	return counts
+}
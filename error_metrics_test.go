@@ -0,0 +1,57 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vesoft-inc/nebula-go/v3/nebula"
+)
+
+func TestClassifyErrorCode(t *testing.T) {
+	assert.Equal(t, ErrorClassConnection, ClassifyErrorCode(ErrorCode_E_RPC_FAILURE))
+	assert.Equal(t, ErrorClassAuth, ClassifyErrorCode(ErrorCode_E_BAD_PERMISSION))
+	assert.Equal(t, ErrorClassSession, ClassifyErrorCode(ErrorCode_E_SESSION_TIMEOUT))
+	assert.Equal(t, ErrorClassStatement, ClassifyErrorCode(ErrorCode_E_SYNTAX_ERROR))
+	assert.Equal(t, ErrorClassNotFound, ClassifyErrorCode(ErrorCode_E_SPACE_NOT_FOUND))
+	assert.Equal(t, ErrorClassNotFound, ClassifyErrorCode(ErrorCode(nebula.ErrorCode_E_TAG_NOT_FOUND)))
+	assert.Equal(t, ErrorClassTransient, ClassifyErrorCode(ErrorCode(nebula.ErrorCode_E_LEADER_CHANGED)))
+	assert.Equal(t, ErrorClassPartial, ClassifyErrorCode(ErrorCode_E_PARTIAL_SUCCEEDED))
+	assert.Equal(t, ErrorClassExecution, ClassifyErrorCode(ErrorCode_E_EXECUTION_ERROR))
+	assert.Equal(t, ErrorClassExecution, ClassifyErrorCode(ErrorCode(-123456)))
+}
+
+func TestErrorCounts(t *testing.T) {
+	pool := &ConnectionPool{conf: NewPoolConf()}
+	graphd1 := HostAddress{Host: "graphd1", Port: DefaultPort}
+	graphd2 := HostAddress{Host: "graphd2", Port: DefaultPort}
+
+	syntaxError := newTestResultSet(t, nil)
+	syntaxError.resp.ErrorCode = nebula.ErrorCode_E_SYNTAX_ERROR
+	pool.recordError(graphd1, "", "FETCH PROP ON player 'p1' YIELD vertex", syntaxError)
+	pool.recordError(graphd1, "", "FETCH PROP ON player 'p2' YIELD vertex", syntaxError)
+	pool.recordError(graphd2, "", "FETCH PROP ON player 'p1' YIELD vertex", syntaxError)
+	pool.recordError(graphd2, "", "SHOW HOSTS", nil)
+
+	counts := pool.ErrorCounts()
+	assert.Len(t, counts, 3)
+	assert.Equal(t, ErrorClassClient, counts[0].Class)
+	assert.Equal(t, ErrorCode_SUCCEEDED, counts[0].Code)
+	assert.Equal(t, "show hosts", counts[0].Template)
+	assert.Equal(t, ErrorCount{
+		Class:                ErrorClassStatement,
+		Code:                 ErrorCode_E_SYNTAX_ERROR,
+		Host:                 graphd1,
+		StatementFingerprint: FingerprintStatement("FETCH PROP ON player 'p1' YIELD vertex"),
+		Count:                2,
+	}, counts[1])
+	assert.Equal(t, graphd2, counts[2].Host)
+	assert.Equal(t, int64(1), counts[2].Count)
+}
@@ -0,0 +1,146 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ExecOption is an option of a single execution, e.g. of ExecuteWithContext
+type ExecOption func(*execOptions)
+
+type execOptions struct {
+	timeout  time.Duration
+	labels   map[string]string
+	readOnly bool
+	retries  int
+	backoff  time.Duration
+	host     *HostAddress
+}
+
+// WithExecTimeout bounds the execution by the timeout, like StatementMeta.Timeout
+func WithExecTimeout(timeout time.Duration) ExecOption {
+	return func(o *execOptions) {
+		o.timeout = timeout
+	}
+}
+
+// WithExecLabel adds a label to the StatementMeta of the execution
+func WithExecLabel(key, value string) ExecOption {
+	return func(o *execOptions) {
+		if o.labels == nil {
+			o.labels = make(map[string]string)
+		}
+		o.labels[key] = value
+	}
+}
+
+// WithExecReadOnly marks the statement as read-only in the StatementMeta of the execution
+func WithExecReadOnly() ExecOption {
+	return func(o *execOptions) {
+		o.readOnly = true
+	}
+}
+
+// WithExecRetries executes the statement again up to n times when the execution fails with an error,
+// waiting a random duration up to backoff before the first retry, doubled after every retry up to DefaultRetryMaxBackoff.
+// Responses reporting an error of the graph service are not retried. The statement must be idempotent.
+func WithExecRetries(n int, backoff time.Duration) ExecOption {
+	return func(o *execOptions) {
+		o.retries = n
+		o.backoff = backoff
+	}
+}
+
+// WithExecHost fails the execution if the session is not connected to the host,
+// e.g. for SHOW LOCAL SESSIONS or SHOW LOCAL QUERIES which only report the graph service executing them
+func WithExecHost(host HostAddress) ExecOption {
+	return func(o *execOptions) {
+		o.host = &host
+	}
+}
+
+func newExecOptions(opts []ExecOption) execOptions {
+	var o execOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// withMeta returns the context carrying the StatementMeta of the context updated by the options,
+// bounded by the timeout if any
+func (o execOptions) withMeta(ctx context.Context) (context.Context, context.CancelFunc) {
+	if o.timeout <= 0 && len(o.labels) == 0 && !o.readOnly {
+		return ctx, func() {}
+	}
+	meta, _ := StatementMetaFrom(ctx)
+	if o.timeout > 0 {
+		meta.Timeout = o.timeout
+	}
+	if len(o.labels) > 0 {
+		labels := make(map[string]string, len(meta.Labels)+len(o.labels))
+		for k, v := range meta.Labels {
+			labels[k] = v
+		}
+		for k, v := range o.labels {
+			labels[k] = v
+		}
+		meta.Labels = labels
+	}
+	meta.ReadOnly = meta.ReadOnly || o.readOnly
+	ctx = WithStatementMeta(ctx, meta)
+	if o.timeout > 0 {
+		return context.WithTimeout(ctx, o.timeout)
+	}
+	return ctx, func() {}
+}
+
+// retry calls execute until it does not fail with an error or the retries of the options are exhausted
+func (o execOptions) retry(ctx context.Context, clock Clock, randIntn func(int) int,
+	execute func() (*ResultSet, error)) (*ResultSet, error) {
+	backoff := o.backoff
+	for attempt := 0; ; attempt++ {
+		resp, err := execute()
+		if err == nil || attempt >= o.retries || ctx.Err() != nil {
+			return resp, err
+		}
+		// full jitter, like the retries of WithSession
+		wait := time.Duration(0)
+		if backoff > 0 {
+			wait = time.Duration(randIntn(int(backoff))) + 1
+			if backoff *= 2; backoff > DefaultRetryMaxBackoff {
+				backoff = DefaultRetryMaxBackoff
+			}
+		}
+		timer := clock.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, fmt.Errorf("failed to retry: %s, last error: %s", ctx.Err().Error(), err.Error())
+		case <-timer.C():
+		}
+	}
+}
+
+// checkHost returns an error if the session is not connected to the host
+func (session *Session) checkHost(host HostAddress) error {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	if session.connection == nil {
+		return fmt.Errorf("failed to execute: Session has been released")
+	}
+	if addr := session.connection.severAddress; addr != host {
+		return fmt.Errorf("failed to execute on %s:%d: the session is connected to %s:%d",
+			host.Host, host.Port, addr.Host, addr.Port)
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExecOptionsMeta(t *testing.T) {
+	var meta StatementMeta
+	var deadline bool
+	conf := NewPoolConf(WithInterceptors(func(ctx context.Context, stmt string, p map[string]interface{}, invoker Invoker) (*ResultSet, error) {
+		meta, _ = StatementMetaFrom(ctx)
+		_, deadline = ctx.Deadline()
+		return &ResultSet{}, nil
+	}))
+	session := &Session{connPool: &ConnectionPool{conf: conf}}
+
+	ctx := WithStatementMeta(context.Background(), StatementMeta{Labels: map[string]string{"feature": "profile"}})
+	_, err := session.ExecuteWithContext(ctx, "YIELD 1", nil,
+		WithExecTimeout(time.Second), WithExecLabel("caller", "batch"), WithExecReadOnly())
+	assert.Nil(t, err)
+	assert.Equal(t, StatementMeta{
+		Timeout:  time.Second,
+		Labels:   map[string]string{"feature": "profile", "caller": "batch"},
+		ReadOnly: true,
+	}, meta)
+	assert.True(t, deadline)
+
+	_, err = session.Execute("YIELD 1")
+	assert.Nil(t, err)
+	assert.Equal(t, StatementMeta{}, meta)
+	assert.False(t, deadline)
+}
+
+func TestExecOptionsRetries(t *testing.T) {
+	var calls int
+	conf := NewPoolConf(WithInterceptors(func(ctx context.Context, stmt string, p map[string]interface{}, invoker Invoker) (*ResultSet, error) {
+		if calls++; calls < 3 {
+			return nil, fmt.Errorf("connection reset")
+		}
+		return &ResultSet{}, nil
+	}))
+	conf.Clock = realClock{}
+	session := &Session{connPool: &ConnectionPool{conf: conf}}
+
+	_, err := session.ExecuteWithContext(context.Background(), "YIELD 1", nil, WithExecRetries(1, 0))
+	assert.EqualError(t, err, "connection reset")
+	assert.Equal(t, 2, calls)
+
+	calls = 0
+	_, err = session.ExecuteWithContext(context.Background(), "YIELD 1", nil, WithExecRetries(2, 0))
+	assert.Nil(t, err)
+	assert.Equal(t, 3, calls)
+
+	calls = 0
+	_, err = session.Execute("YIELD 1")
+	assert.EqualError(t, err, "connection reset")
+	assert.Equal(t, 1, calls)
+}
+
+func TestExecOptionsHost(t *testing.T) {
+	var calls int
+	conf := NewPoolConf(WithInterceptors(func(ctx context.Context, stmt string, p map[string]interface{}, invoker Invoker) (*ResultSet, error) {
+		calls++
+		return &ResultSet{}, nil
+	}))
+	host := HostAddress{Host: "graphd0", Port: 9669}
+	session := &Session{connPool: &ConnectionPool{conf: conf}, connection: &connection{severAddress: host}}
+
+	_, err := session.Execute("SHOW LOCAL SESSIONS", WithExecHost(host))
+	assert.Nil(t, err)
+	_, err = session.Execute("SHOW LOCAL SESSIONS", WithExecHost(HostAddress{Host: "graphd1", Port: 9669}))
+	assert.EqualError(t, err, "failed to execute on graphd1:9669: the session is connected to graphd0:9669")
+	assert.Equal(t, 1, calls)
+}
+
+func TestExecOptionsRetryBackoff(t *testing.T) {
+	var bounds []int
+	randIntn := func(n int) int {
+		bounds = append(bounds, n)
+		return 0
+	}
+	calls := 0
+	o := newExecOptions([]ExecOption{WithExecRetries(3, 20*time.Second)})
+	_, err := o.retry(context.Background(), realClock{}, randIntn, func() (*ResultSet, error) {
+		calls++
+		return nil, fmt.Errorf("connection reset")
+	})
+	assert.EqualError(t, err, "connection reset")
+	assert.Equal(t, 4, calls)
+	assert.Equal(t, []int{int(20 * time.Second), int(DefaultRetryMaxBackoff), int(DefaultRetryMaxBackoff)}, bounds)
+}
@@ -0,0 +1,147 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Direction is the direction of the edges followed by Expand
+type Direction int
+
+const (
+	// DirectionOut follows the outgoing edges
+	DirectionOut Direction = iota
+	// DirectionIn follows the incoming edges
+	DirectionIn
+	// DirectionBoth follows the edges in both directions
+	DirectionBoth
+)
+
+// ExpandOptions are the options of Expand
+type ExpandOptions struct {
+	// The max number of steps, 0 means 1
+	Depth int
+	// The edge types followed, empty means every edge type
+	EdgeTypes []string
+	// The direction of the edges followed
+	Direction Direction
+	// The max number of edges returned, 0 means no limit
+	Limit int
+	// An nGQL expression filtering the edges, e.g. "follow.degree > 90" or "$$.player.age > 30"
+	// It is interpolated into the statement as is, so it must not contain untrusted input.
+	Where string
+}
+
+// Subgraph is a set of vertices and of the edges between them
+type Subgraph struct {
+	Nodes         map[VID]*Node
+	Relationships []*Relationship
+}
+
+// Expand returns the neighborhood of the vertex: the edges reachable from it within opts.Depth steps
+// and their vertices, including the vertex itself if it has at least one edge.
+// The vertex is formatted with its own type, so it must match the vid type of the current space.
+func (session *Session) Expand(ctx context.Context, vid VID, opts ExpandOptions) (*Subgraph, error) {
+	resp, err := session.executeChecked(ctx, expandStatement(vid, opts), "expand "+vid.String())
+	if err != nil {
+		return nil, err
+	}
+	return collectSubgraph(resp)
+}
+
+// expandStatement returns the GO statement yielding the source vertex, the edge and the destination vertex
+func expandStatement(vid VID, opts ExpandOptions) string {
+	depth := opts.Depth
+	if depth < 1 {
+		depth = 1
+	}
+	over := "*"
+	if len(opts.EdgeTypes) > 0 {
+		edges := make([]string, len(opts.EdgeTypes))
+		for i, edge := range opts.EdgeTypes {
+			edges[i] = QuoteIdentifier(edge)
+		}
+		over = strings.Join(edges, ", ")
+	}
+	switch opts.Direction {
+	case DirectionIn:
+		over += " REVERSELY"
+	case DirectionBoth:
+		over += " BIDIRECT"
+	}
+	stmt := fmt.Sprintf("GO 1 TO %d STEPS FROM %s OVER %s", depth, vid.String(), over)
+	if opts.Where != "" {
+		stmt += " WHERE " + opts.Where
+	}
+	stmt += " YIELD $^ AS src, edge AS e, $$ AS dst"
+	if opts.Limit > 0 {
+		stmt += fmt.Sprintf(" | LIMIT %d", opts.Limit)
+	}
+	return stmt
+}
+
+// collectSubgraph returns the subgraph of the rows of vertices and edges, the edges are deduplicated
+func collectSubgraph(resp *ResultSet) (*Subgraph, error) {
+	type edgeID struct {
+		name string
+		key  EdgeKey
+	}
+	subgraph := &Subgraph{Nodes: make(map[VID]*Node)}
+	seen := make(map[edgeID]bool)
+	for i := 0; i < resp.GetRowSize(); i++ {
+		record, err := resp.GetRowValuesByIndex(i)
+		if err != nil {
+			return nil, err
+		}
+		for _, col := range []string{"src", "dst"} {
+			val, err := record.GetValueByColName(col)
+			if err != nil {
+				return nil, err
+			}
+			if !val.IsVertex() {
+				continue
+			}
+			node, err := val.AsNode()
+			if err != nil {
+				return nil, err
+			}
+			vid, err := node.GetID().AsVID()
+			if err != nil {
+				return nil, err
+			}
+			subgraph.Nodes[vid] = node
+		}
+
+		val, err := record.GetValueByColName("e")
+		if err != nil {
+			return nil, err
+		}
+		relationship, err := val.AsRelationship()
+		if err != nil {
+			return nil, err
+		}
+		src, err := relationship.GetSrcVertexID().AsVID()
+		if err != nil {
+			return nil, err
+		}
+		dst, err := relationship.GetDstVertexID().AsVID()
+		if err != nil {
+			return nil, err
+		}
+		id := edgeID{relationship.GetEdgeName(), EdgeKey{Src: src, Dst: dst, Rank: relationship.GetRanking()}}
+		if !seen[id] {
+			seen[id] = true
+			subgraph.Relationships = append(subgraph.Relationships, relationship)
+		}
+	}
+	return subgraph, nil
+}
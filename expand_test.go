@@ -0,0 +1,54 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vesoft-inc/nebula-go/v3/nebula"
+	"github.com/vesoft-inc/nebula-go/v3/nebula/graph"
+)
+
+func TestExpandStatement(t *testing.T) {
+	assert.Equal(t, `GO 1 TO 1 STEPS FROM "player100" OVER * YIELD $^ AS src, edge AS e, $$ AS dst`,
+		expandStatement(StringVID("player100"), ExpandOptions{}))
+	assert.Equal(t, "GO 1 TO 3 STEPS FROM 100 OVER `follow`, `serve` BIDIRECT WHERE follow.degree > 90 "+
+		"YIELD $^ AS src, edge AS e, $$ AS dst | LIMIT 10",
+		expandStatement(IntVID(100), ExpandOptions{
+			Depth:     3,
+			EdgeTypes: []string{"follow", "serve"},
+			Direction: DirectionBoth,
+			Limit:     10,
+			Where:     "follow.degree > 90",
+		}))
+	assert.Contains(t, expandStatement(IntVID(100), ExpandOptions{Direction: DirectionIn}), "OVER * REVERSELY")
+}
+
+func TestCollectSubgraph(t *testing.T) {
+	edge := &nebula.Value{EVal: getEdge("Bob", "Tom", 0)}
+	row := func(src, dst string) *nebula.Row {
+		return &nebula.Row{Values: []*nebula.Value{
+			{VVal: getVertex(src, 1, 0)}, edge, {VVal: getVertex(dst, 1, 0)},
+		}}
+	}
+	dataset := &nebula.DataSet{
+		ColumnNames: [][]byte{[]byte("src"), []byte("e"), []byte("dst")},
+		Rows:        []*nebula.Row{row("Bob", "Tom"), row("Bob", "Tom")},
+	}
+	resp, err := genResultSet(&graph.ExecutionResponse{ErrorCode: nebula.ErrorCode_SUCCEEDED, Data: dataset}, testTimezone)
+	assert.Nil(t, err)
+
+	subgraph, err := collectSubgraph(resp)
+	assert.Nil(t, err)
+	assert.Len(t, subgraph.Nodes, 2)
+	assert.Contains(t, subgraph.Nodes, StringVID("Bob"))
+	assert.Contains(t, subgraph.Nodes, StringVID("Tom"))
+	assert.Len(t, subgraph.Relationships, 1)
+}
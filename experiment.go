@@ -0,0 +1,175 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The variants of an experiment
+const (
+	VariantControl   = "control"
+	VariantTreatment = "treatment"
+)
+
+// Experiment routes a percentage of the executions of the statements of a fingerprint to an alternative
+// statement, e.g. to validate a rewrite of a slow query in production
+type Experiment struct {
+	Name string
+	// The digest of the fingerprint of the statements of the experiment, see FingerprintStatement
+	Digest string
+	// The percentage of the executions routed to the alternative statement, between 0 and 100
+	Percent float64
+	// The alternative statement, executed with the parameters of the original one
+	Stmt string
+	// Returns the alternative statement and parameters of an execution, e.g. to carry over its literals.
+	// It takes precedence over Stmt. The original statement is executed if it fails.
+	Rewrite func(stmt string, params map[string]interface{}) (string, map[string]interface{}, error)
+	// Execute the original statement too when an execution is routed to the alternative statement
+	// and compare their rows, see ExperimentResult.Match. The caller gets the result of the original statement,
+	// so the statements of an experiment comparing its results must be read-only.
+	Compare bool
+	// Called after every execution of the statements of the experiment, from the executing goroutine
+	OnResult func(ExperimentResult)
+}
+
+// ExperimentResult is an execution of the statements of an experiment
+type ExperimentResult struct {
+	Experiment string
+	// VariantControl for the original statement, VariantTreatment for the alternative one
+	Variant string
+	Stmt    string
+	Latency time.Duration
+	// The result of the execution, nil if it failed
+	ResultSet *ResultSet
+	Err       error
+	// The error of Experiment.Rewrite which made the execution fall back to the original statement
+	RewriteErr error
+	// Whether the rows were compared with the rows of the original statement, see Experiment.Compare
+	Compared bool
+	// Whether both statements succeeded with the same rows, in any order
+	Match bool
+}
+
+// ExperimentInterceptor returns an interceptor running the experiments, see WithInterceptors.
+// The statements whose fingerprint is not the one of an experiment are executed unchanged.
+func ExperimentInterceptor(experiments ...Experiment) Interceptor {
+	rnd := rand.New(rand.NewSource(time.Now().UnixNano()))
+	var mu sync.Mutex
+	return newExperimentInterceptor(realClock{}, func() float64 {
+		mu.Lock()
+		defer mu.Unlock()
+		return rnd.Float64() * 100
+	}, experiments)
+}
+
+func newExperimentInterceptor(clock Clock, roll func() float64, experiments []Experiment) Interceptor {
+	byDigest := make(map[string]Experiment, len(experiments))
+	for _, e := range experiments {
+		byDigest[e.Digest] = e
+	}
+	return func(ctx context.Context, stmt string, params map[string]interface{}, invoker Invoker) (*ResultSet, error) {
+		e, ok := byDigest[FingerprintStatement(stmt).Digest]
+		if !ok {
+			return invoker(ctx, stmt, params)
+		}
+		if roll() >= e.Percent {
+			result := e.execute(ctx, clock, invoker, VariantControl, stmt, params)
+			e.report(result)
+			return result.ResultSet, result.Err
+		}
+		alt, altParams, err := e.alternative(stmt, params)
+		if err != nil {
+			result := e.execute(ctx, clock, invoker, VariantControl, stmt, params)
+			result.RewriteErr = err
+			e.report(result)
+			return result.ResultSet, result.Err
+		}
+		if !e.Compare {
+			result := e.execute(ctx, clock, invoker, VariantTreatment, alt, altParams)
+			e.report(result)
+			return result.ResultSet, result.Err
+		}
+		control := e.execute(ctx, clock, invoker, VariantControl, stmt, params)
+		treatment := e.execute(ctx, clock, invoker, VariantTreatment, alt, altParams)
+		treatment.Compared = true
+		treatment.Match = sameRows(control, treatment)
+		e.report(control)
+		e.report(treatment)
+		return control.ResultSet, control.Err
+	}
+}
+
+// execute executes a variant of the statement, the result is reported by the caller
+func (e Experiment) execute(ctx context.Context, clock Clock, invoker Invoker, variant, stmt string,
+	params map[string]interface{}) ExperimentResult {
+	start := clock.Now()
+	resp, err := invoker(ctx, stmt, params)
+	return ExperimentResult{
+		Experiment: e.Name,
+		Variant:    variant,
+		Stmt:       stmt,
+		Latency:    clock.Now().Sub(start),
+		ResultSet:  resp,
+		Err:        err,
+	}
+}
+
+func (e Experiment) report(result ExperimentResult) {
+	if e.OnResult != nil {
+		e.OnResult(result)
+	}
+}
+
+// sameRows returns true if both executions succeeded with the same rows in any order,
+// the column names are not compared as a rewrite may alias them differently
+func sameRows(a, b ExperimentResult) bool {
+	if a.Err != nil || b.Err != nil || a.ResultSet == nil || b.ResultSet == nil ||
+		!a.ResultSet.IsSucceed() || !b.ResultSet.IsSucceed() {
+		return false
+	}
+	rowsA, rowsB := sortedRows(a.ResultSet), sortedRows(b.ResultSet)
+	if len(rowsA) != len(rowsB) {
+		return false
+	}
+	for i := range rowsA {
+		if rowsA[i] != rowsB[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// sortedRows returns the rows of the result set rendered as strings, sorted
+func sortedRows(res *ResultSet) []string {
+	table := res.AsStringTable()
+	rows := make([]string, 0, len(table)-1)
+	for _, row := range table[1:] {
+		rows = append(rows, strings.Join(row, "\x00"))
+	}
+	sort.Strings(rows)
+	return rows
+}
+
+func (e Experiment) alternative(stmt string, params map[string]interface{}) (string, map[string]interface{}, error) {
+	if e.Rewrite == nil {
+		return e.Stmt, params, nil
+	}
+	alt, altParams, err := e.Rewrite(stmt, params)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to rewrite statement of experiment %s: %s", e.Name, err.Error())
+	}
+	return alt, altParams, nil
+}
@@ -0,0 +1,114 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExperimentInterceptor(t *testing.T) {
+	clock := NewManualClock(time.Now())
+	var results []ExperimentResult
+	experiment := Experiment{
+		Name:     "match-rewrite",
+		Digest:   FingerprintStatement("GO FROM 'p1' OVER follow YIELD dst(edge)").Digest,
+		Percent:  25,
+		Stmt:     "MATCH (v)-[:follow]->(d) WHERE id(v) == $id RETURN id(d)",
+		OnResult: func(r ExperimentResult) { results = append(results, r) },
+	}
+	rolls := []float64{10, 80}
+	interceptor := newExperimentInterceptor(clock, func() float64 {
+		roll := rolls[0]
+		rolls = rolls[1:]
+		return roll
+	}, []Experiment{experiment})
+
+	var executed []string
+	invoker := func(ctx context.Context, stmt string, params map[string]interface{}) (*ResultSet, error) {
+		executed = append(executed, stmt)
+		clock.Advance(time.Millisecond)
+		return newTestResultSet(t, nil), nil
+	}
+	for i := 0; i < 2; i++ {
+		_, err := interceptor(context.Background(), "GO FROM 'p2' OVER follow YIELD dst(edge)", nil, invoker)
+		assert.Nil(t, err)
+	}
+	_, err := interceptor(context.Background(), "SHOW HOSTS", nil, invoker)
+	assert.Nil(t, err)
+
+	assert.Equal(t, []string{experiment.Stmt, "GO FROM 'p2' OVER follow YIELD dst(edge)", "SHOW HOSTS"}, executed)
+	if assert.Len(t, results, 2) {
+		assert.Equal(t, VariantTreatment, results[0].Variant)
+		assert.Equal(t, VariantControl, results[1].Variant)
+		assert.Equal(t, time.Millisecond, results[1].Latency)
+		assert.NotNil(t, results[1].ResultSet)
+	}
+}
+
+func TestExperimentCompare(t *testing.T) {
+	var results []ExperimentResult
+	stmt := "GO FROM 'p1' OVER follow YIELD dst(edge)"
+	experiment := Experiment{
+		Name:    "match-rewrite",
+		Digest:  FingerprintStatement(stmt).Digest,
+		Percent: 100,
+		Rewrite: func(stmt string, params map[string]interface{}) (string, map[string]interface{}, error) {
+			if params["fail"] == true {
+				return "", nil, fmt.Errorf("unsupported statement")
+			}
+			return "MATCH (v)-[:follow]->(d) RETURN id(d)", params, nil
+		},
+		Compare:  true,
+		OnResult: func(r ExperimentResult) { results = append(results, r) },
+	}
+	interceptor := newExperimentInterceptor(NewManualClock(time.Now()), func() float64 { return 0 }, []Experiment{experiment})
+
+	rows := map[string][][]interface{}{
+		stmt:                                    {{"p2"}, {"p3"}},
+		"MATCH (v)-[:follow]->(d) RETURN id(d)": {{"p3"}, {"p2"}},
+	}
+	var executed []string
+	invoker := func(ctx context.Context, stmt string, params map[string]interface{}) (*ResultSet, error) {
+		executed = append(executed, stmt)
+		return newTestResultSet(t, []string{"id"}, rows[stmt]...), nil
+	}
+	resp, err := interceptor(context.Background(), stmt, nil, invoker)
+	assert.Nil(t, err)
+	assert.Equal(t, [][]string{{"id"}, {"\"p2\""}, {"\"p3\""}}, resp.AsStringTable())
+	assert.Equal(t, []string{stmt, "MATCH (v)-[:follow]->(d) RETURN id(d)"}, executed)
+	if assert.Len(t, results, 2) {
+		assert.Equal(t, VariantControl, results[0].Variant)
+		assert.Equal(t, VariantTreatment, results[1].Variant)
+		assert.True(t, results[1].Compared)
+		assert.True(t, results[1].Match)
+	}
+
+	rows["MATCH (v)-[:follow]->(d) RETURN id(d)"] = [][]interface{}{{"p2"}}
+	results = nil
+	_, err = interceptor(context.Background(), stmt, nil, invoker)
+	assert.Nil(t, err)
+	if assert.Len(t, results, 2) {
+		assert.False(t, results[1].Match)
+	}
+
+	// the original statement is executed if the rewrite fails
+	results, executed = nil, nil
+	_, err = interceptor(context.Background(), stmt, map[string]interface{}{"fail": true}, invoker)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{stmt}, executed)
+	if assert.Len(t, results, 1) {
+		assert.Equal(t, VariantControl, results[0].Variant)
+		assert.EqualError(t, results[0].RewriteErr, "failed to rewrite statement of experiment match-rewrite: unsupported statement")
+	}
+}
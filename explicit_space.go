@@ -0,0 +1,74 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// WithExplicitSpace requires the space of every statement to be given explicitly with ExecuteIn,
+// so that a statement can not run in the space left by a previous one, e.g. of another tenant
+func WithExplicitSpace() PoolConfOption {
+	return func(conf *PoolConfig) {
+		conf.ExplicitSpace = true
+	}
+}
+
+// explicitSpaceKey marks the context of the statements whose space is explicit
+type explicitSpaceKey struct{}
+
+func withExplicitSpace(ctx context.Context) context.Context {
+	return context.WithValue(ctx, explicitSpaceKey{}, true)
+}
+
+// ExecuteIn executes the statement in the space, prefixing it with USE in the same request
+// so that no other statement can run in between. An empty space executes the statement as is,
+// for the statements which do not depend on a space, e.g. SHOW SPACES.
+func (session *Session) ExecuteIn(ctx context.Context, space, stmt string, params map[string]interface{}) (*ResultSet, error) {
+	if space != "" {
+		stmt = "USE " + QuoteIdentifier(space) + "; " + stmt
+	}
+	return session.ExecuteWithContext(withExplicitSpace(ctx), stmt, params)
+}
+
+// GetSpaceName returns the space used by the session, as reported by the last statement
+func (session *Session) GetSpaceName() string {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	return session.space
+}
+
+// checkExplicitSpace rejects, if ExplicitSpace is set in the pool config, the statements which would run
+// in the space left by a previous statement: the statements neither executed with ExecuteIn
+// nor starting with USE, once the session uses a space
+func (session *Session) checkExplicitSpace(ctx context.Context, stmt string) error {
+	if session.connPool == nil || !session.connPool.conf.ExplicitSpace || ctx.Value(explicitSpaceKey{}) != nil {
+		return nil
+	}
+	if startsWithUse(stmt) {
+		return nil
+	}
+	if space := session.GetSpaceName(); space != "" {
+		return fmt.Errorf("failed to execute: the session uses the space %s of a previous statement "+
+			"and the pool requires an explicit space, use ExecuteIn", space)
+	}
+	return nil
+}
+
+// startsWithUse returns true if the first statement is USE
+func startsWithUse(stmt string) bool {
+	for _, tok := range lexStatement(stmt) {
+		if tok.kind != tokenSpace {
+			return tok.kind == tokenWord && strings.EqualFold(tok.text, "use")
+		}
+	}
+	return false
+}
@@ -0,0 +1,59 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckExplicitSpace(t *testing.T) {
+	ctx := context.Background()
+	session := &Session{connPool: &ConnectionPool{conf: NewPoolConf(WithExplicitSpace())}}
+	assert.Nil(t, session.checkExplicitSpace(ctx, "SHOW SPACES"))
+
+	session.space = "tenant1"
+	err := session.checkExplicitSpace(ctx, "MATCH (v) RETURN v LIMIT 1")
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "the session uses the space tenant1 of a previous statement")
+	}
+	assert.Nil(t, session.checkExplicitSpace(withExplicitSpace(ctx), "MATCH (v) RETURN v LIMIT 1"))
+	assert.Nil(t, session.checkExplicitSpace(ctx, " /* tenant2 */ use `tenant2`; MATCH (v) RETURN v LIMIT 1"))
+	assert.NotNil(t, session.checkExplicitSpace(ctx, "YIELD 1; USE tenant2"))
+
+	session.connPool.conf.ExplicitSpace = false
+	assert.Nil(t, session.checkExplicitSpace(ctx, "MATCH (v) RETURN v LIMIT 1"))
+}
+
+func TestExplicitSpaceHelpers(t *testing.T) {
+	var explicit []bool
+	conf := NewPoolConf(WithExplicitSpace(), WithInterceptors(func(ctx context.Context, stmt string, p map[string]interface{}, invoker Invoker) (*ResultSet, error) {
+		explicit = append(explicit, ctx.Value(explicitSpaceKey{}) != nil)
+		if strings.HasPrefix(stmt, "SHOW") {
+			return newTestResultSet(t, nil), nil
+		}
+		return invoker(ctx, stmt, p)
+	}))
+	session := &Session{connPool: &ConnectionPool{conf: conf}, space: "tenant1"}
+
+	// the helpers depending on the space of the session are checked
+	_, _, err := session.FetchVertices(context.Background(), "player", []VID{StringVID("p1")})
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "the session uses the space tenant1 of a previous statement")
+	}
+	_, err = session.Expand(context.Background(), StringVID("p1"), ExpandOptions{})
+	assert.NotNil(t, err)
+	// the administration statements are not
+	_, err = session.ShowSessions(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, []bool{false, false, true}, explicit)
+}
@@ -0,0 +1,207 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultFallbackRetryInterval is the interval after which a FallbackPool tries the primary cluster again
+const DefaultFallbackRetryInterval = 30 * time.Second
+
+// ParseConnectionStrings parses an ordered list of connection strings, the first one is the primary cluster
+func ParseConnectionStrings(dsns ...string) ([]*ConnectionConfig, error) {
+	if len(dsns) == 0 {
+		return nil, fmt.Errorf("invalid connection strings: no connection string")
+	}
+	configs := make([]*ConnectionConfig, len(dsns))
+	for i, dsn := range dsns {
+		cfg, err := ParseConnectionString(dsn)
+		if err != nil {
+			return nil, err
+		}
+		configs[i] = cfg
+	}
+	return configs, nil
+}
+
+// FallbackPool acquires sessions from the first available cluster of an ordered list,
+// so that the clients fail over to a secondary cluster when the primary one is completely unavailable.
+// A cluster is unavailable when a session can not be acquired and none of its hosts can be reached,
+// other errors, such as an authentication failure, are returned without failing over.
+// Once failed over, the primary cluster is tried again every DefaultFallbackRetryInterval.
+type FallbackPool struct {
+	configs []*ConnectionConfig
+	log     Logger
+	clock   Clock
+
+	mu       sync.Mutex
+	pools    []*ConnectionPool
+	active   int
+	failedAt time.Time // when the primary cluster was found unavailable
+	closed   bool
+}
+
+// BuildFallbackPool parses the connection strings and returns a pool failing over between their clusters
+func BuildFallbackPool(log Logger, dsns ...string) (*FallbackPool, error) {
+	configs, err := ParseConnectionStrings(dsns...)
+	if err != nil {
+		return nil, err
+	}
+	return NewFallbackPool(configs, log)
+}
+
+// NewFallbackPool returns a pool failing over between the clusters of the configs, the first one is the primary.
+// The pool of a cluster is created when the cluster is first used, it fails if no cluster is available.
+func NewFallbackPool(configs []*ConnectionConfig, log Logger) (*FallbackPool, error) {
+	if len(configs) == 0 {
+		return nil, fmt.Errorf("failed to initialize fallback pool: no cluster")
+	}
+	p := &FallbackPool{
+		configs: configs,
+		log:     log,
+		clock:   configs[0].PoolConfig.Clock,
+		pools:   make([]*ConnectionPool, len(configs)),
+	}
+	if p.clock == nil {
+		p.clock = realClock{}
+	}
+	var errs []string
+	for i := range configs {
+		if _, err := p.pool(i); err != nil {
+			errs = append(errs, fmt.Sprintf("cluster %d: %s", i, err.Error()))
+			continue
+		}
+		p.setActive(i)
+		return p, nil
+	}
+	return nil, fmt.Errorf("failed to initialize fallback pool: every cluster is unavailable: %s", strings.Join(errs, "; "))
+}
+
+// Acquire returns a session of the active cluster, failing over to the next clusters in order
+// when it is unavailable
+func (p *FallbackPool) Acquire(ctx context.Context) (*Session, error) {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil, fmt.Errorf("failed to acquire session: the fallback pool is closed")
+	}
+	start := p.active
+	if start > 0 && p.clock.Now().Sub(p.failedAt) >= DefaultFallbackRetryInterval {
+		start = 0
+	}
+	p.mu.Unlock()
+
+	var errs []string
+	for n := 0; n < len(p.configs); n++ {
+		i := (start + n) % len(p.configs)
+		pool, err := p.pool(i)
+		if err == nil {
+			var session *Session
+			if session, err = pool.Acquire(ctx); err == nil {
+				p.setActive(i)
+				return session, nil
+			}
+			if clusterReachable(pool) {
+				return nil, err
+			}
+		}
+		errs = append(errs, fmt.Sprintf("cluster %d: %s", i, err.Error()))
+		p.log.Warn(fmt.Sprintf("Cluster %d is unavailable: %s", i, err.Error()))
+		if i == 0 {
+			p.mu.Lock()
+			p.failedAt = p.clock.Now()
+			p.mu.Unlock()
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return nil, fmt.Errorf("failed to acquire session: every cluster is unavailable: %s", strings.Join(errs, "; "))
+}
+
+// Active returns the index of the cluster sessions are acquired from
+func (p *FallbackPool) Active() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.active
+}
+
+// Close closes the pools of every cluster
+func (p *FallbackPool) Close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.closed = true
+	for i, pool := range p.pools {
+		if pool != nil {
+			pool.Close()
+			p.pools[i] = nil
+		}
+	}
+}
+
+// pool returns the pool of the i-th cluster, creating it if needed
+func (p *FallbackPool) pool(i int) (*ConnectionPool, error) {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil, fmt.Errorf("the fallback pool is closed")
+	}
+	if pool := p.pools[i]; pool != nil {
+		p.mu.Unlock()
+		return pool, nil
+	}
+	p.mu.Unlock()
+
+	// the hosts are dialed without the lock, so that an unreachable cluster does not block the others
+	pool, err := p.configs[i].NewPool(p.log)
+	if err != nil {
+		return nil, err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		pool.Close()
+		return nil, fmt.Errorf("the fallback pool is closed")
+	}
+	if p.pools[i] != nil {
+		// another caller opened the pool meanwhile
+		pool.Close()
+		return p.pools[i], nil
+	}
+	p.pools[i] = pool
+	return pool, nil
+}
+
+func (p *FallbackPool) setActive(i int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.active != i {
+		p.log.Warn(fmt.Sprintf("Failing over from cluster %d to cluster %d", p.active, i))
+		p.active = i
+	}
+}
+
+// clusterReachable returns true if at least one of the hosts of the pool can be reached
+func clusterReachable(pool *ConnectionPool) bool {
+	timeout := 3 * time.Second
+	if pool.conf.TimeOut != 0 && pool.conf.TimeOut < timeout {
+		timeout = pool.conf.TimeOut
+	}
+	for _, address := range pool.addresses {
+		if err := pool.Ping(address, timeout); err == nil {
+			return true
+		}
+	}
+	return false
+}
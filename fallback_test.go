@@ -0,0 +1,44 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFallbackPoolUnavailable(t *testing.T) {
+	_, err := ParseConnectionStrings()
+	assert.NotNil(t, err)
+	_, err = ParseConnectionStrings("nebula://127.0.0.1:1", "graphd")
+	assert.NotNil(t, err)
+
+	// nothing listens on port 1 and 2
+	_, err = BuildFallbackPool(DefaultLogger{}, "nebula://127.0.0.1:1?timeout=1s", "nebula://127.0.0.1:2?timeout=1s")
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "every cluster is unavailable")
+	}
+
+	pool, err := BuildFallbackPool(DefaultLogger{},
+		"nebula://127.0.0.1:1?timeout=1s&lazy_init=1", "nebula://127.0.0.1:2?timeout=1s&lazy_init=1")
+	assert.Nil(t, err)
+	defer pool.Close()
+	assert.Equal(t, 0, pool.Active())
+	_, err = pool.Acquire(context.Background())
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "cluster 0")
+		assert.Contains(t, err.Error(), "cluster 1")
+	}
+
+	pool.Close()
+	_, err = pool.Acquire(context.Background())
+	assert.NotNil(t, err)
+}
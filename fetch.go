@@ -0,0 +1,180 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DefaultFetchBatchSize is the max number of ids fetched by a FETCH statement of FetchVertices and FetchEdges
+const DefaultFetchBatchSize = 500
+
+// EdgeKey identifies an edge of an edge type
+type EdgeKey struct {
+	Src  VID
+	Dst  VID
+	Rank int64
+}
+
+func (key EdgeKey) String() string {
+	return key.Src.String() + "->" + key.Dst.String() + "@" + strconv.FormatInt(key.Rank, 10)
+}
+
+// FetchVertices fetches the vertices of the tag, or of any tag if tag is "*", with FETCH PROP statements
+// of at most DefaultFetchBatchSize ids each. It returns the vertices found by id and the ids which were not found.
+// The ids are formatted with their own type, so they must match the vid type of the current space.
+func (session *Session) FetchVertices(ctx context.Context, tag string, vids []VID) (map[VID]*Node, []VID, error) {
+	vids = dedupVIDs(vids)
+	found := make(map[VID]*Node, len(vids))
+	for start := 0; start < len(vids); start += DefaultFetchBatchSize {
+		end := start + DefaultFetchBatchSize
+		if end > len(vids) {
+			end = len(vids)
+		}
+		ids := make([]string, 0, end-start)
+		for _, vid := range vids[start:end] {
+			ids = append(ids, vid.String())
+		}
+		stmt := fmt.Sprintf("FETCH PROP ON %s %s YIELD vertex AS v", schemaName(tag), strings.Join(ids, ", "))
+		resp, err := session.executeChecked(ctx, stmt, "fetch vertices")
+		if err != nil {
+			return nil, nil, err
+		}
+		if err = collectVertices(resp, found); err != nil {
+			return nil, nil, err
+		}
+	}
+	var missing []VID
+	for _, vid := range vids {
+		if _, ok := found[vid]; !ok {
+			missing = append(missing, vid)
+		}
+	}
+	return found, missing, nil
+}
+
+// FetchEdges fetches the edges of the edge type with FETCH PROP statements of at most DefaultFetchBatchSize keys each.
+// It returns the edges found by key and the keys which were not found.
+// The ids are formatted with their own type, so they must match the vid type of the current space.
+func (session *Session) FetchEdges(ctx context.Context, edge string, keys []EdgeKey) (map[EdgeKey]*Relationship, []EdgeKey, error) {
+	keys = dedupEdgeKeys(keys)
+	found := make(map[EdgeKey]*Relationship, len(keys))
+	for start := 0; start < len(keys); start += DefaultFetchBatchSize {
+		end := start + DefaultFetchBatchSize
+		if end > len(keys) {
+			end = len(keys)
+		}
+		ids := make([]string, 0, end-start)
+		for _, key := range keys[start:end] {
+			ids = append(ids, key.String())
+		}
+		stmt := fmt.Sprintf("FETCH PROP ON %s %s YIELD edge AS e", QuoteIdentifier(edge), strings.Join(ids, ", "))
+		resp, err := session.executeChecked(ctx, stmt, "fetch edges")
+		if err != nil {
+			return nil, nil, err
+		}
+		if err = collectEdges(resp, found); err != nil {
+			return nil, nil, err
+		}
+	}
+	var missing []EdgeKey
+	for _, key := range keys {
+		if _, ok := found[key]; !ok {
+			missing = append(missing, key)
+		}
+	}
+	return found, missing, nil
+}
+
+// schemaName quotes the name of a tag, unless it is the wildcard *
+func schemaName(name string) string {
+	if name == "*" {
+		return name
+	}
+	return QuoteIdentifier(name)
+}
+
+// collectVertices adds the vertices of the first column of the result to found
+func collectVertices(resp *ResultSet, found map[VID]*Node) error {
+	for i := 0; i < resp.GetRowSize(); i++ {
+		record, err := resp.GetRowValuesByIndex(i)
+		if err != nil {
+			return err
+		}
+		val, err := record.GetValueByIndex(0)
+		if err != nil {
+			return err
+		}
+		node, err := val.AsNode()
+		if err != nil {
+			return err
+		}
+		vid, err := node.GetID().AsVID()
+		if err != nil {
+			return err
+		}
+		found[vid] = node
+	}
+	return nil
+}
+
+// collectEdges adds the edges of the first column of the result to found
+func collectEdges(resp *ResultSet, found map[EdgeKey]*Relationship) error {
+	for i := 0; i < resp.GetRowSize(); i++ {
+		record, err := resp.GetRowValuesByIndex(i)
+		if err != nil {
+			return err
+		}
+		val, err := record.GetValueByIndex(0)
+		if err != nil {
+			return err
+		}
+		relationship, err := val.AsRelationship()
+		if err != nil {
+			return err
+		}
+		src, err := relationship.GetSrcVertexID().AsVID()
+		if err != nil {
+			return err
+		}
+		dst, err := relationship.GetDstVertexID().AsVID()
+		if err != nil {
+			return err
+		}
+		found[EdgeKey{Src: src, Dst: dst, Rank: relationship.GetRanking()}] = relationship
+	}
+	return nil
+}
+
+func dedupVIDs(vids []VID) []VID {
+	seen := make(map[VID]bool, len(vids))
+	deduped := make([]VID, 0, len(vids))
+	for _, vid := range vids {
+		if !seen[vid] {
+			seen[vid] = true
+			deduped = append(deduped, vid)
+		}
+	}
+	return deduped
+}
+
+func dedupEdgeKeys(keys []EdgeKey) []EdgeKey {
+	seen := make(map[EdgeKey]bool, len(keys))
+	deduped := make([]EdgeKey, 0, len(keys))
+	for _, key := range keys {
+		if !seen[key] {
+			seen[key] = true
+			deduped = append(deduped, key)
+		}
+	}
+	return deduped
+}
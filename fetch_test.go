@@ -0,0 +1,57 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vesoft-inc/nebula-go/v3/nebula"
+	"github.com/vesoft-inc/nebula-go/v3/nebula/graph"
+)
+
+func newTestGraphResultSet(t *testing.T, col string, values ...*nebula.Value) *ResultSet {
+	dataset := &nebula.DataSet{ColumnNames: [][]byte{[]byte(col)}}
+	for _, v := range values {
+		dataset.Rows = append(dataset.Rows, &nebula.Row{Values: []*nebula.Value{v}})
+	}
+	resp := &graph.ExecutionResponse{ErrorCode: nebula.ErrorCode_SUCCEEDED, Data: dataset}
+	resultSet, err := genResultSet(resp, testTimezone)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resultSet
+}
+
+func TestCollectVertices(t *testing.T) {
+	found := make(map[VID]*Node)
+	resp := newTestGraphResultSet(t, "v",
+		&nebula.Value{VVal: getVertex("Bob", 1, 1)}, &nebula.Value{VVal: getVertex("Tom", 1, 1)})
+	assert.Nil(t, collectVertices(resp, found))
+	assert.Len(t, found, 2)
+	assert.Equal(t, []string{"tag0"}, found[StringVID("Bob")].GetTags())
+
+	resp = newTestGraphResultSet(t, "v", &nebula.Value{VVal: getVertexInt(100, 0, 0)})
+	assert.Nil(t, collectVertices(resp, found))
+	assert.Contains(t, found, IntVID(100))
+}
+
+func TestCollectEdges(t *testing.T) {
+	found := make(map[EdgeKey]*Relationship)
+	resp := newTestGraphResultSet(t, "e", &nebula.Value{EVal: getEdge("Bob", "Tom", 1)})
+	assert.Nil(t, collectEdges(resp, found))
+	key := EdgeKey{Src: StringVID("Bob"), Dst: StringVID("Tom"), Rank: 100}
+	assert.Contains(t, found, key)
+	assert.Equal(t, `"Bob"->"Tom"@100`, key.String())
+}
+
+func TestDedupVIDs(t *testing.T) {
+	assert.Equal(t, []VID{StringVID("a"), IntVID(1)},
+		dedupVIDs([]VID{StringVID("a"), IntVID(1), StringVID("a")}))
+}
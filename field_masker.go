@@ -0,0 +1,72 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"context"
+
+	"github.com/vesoft-inc/nebula-go/v3/nebula"
+)
+
+// MaskedField is a property of a vertex or an edge passed to a FieldMasker
+type MaskedField struct {
+	// The tag of the property of a vertex, empty for an edge
+	Tag string
+	// The edge type of the property of an edge, empty for a vertex
+	Edge string
+	Prop string
+}
+
+// FieldMasker returns the value replacing a property when it is read, e.g. RedactedValue() or MaskedString("***")
+// for the PII properties the caller's role may not see, or the value itself. A nil value is encoded as null.
+// It is applied by the JSON encoding of the vertices and edges, including the vertices nested in lists,
+// maps and paths, and by the decoding of the ogm and repo packages.
+type FieldMasker func(field MaskedField, value *ValueWrapper) (*ValueWrapper, error)
+
+// RedactedValue returns a null value, to replace a property which must not be read
+func RedactedValue() *ValueWrapper {
+	return &ValueWrapper{value: &nebula.Value{NVal: nebula.NullTypePtr(nebula.NullType___NULL__)}}
+}
+
+// MaskedString returns a string value, to replace a property with a partially masked one
+func MaskedString(s string) *ValueWrapper {
+	return &ValueWrapper{value: &nebula.Value{SVal: []byte(s)}}
+}
+
+// Mask applies the masker to the property value, the masker can be nil
+func (masker FieldMasker) Mask(field MaskedField, value *ValueWrapper) (*ValueWrapper, error) {
+	if masker == nil {
+		return value, nil
+	}
+	masked, err := masker(field, value)
+	if err != nil {
+		return nil, err
+	}
+	if masked == nil {
+		return RedactedValue(), nil
+	}
+	return masked, nil
+}
+
+type fieldMaskerKey struct{}
+
+// WithFieldMasker returns a context carrying the masker of the reads made with it,
+// e.g. a masker bound to the role of the caller of an HTTP request
+func WithFieldMasker(ctx context.Context, masker FieldMasker) context.Context {
+	return context.WithValue(ctx, fieldMaskerKey{}, masker)
+}
+
+// FieldMaskerFrom returns the masker of the context, nil if it has none
+func FieldMaskerFrom(ctx context.Context) FieldMasker {
+	if ctx == nil {
+		return nil
+	}
+	masker, _ := ctx.Value(fieldMaskerKey{}).(FieldMasker)
+	return masker
+}
@@ -0,0 +1,52 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vesoft-inc/nebula-go/v3/nebula"
+)
+
+func TestFieldMasker(t *testing.T) {
+	var masked []MaskedField
+	masker := FieldMasker(func(field MaskedField, value *ValueWrapper) (*ValueWrapper, error) {
+		masked = append(masked, field)
+		if field.Tag == "tag1" {
+			return nil, nil
+		}
+		if field.Edge == "classmate" {
+			return MaskedString("***"), nil
+		}
+		return value, nil
+	})
+	opts := JSONOptions{Masker: masker}
+
+	// the vertices nested in a list are masked too
+	vertices := ValueWrapper{&nebula.Value{LVal: &nebula.NList{Values: []*nebula.Value{
+		{VVal: getVertexInt(1, 2, 1)},
+	}}}, testTimezone}
+	b, err := vertices.MarshalJSONWithOptions(opts)
+	assert.Nil(t, err)
+	assert.JSONEq(t, `[{"vid":1,"tags":{"tag0":{"prop0":0},"tag1":{"prop0":null}}}]`, string(b))
+	assert.Equal(t, []MaskedField{{Tag: "tag0", Prop: "prop0"}, {Tag: "tag1", Prop: "prop0"}}, masked)
+
+	edge := ValueWrapper{&nebula.Value{EVal: getEdge("Alice", "Bob", 1)}, testTimezone}
+	b, err = edge.MarshalJSONWithOptions(opts)
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"src":"Alice","dst":"Bob","name":"classmate","ranking":100,"props":{"prop0":"***"}}`, string(b))
+
+	assert.Nil(t, FieldMaskerFrom(context.Background()))
+	assert.NotNil(t, FieldMaskerFrom(WithFieldMasker(context.Background(), masker)))
+	value, err := FieldMasker(nil).Mask(MaskedField{}, MaskedString("a"))
+	assert.Nil(t, err)
+	assert.Equal(t, MaskedString("a"), value)
+}
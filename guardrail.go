@@ -0,0 +1,206 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// DefaultGuardrailOperators are the plan operators of full scans and cartesian products
+var DefaultGuardrailOperators = []string{
+	"ScanVertices",
+	"ScanEdges",
+	"TagIndexFullScan",
+	"EdgeIndexFullScan",
+	"CartesianProduct",
+	"BiCartesianProduct",
+}
+
+// DefaultPlanCacheSize is the max number of statement fingerprints whose verdict is cached by a Guardrail
+const DefaultPlanCacheSize = 4096
+
+// GuardrailError is returned for a statement whose plan contains a denied operator
+type GuardrailError struct {
+	Stmt      string
+	Operators []string
+}
+
+func (e *GuardrailError) Error() string {
+	return fmt.Sprintf("failed to execute %s: the plan contains the denied operators %s",
+		e.Stmt, strings.Join(e.Operators, ", "))
+}
+
+// PlanCacheStats are the statistics of the plan cache of a Guardrail
+type PlanCacheStats struct {
+	// The number of executions whose verdict was cached
+	Hits int64
+	// The number of executions which were explained
+	Misses int64
+	// The number of verdicts evicted because the cache was full
+	Evictions int64
+	// The number of cached verdicts
+	Size int
+}
+
+// Guardrail EXPLAINs the statements whose fingerprint it has not seen yet, see FingerprintStatement,
+// and rejects those whose plan contains a denied operator with a *GuardrailError.
+// The verdict of a fingerprint is cached, so that a familiar statement costs no extra round trip,
+// the least recently used verdict being evicted when the cache is full.
+// Statements which can not be explained, e.g. DDL, are executed unchecked, and explained again the next time
+// as the failure of their EXPLAIN may be transient.
+type Guardrail struct {
+	denied  map[string]bool
+	maxSize int
+
+	mu        sync.Mutex
+	verdicts  map[string]*list.Element
+	lru       list.List
+	hits      int64
+	misses    int64
+	evictions int64
+}
+
+type planVerdict struct {
+	digest    string
+	operators []string
+}
+
+// NewGuardrail returns a guardrail denying the operators and caching up to cacheSize verdicts.
+// No operator means DefaultGuardrailOperators and a cacheSize lower than 1 means DefaultPlanCacheSize.
+func NewGuardrail(cacheSize int, operators ...string) *Guardrail {
+	if len(operators) == 0 {
+		operators = DefaultGuardrailOperators
+	}
+	if cacheSize < 1 {
+		cacheSize = DefaultPlanCacheSize
+	}
+	denied := make(map[string]bool, len(operators))
+	for _, op := range operators {
+		denied[strings.ToLower(op)] = true
+	}
+	return &Guardrail{denied: denied, maxSize: cacheSize, verdicts: make(map[string]*list.Element)}
+}
+
+// GuardrailInterceptor returns the interceptor of a new guardrail with the default cache size, see NewGuardrail
+func GuardrailInterceptor(operators ...string) Interceptor {
+	return NewGuardrail(0, operators...).Interceptor()
+}
+
+// Interceptor returns the interceptor checking the statements, see WithInterceptors
+func (g *Guardrail) Interceptor() Interceptor {
+	return func(ctx context.Context, stmt string, params map[string]interface{}, invoker Invoker) (*ResultSet, error) {
+		if isExplained(stmt) {
+			return invoker(ctx, stmt, params)
+		}
+		digest := FingerprintStatement(stmt).Digest
+		ops, known := g.lookup(digest)
+		if !known {
+			plan, err := invoker(ctx, explainStmt(stmt), params)
+			if err != nil {
+				return nil, err
+			}
+			if !plan.IsSucceed() {
+				return invoker(ctx, stmt, params)
+			}
+			ops = deniedOperators(plan, g.denied)
+			g.store(digest, ops)
+		}
+		if len(ops) > 0 {
+			return nil, &GuardrailError{Stmt: stmt, Operators: ops}
+		}
+		return invoker(ctx, stmt, params)
+	}
+}
+
+// Stats returns the statistics of the plan cache
+func (g *Guardrail) Stats() PlanCacheStats {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return PlanCacheStats{Hits: g.hits, Misses: g.misses, Evictions: g.evictions, Size: g.lru.Len()}
+}
+
+// InvalidateTemplate removes the verdict of the fingerprint, e.g. after an index was created,
+// so that the next statement of the fingerprint is explained again. It returns false if there was none.
+func (g *Guardrail) InvalidateTemplate(fingerprint StatementFingerprint) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	e, ok := g.verdicts[fingerprint.Digest]
+	if ok {
+		g.lru.Remove(e)
+		delete(g.verdicts, fingerprint.Digest)
+	}
+	return ok
+}
+
+// InvalidateAll removes every verdict, e.g. after a schema change
+func (g *Guardrail) InvalidateAll() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.verdicts = make(map[string]*list.Element)
+	g.lru.Init()
+}
+
+func (g *Guardrail) lookup(digest string) ([]string, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	e, ok := g.verdicts[digest]
+	if !ok {
+		g.misses++
+		return nil, false
+	}
+	g.hits++
+	g.lru.MoveToFront(e)
+	return e.Value.(*planVerdict).operators, true
+}
+
+func (g *Guardrail) store(digest string, ops []string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if e, ok := g.verdicts[digest]; ok {
+		e.Value.(*planVerdict).operators = ops
+		g.lru.MoveToFront(e)
+		return
+	}
+	if g.lru.Len() >= g.maxSize {
+		oldest := g.lru.Back()
+		g.lru.Remove(oldest)
+		delete(g.verdicts, oldest.Value.(*planVerdict).digest)
+		g.evictions++
+	}
+	g.verdicts[digest] = g.lru.PushFront(&planVerdict{digest: digest, operators: ops})
+}
+
+// deniedOperators returns the denied operators of the plan of a successful EXPLAIN
+func deniedOperators(plan *ResultSet, denied map[string]bool) []string {
+	if plan.GetPlanDesc() == nil {
+		return nil
+	}
+	var ops []string
+	for _, node := range plan.GetPlanDesc().GetPlanNodeDescs() {
+		name := string(node.GetName())
+		if denied[strings.ToLower(name)] && !containsString(ops, name) {
+			ops = append(ops, name)
+		}
+	}
+	return ops
+}
+
+// isExplained returns true for EXPLAIN and PROFILE statements
+func isExplained(stmt string) bool {
+	fields := strings.Fields(stmt)
+	if len(fields) == 0 {
+		return false
+	}
+	keyword := strings.ToUpper(fields[0])
+	return keyword == "EXPLAIN" || keyword == "PROFILE"
+}
@@ -0,0 +1,127 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vesoft-inc/nebula-go/v3/nebula"
+	"github.com/vesoft-inc/nebula-go/v3/nebula/graph"
+)
+
+func TestGuardrailInterceptor(t *testing.T) {
+	interceptor := GuardrailInterceptor()
+	var executed []string
+	invoker := func(ctx context.Context, stmt string, params map[string]interface{}) (*ResultSet, error) {
+		executed = append(executed, stmt)
+		resp := newTestResultSet(t, nil)
+		if strings.HasPrefix(stmt, "EXPLAIN") {
+			operator := "IndexScan"
+			if strings.Contains(stmt, "MATCH (v)") {
+				operator = "ScanVertices"
+			}
+			resp.resp.PlanDesc = &graph.PlanDescription{PlanNodeDescs: []*graph.PlanNodeDescription{
+				{Name: []byte("Project")}, {Name: []byte(operator)}, {Name: []byte(operator)},
+			}}
+		}
+		return resp, nil
+	}
+
+	_, err := interceptor(context.Background(), "MATCH (v) RETURN v LIMIT 10", nil, invoker)
+	assert.EqualError(t, err, "failed to execute MATCH (v) RETURN v LIMIT 10: the plan contains the denied operators ScanVertices")
+	_, err = interceptor(context.Background(), "MATCH (v) RETURN v LIMIT 20", nil, invoker)
+	assert.IsType(t, &GuardrailError{}, err)
+
+	for i := 0; i < 2; i++ {
+		_, err = interceptor(context.Background(), "LOOKUP ON player WHERE player.name == 'Tim' YIELD id(vertex)", nil, invoker)
+		assert.Nil(t, err)
+	}
+	_, err = interceptor(context.Background(), "EXPLAIN MATCH (v) RETURN v", nil, invoker)
+	assert.Nil(t, err)
+
+	assert.Equal(t, []string{
+		"EXPLAIN MATCH (v) RETURN v LIMIT 10",
+		"EXPLAIN LOOKUP ON player WHERE player.name == 'Tim' YIELD id(vertex)",
+		"LOOKUP ON player WHERE player.name == 'Tim' YIELD id(vertex)",
+		"LOOKUP ON player WHERE player.name == 'Tim' YIELD id(vertex)",
+		"EXPLAIN MATCH (v) RETURN v",
+	}, executed)
+}
+
+func TestGuardrailInterceptorOperators(t *testing.T) {
+	interceptor := GuardrailInterceptor("indexscan")
+	invoker := func(ctx context.Context, stmt string, params map[string]interface{}) (*ResultSet, error) {
+		resp := newTestResultSet(t, nil)
+		resp.resp.PlanDesc = &graph.PlanDescription{PlanNodeDescs: []*graph.PlanNodeDescription{{Name: []byte("IndexScan")}}}
+		return resp, nil
+	}
+	_, err := interceptor(context.Background(), "LOOKUP ON player YIELD id(vertex)", nil, invoker)
+	if assert.IsType(t, &GuardrailError{}, err) {
+		assert.Equal(t, []string{"IndexScan"}, err.(*GuardrailError).Operators)
+	}
+}
+
+func TestGuardrailPlanCache(t *testing.T) {
+	guardrail := NewGuardrail(2)
+	interceptor := guardrail.Interceptor()
+	explained := 0
+	invoker := func(ctx context.Context, stmt string, params map[string]interface{}) (*ResultSet, error) {
+		if strings.HasPrefix(stmt, "EXPLAIN") {
+			explained++
+		}
+		return newTestResultSet(t, nil), nil
+	}
+	for _, stmt := range []string{"FETCH PROP ON player 'p1' YIELD vertex", "FETCH PROP ON player 'p2' YIELD vertex",
+		"GO FROM 'p1' OVER follow YIELD dst(edge)", "SHOW HOSTS", "FETCH PROP ON player 'p3' YIELD vertex"} {
+		_, err := interceptor(context.Background(), stmt, nil, invoker)
+		assert.Nil(t, err)
+	}
+	assert.Equal(t, 4, explained)
+	assert.Equal(t, PlanCacheStats{Hits: 1, Misses: 4, Evictions: 2, Size: 2}, guardrail.Stats())
+
+	assert.True(t, guardrail.InvalidateTemplate(FingerprintStatement("SHOW HOSTS")))
+	assert.False(t, guardrail.InvalidateTemplate(FingerprintStatement("SHOW HOSTS")))
+	_, err := interceptor(context.Background(), "SHOW HOSTS", nil, invoker)
+	assert.Nil(t, err)
+	assert.Equal(t, 5, explained)
+
+	guardrail.InvalidateAll()
+	assert.Equal(t, 0, guardrail.Stats().Size)
+}
+
+func TestGuardrailFailedExplainNotCached(t *testing.T) {
+	guardrail := NewGuardrail(0)
+	interceptor := guardrail.Interceptor()
+	explainFails := true
+	var executed []string
+	invoker := func(ctx context.Context, stmt string, params map[string]interface{}) (*ResultSet, error) {
+		executed = append(executed, stmt)
+		resp := newTestResultSet(t, nil)
+		if strings.HasPrefix(stmt, "EXPLAIN") {
+			if explainFails {
+				resp.resp.ErrorCode = nebula.ErrorCode_E_EXECUTION_ERROR
+				return resp, nil
+			}
+			resp.resp.PlanDesc = &graph.PlanDescription{PlanNodeDescs: []*graph.PlanNodeDescription{{Name: []byte("ScanVertices")}}}
+		}
+		return resp, nil
+	}
+
+	_, err := interceptor(context.Background(), "MATCH (v) RETURN v", nil, invoker)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, guardrail.Stats().Size)
+
+	explainFails = false
+	_, err = interceptor(context.Background(), "MATCH (v) RETURN v", nil, invoker)
+	assert.IsType(t, &GuardrailError{}, err)
+	assert.Equal(t, []string{"EXPLAIN MATCH (v) RETURN v", "MATCH (v) RETURN v", "EXPLAIN MATCH (v) RETURN v"}, executed)
+}
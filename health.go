@@ -0,0 +1,196 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// HostStatus is a host of a service of the cluster, as shown by SHOW HOSTS
+type HostStatus struct {
+	Host HostAddress
+	// ONLINE or OFFLINE
+	Status  string
+	Version string
+	// The number of partition leaders per space, storage hosts only
+	Leaders map[string]int64
+	// The number of partitions per space, storage hosts only
+	Partitions map[string]int64
+}
+
+// IsOnline returns true if the status of the host is ONLINE
+func (h HostStatus) IsOnline() bool {
+	return strings.EqualFold(h.Status, "ONLINE")
+}
+
+// ServiceHealth is the health of the hosts of a service
+type ServiceHealth struct {
+	Online  int
+	Offline int
+	Hosts   []HostStatus
+}
+
+// ClusterHealth is the health of the graph, meta and storage services of a cluster
+type ClusterHealth struct {
+	Graph   ServiceHealth
+	Meta    ServiceHealth
+	Storage ServiceHealth
+	// The difference between the highest and the lowest number of partition leaders
+	// of the online storage hosts, per space
+	LeaderImbalance map[string]int64
+	// The difference between the highest and the lowest number of partitions
+	// of the online storage hosts, per space
+	PartitionImbalance map[string]int64
+}
+
+// Healthy returns true if every host of every service is online
+func (h ClusterHealth) Healthy() bool {
+	return h.Graph.Offline == 0 && h.Meta.Offline == 0 && h.Storage.Offline == 0 &&
+		h.Graph.Online > 0 && h.Meta.Online > 0 && h.Storage.Online > 0
+}
+
+// Balanced returns true if the partitions and their leaders are evenly distributed over the storage hosts,
+// i.e. if no host has more than one leader or partition of a space more than another.
+func (h ClusterHealth) Balanced() bool {
+	for _, n := range h.LeaderImbalance {
+		if n > 1 {
+			return false
+		}
+	}
+	for _, n := range h.PartitionImbalance {
+		if n > 1 {
+			return false
+		}
+	}
+	return true
+}
+
+// ClusterHealth returns the health of the cluster from SHOW HOSTS GRAPH, META and STORAGE,
+// using a session acquired with the credentials of the config of the pool.
+func (pool *ConnectionPool) ClusterHealth(ctx context.Context) (*ClusterHealth, error) {
+	session, err := pool.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer session.Release()
+
+	health := &ClusterHealth{}
+	for _, s := range []struct {
+		role    string
+		service *ServiceHealth
+	}{
+		{"GRAPH", &health.Graph},
+		{"META", &health.Meta},
+		{"STORAGE", &health.Storage},
+	} {
+		resp, err := session.executeAdmin(ctx, "SHOW HOSTS "+s.role, "show hosts "+strings.ToLower(s.role))
+		if err != nil {
+			return nil, err
+		}
+		if *s.service, err = parseServiceHealth(resp); err != nil {
+			return nil, err
+		}
+	}
+	health.LeaderImbalance = imbalance(health.Storage.Hosts, func(h HostStatus) map[string]int64 { return h.Leaders })
+	health.PartitionImbalance = imbalance(health.Storage.Hosts, func(h HostStatus) map[string]int64 { return h.Partitions })
+	return health, nil
+}
+
+func parseServiceHealth(resp *ResultSet) (ServiceHealth, error) {
+	var service ServiceHealth
+	for i := 0; i < resp.GetRowSize(); i++ {
+		record, err := resp.GetRowValuesByIndex(i)
+		if err != nil {
+			return ServiceHealth{}, err
+		}
+		var h HostStatus
+		var port int64
+		r := recordReader{record: record}
+		r.string("Host", &h.Host.Host)
+		r.int("Port", &port)
+		r.string("Status", &h.Status)
+		if record.hasColName("Version") {
+			r.string("Version", &h.Version)
+		}
+		if record.hasColName("Leader distribution") {
+			var leaders, partitions string
+			r.string("Leader distribution", &leaders)
+			r.string("Partition distribution", &partitions)
+			if r.err == nil {
+				h.Leaders, r.err = parseDistribution(leaders)
+			}
+			if r.err == nil {
+				h.Partitions, r.err = parseDistribution(partitions)
+			}
+		}
+		if r.err != nil {
+			return ServiceHealth{}, fmt.Errorf("failed to show hosts: %s", r.err.Error())
+		}
+		h.Host.Port = int(port)
+		if h.IsOnline() {
+			service.Online++
+		} else {
+			service.Offline++
+		}
+		service.Hosts = append(service.Hosts, h)
+	}
+	return service, nil
+}
+
+// parseDistribution parses a distribution such as "basketballplayer:5, test:3" or "No valid partition"
+func parseDistribution(s string) (map[string]int64, error) {
+	dist := make(map[string]int64)
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		i := strings.LastIndexByte(item, ':')
+		if i < 0 {
+			// "No valid partition"
+			continue
+		}
+		n, err := strconv.ParseInt(strings.TrimSpace(item[i+1:]), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid distribution: %s", s)
+		}
+		dist[strings.TrimSpace(item[:i])] = n
+	}
+	return dist, nil
+}
+
+// imbalance returns the difference between the highest and the lowest count of the online hosts, per space
+func imbalance(hosts []HostStatus, counts func(HostStatus) map[string]int64) map[string]int64 {
+	spaces := make(map[string]bool)
+	for _, h := range hosts {
+		for space := range counts(h) {
+			spaces[space] = true
+		}
+	}
+	result := make(map[string]int64, len(spaces))
+	for space := range spaces {
+		first := true
+		var min, max int64
+		for _, h := range hosts {
+			if !h.IsOnline() {
+				continue
+			}
+			n := counts(h)[space]
+			if first || n < min {
+				min = n
+			}
+			if first || n > max {
+				max = n
+			}
+			first = false
+		}
+		result[space] = max - min
+	}
+	return result
+}
@@ -0,0 +1,93 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Heartbeat is reported periodically while a statement executed by ExecuteWithHeartbeat runs
+type Heartbeat struct {
+	// The time elapsed since the statement was sent
+	Elapsed time.Duration
+	// The graph service answered the heartbeat
+	Alive bool
+	// The query of the session as shown by SHOW QUERIES, nil if it is not listed,
+	// e.g. because it has just finished or the graph service executing it is unreachable
+	Query *RunningQuery
+	// The error of the heartbeat if the graph service did not answer within the interval
+	Err error
+}
+
+// ExecuteWithHeartbeat executes the statement like ExecuteWithContext and, while it runs,
+// calls onHeartbeat every interval with the result of SHOW QUERIES executed on a separate session
+// acquired from the pool with the credentials of its config. It lets the caller tell a slow query,
+// which is listed with its server side duration, from an unreachable graph service.
+// onHeartbeat is called from another goroutine and never after ExecuteWithHeartbeat has returned.
+func (session *Session) ExecuteWithHeartbeat(ctx context.Context, stmt string, params map[string]interface{},
+	interval time.Duration, onHeartbeat func(Heartbeat)) (*ResultSet, error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("failed to execute with heartbeat: invalid interval %s", interval)
+	}
+	side, err := session.connPool.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire heartbeat session: %s", err.Error())
+	}
+	defer side.Release()
+
+	clock := session.connPool.conf.Clock
+	start := clock.Now()
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		timer := clock.NewTimer(interval)
+		defer timer.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-timer.C():
+			}
+			beatCtx, cancel := context.WithTimeout(ctx, interval)
+			queries, err := side.ShowQueries(beatCtx, false)
+			cancel()
+			beat := Heartbeat{Elapsed: clock.Now().Sub(start), Alive: err == nil, Err: err}
+			if err == nil {
+				beat.Query = findSessionQuery(queries, session.GetSessionID())
+			}
+			select {
+			case <-done:
+				return
+			default:
+			}
+			onHeartbeat(beat)
+			timer.Reset(interval)
+		}
+	}()
+
+	resp, err := session.ExecuteWithContext(ctx, stmt, params)
+	close(done)
+	wg.Wait()
+	return resp, err
+}
+
+// findSessionQuery returns the query of the session, or nil if it is not listed
+func findSessionQuery(queries []RunningQuery, sessionID int64) *RunningQuery {
+	for i := range queries {
+		if queries[i].SessionID == sessionID {
+			return &queries[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,22 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindSessionQuery(t *testing.T) {
+	queries := []RunningQuery{{SessionID: 1, PlanID: 10}, {SessionID: 2, PlanID: 20}}
+	assert.Equal(t, int64(20), findSessionQuery(queries, 2).PlanID)
+	assert.Nil(t, findSessionQuery(queries, 3))
+	assert.Nil(t, findSessionQuery(nil, 1))
+}
@@ -22,6 +22,10 @@ type HostAddress struct {
 func DomainToIP(addresses []HostAddress) ([]HostAddress, error) {
 	var newHostsList []HostAddress
 	for _, host := range addresses {
+		if host.IsUnixSocket() {
+			newHostsList = append(newHostsList, host)
+			continue
+		}
 		// Get ip from domain
 		ips, err := net.LookupIP(host.Host)
 		if err != nil {
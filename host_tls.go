@@ -0,0 +1,109 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"crypto/tls"
+	"fmt"
+)
+
+// WithHostTLS overrides the TLS config of the pool for the connections to a host, e.g. for a host reached
+// through a TLS-terminating proxy while the others are reached directly. A nil config disables TLS for the host.
+// The host must be given as it is passed to the pool, before its name is resolved.
+func WithHostTLS(host HostAddress, config *tls.Config) PoolConfOption {
+	return func(conf *PoolConfig) {
+		overrides := make(map[HostAddress]*tls.Config, len(conf.HostTLS)+1)
+		for h, c := range conf.HostTLS {
+			overrides[h] = c
+		}
+		overrides[host] = config
+		conf.HostTLS = overrides
+	}
+}
+
+// WithTLSServerName sets the server name verified against the certificates of the graph services,
+// and sent with SNI, when the hosts are IPs or load balancers whose name does not match the certificates.
+// It does not apply to the TLS configs which already have a ServerName.
+func WithTLSServerName(name string) PoolConfOption {
+	return func(conf *PoolConfig) {
+		conf.TLSServerName = name
+	}
+}
+
+// withServerName returns a copy of the config with the server name, if the config has none
+func withServerName(config *tls.Config, name string) *tls.Config {
+	if config == nil || name == "" || config.ServerName != "" {
+		return config
+	}
+	config = config.Clone()
+	config.ServerName = name
+	return config
+}
+
+// resolveHostTLS returns the TLS overrides of the config keyed by the resolved addresses of the hosts
+func resolveHostTLS(overrides map[HostAddress]*tls.Config, addresses, resolved []HostAddress) map[HostAddress]*tls.Config {
+	if len(overrides) == 0 {
+		return nil
+	}
+	byResolved := make(map[HostAddress]*tls.Config, len(overrides))
+	if len(addresses) != len(resolved) {
+		for host, config := range overrides {
+			byResolved[host] = config
+		}
+		return byResolved
+	}
+	for i, host := range addresses {
+		if config, ok := overrides[host]; ok {
+			byResolved[resolved[i]] = config
+		}
+	}
+	return byResolved
+}
+
+// prepareTLS applies the server name, the TLS policy and the pinned certificates of the pool config
+// to the TLS config of the pool and to the TLS configs of the hosts
+func (pool *ConnectionPool) prepareTLS() error {
+	prepare := func(config *tls.Config) (*tls.Config, error) {
+		config, err := withTLSPolicy(withServerName(config, pool.conf.TLSServerName), pool.conf.TLSMinVersion, pool.conf.TLSCipherSuites)
+		if err != nil {
+			return nil, err
+		}
+		return withPinnedCertificates(config, pool.conf.PinnedCertificates)
+	}
+	var err error
+	if pool.sslConfig, err = prepare(pool.sslConfig); err != nil {
+		return fmt.Errorf("failed to prepare the TLS config of the pool: %s", err.Error())
+	}
+	for host, config := range pool.hostTLS {
+		if pool.hostTLS[host], err = prepare(config); err != nil {
+			return fmt.Errorf("failed to prepare the TLS config of host %s:%d: %s", host.Host, host.Port, err.Error())
+		}
+	}
+	return nil
+}
+
+// tlsConfigFor returns the TLS config of the connections to the host
+func (pool *ConnectionPool) tlsConfigFor(host HostAddress) *tls.Config {
+	if config, ok := pool.hostTLS[host]; ok {
+		return config
+	}
+	return pool.sslConfig
+}
+
+// tlsModeConfig returns the TLS config of a TLS mode of a connection string, or nil if TLS is disabled
+func tlsModeConfig(mode string) *tls.Config {
+	switch mode {
+	case TLSEnabled:
+		return &tls.Config{}
+	case TLSSkipVerify:
+		return &tls.Config{InsecureSkipVerify: true}
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,43 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"crypto/tls"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHostTLS(t *testing.T) {
+	proxy := HostAddress{Host: "proxy", Port: 9669}
+	direct := HostAddress{Host: "graphd", Port: 9669}
+	proxyTLS := &tls.Config{ServerName: "proxy"}
+	conf := NewPoolConf(WithHostTLS(proxy, proxyTLS), WithHostTLS(direct, nil))
+
+	resolved := []HostAddress{{Host: "10.0.0.1", Port: 9669}, {Host: "10.0.0.2", Port: 9669}, {Host: "10.0.0.3", Port: 9669}}
+	pool := &ConnectionPool{
+		sslConfig: &tls.Config{},
+		hostTLS:   resolveHostTLS(conf.HostTLS, []HostAddress{proxy, direct, {Host: "other", Port: 9669}}, resolved),
+	}
+	assert.True(t, pool.tlsConfigFor(resolved[0]) == proxyTLS)
+	assert.Nil(t, pool.tlsConfigFor(resolved[1]))
+	assert.True(t, pool.tlsConfigFor(resolved[2]) == pool.sslConfig)
+}
+
+func TestWithServerName(t *testing.T) {
+	assert.Nil(t, withServerName(nil, "graphd"))
+	config := &tls.Config{}
+	assert.True(t, withServerName(config, "") == config)
+	named := withServerName(config, "graphd")
+	assert.Equal(t, "graphd", named.ServerName)
+	assert.Equal(t, "", config.ServerName)
+	assert.Equal(t, "graphd", withServerName(named, "other").ServerName)
+	assert.Equal(t, "lb", NewPoolConf(WithTLSServerName("lb")).TLSServerName)
+}
@@ -0,0 +1,136 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+// Package idempotency skips the writes whose idempotency key was already applied within a window,
+// e.g. the edges inserted again when an at-least-once queue redelivers a message.
+//
+// The applied keys are recorded in a Store. A key is reserved before its write and released
+// if the write fails, so that a redelivery applies it, concurrent writes of a key are applied once.
+package idempotency
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	nebula "github.com/vesoft-inc/nebula-go/v3"
+)
+
+// Store records the idempotency keys of the applied writes.
+// Implementations must be safe for concurrent use.
+type Store interface {
+	// Reserve records the key, it returns false if the key was recorded less than window ago
+	Reserve(ctx context.Context, key string, window time.Duration) (bool, error)
+	// Release forgets the key of a write which failed
+	Release(ctx context.Context, key string) error
+}
+
+// Deduplicator applies each write at most once per key within a window
+type Deduplicator struct {
+	store  Store
+	window time.Duration
+}
+
+// New returns a deduplicator recording the keys in the store for window
+func New(store Store, window time.Duration) *Deduplicator {
+	return &Deduplicator{store: store, window: window}
+}
+
+// Do calls write unless the key was applied within the window, e.g. around the write helpers
+// of the repo or ogm packages. It returns false without calling write for a duplicate.
+func (d *Deduplicator) Do(ctx context.Context, key string, write func(ctx context.Context) error) (bool, error) {
+	if key == "" {
+		return false, fmt.Errorf("failed to deduplicate write: empty idempotency key")
+	}
+	reserved, err := d.store.Reserve(ctx, key, d.window)
+	if err != nil {
+		return false, fmt.Errorf("failed to reserve idempotency key %s: %s", key, err.Error())
+	}
+	if !reserved {
+		return false, nil
+	}
+	if err := write(ctx); err != nil {
+		if rerr := d.store.Release(ctx, key); rerr != nil {
+			return false, fmt.Errorf("%s, and failed to release idempotency key %s: %s", err.Error(), key, rerr.Error())
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// Execute executes the write statement with the session unless the key was applied within the window.
+// It returns false without executing the statement for a duplicate.
+func (d *Deduplicator) Execute(ctx context.Context, session *nebula.Session, key, stmt string,
+	params map[string]interface{}) (bool, error) {
+	return d.Do(ctx, key, func(ctx context.Context) error {
+		resp, err := session.ExecuteWithContext(ctx, stmt, params)
+		if err != nil {
+			return err
+		}
+		if !resp.IsSucceed() {
+			return fmt.Errorf("failed to execute %s: %s", stmt, resp.GetErrorMsg())
+		}
+		return nil
+	})
+}
+
+// MemoryStore is a Store keeping the keys in memory, it only deduplicates the writes of a process
+type MemoryStore struct {
+	mu        sync.Mutex
+	keys      map[string]time.Time
+	lastSweep time.Time
+	clock     nebula.Clock
+}
+
+// NewMemoryStore returns an empty memory store
+func NewMemoryStore() *MemoryStore {
+	return NewMemoryStoreWithClock(nebula.RealClock())
+}
+
+// NewMemoryStoreWithClock returns an empty memory store whose keys expire by the clock,
+// e.g. the clock of the pool config
+func NewMemoryStoreWithClock(clock nebula.Clock) *MemoryStore {
+	return &MemoryStore{keys: make(map[string]time.Time), clock: clock}
+}
+
+// Reserve implements Store
+func (s *MemoryStore) Reserve(ctx context.Context, key string, window time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.clock.Now()
+	// the expired keys are swept once per window, so that the store does not grow with every key ever seen
+	if now.Sub(s.lastSweep) >= window {
+		for k, at := range s.keys {
+			if now.Sub(at) >= window {
+				delete(s.keys, k)
+			}
+		}
+		s.lastSweep = now
+	}
+	if at, ok := s.keys[key]; ok && now.Sub(at) < window {
+		return false, nil
+	}
+	s.keys[key] = now
+	return true, nil
+}
+
+// Release implements Store
+func (s *MemoryStore) Release(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.keys, key)
+	return nil
+}
+
+// Len returns the number of keys recorded, including the expired keys not swept yet
+func (s *MemoryStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.keys)
+}
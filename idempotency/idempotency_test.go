@@ -0,0 +1,58 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	nebula "github.com/vesoft-inc/nebula-go/v3"
+)
+
+func TestDeduplicator(t *testing.T) {
+	clock := nebula.NewManualClock(time.Now())
+	store := NewMemoryStoreWithClock(clock)
+	d := New(store, time.Minute)
+	ctx := context.Background()
+
+	writes := 0
+	write := func(context.Context) error {
+		writes++
+		return nil
+	}
+	applied, err := d.Do(ctx, "msg-1", write)
+	assert.Nil(t, err)
+	assert.True(t, applied)
+	applied, err = d.Do(ctx, "msg-1", write)
+	assert.Nil(t, err)
+	assert.False(t, applied)
+	assert.Equal(t, 1, writes)
+
+	// a failed write is applied by the redelivery
+	failure := errors.New("connection reset")
+	applied, err = d.Do(ctx, "msg-2", func(context.Context) error { return failure })
+	assert.Equal(t, failure, err)
+	assert.False(t, applied)
+	applied, err = d.Do(ctx, "msg-2", write)
+	assert.Nil(t, err)
+	assert.True(t, applied)
+
+	// the keys expire after the window and are swept
+	clock.Advance(time.Minute)
+	applied, err = d.Do(ctx, "msg-1", write)
+	assert.Nil(t, err)
+	assert.True(t, applied)
+	assert.Equal(t, 1, store.Len())
+
+	_, err = d.Do(ctx, "", write)
+	assert.NotNil(t, err)
+}
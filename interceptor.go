@@ -0,0 +1,95 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"context"
+	"fmt"
+)
+
+// Invoker executes a statement with parameters
+type Invoker func(ctx context.Context, stmt string, params map[string]interface{}) (*ResultSet, error)
+
+// Interceptor intercepts the execution of a statement by a session.
+// It must call invoker to execute the statement, possibly with a modified context, statement or parameters.
+type Interceptor func(ctx context.Context, stmt string, params map[string]interface{}, invoker Invoker) (*ResultSet, error)
+
+// WithInterceptors appends interceptors to the chain of the pool,
+// the first interceptor is the outermost one.
+func WithInterceptors(interceptors ...Interceptor) PoolConfOption {
+	return func(conf *PoolConfig) {
+		conf.Interceptors = append(conf.Interceptors, interceptors...)
+	}
+}
+
+// chainInterceptors returns an invoker calling the interceptors in order before invoker
+func chainInterceptors(interceptors []Interceptor, invoker Invoker) Invoker {
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, next := interceptors[i], invoker
+		invoker = func(ctx context.Context, stmt string, params map[string]interface{}) (*ResultSet, error) {
+			return interceptor(ctx, stmt, params, next)
+		}
+	}
+	return invoker
+}
+
+// ExecuteMethod is the method name passed to unary client interceptors
+const ExecuteMethod = "/nebula.graph.GraphService/Execute"
+
+// ExecuteRequest is the request passed to unary client interceptors
+type ExecuteRequest struct {
+	Stmt   string
+	Params map[string]interface{}
+}
+
+// ExecuteReply is the reply passed to unary client interceptors
+type ExecuteReply struct {
+	ResultSet *ResultSet
+}
+
+// UnaryInvoker has the shape of grpc.UnaryInvoker without the connection and call options
+type UnaryInvoker func(ctx context.Context, method string, req, reply interface{}) error
+
+// UnaryClientInterceptor has the shape of grpc.UnaryClientInterceptor without the connection and call options.
+// An existing gRPC interceptor can be adapted with a closure passing a nil connection, e.g.
+//
+//	func(ctx context.Context, method string, req, reply interface{}, invoker UnaryInvoker) error {
+//		return grpcInterceptor(ctx, method, req, reply, nil,
+//			func(ctx context.Context, method string, req, reply interface{}, _ *grpc.ClientConn, _ ...grpc.CallOption) error {
+//				return invoker(ctx, method, req, reply)
+//			})
+//	}
+type UnaryClientInterceptor func(ctx context.Context, method string, req, reply interface{}, invoker UnaryInvoker) error
+
+// FromUnaryClientInterceptor adapts a gRPC style unary client interceptor to an Interceptor.
+// The request is an *ExecuteRequest and the reply an *ExecuteReply, the interceptor may replace the request.
+func FromUnaryClientInterceptor(interceptor UnaryClientInterceptor) Interceptor {
+	return func(ctx context.Context, stmt string, params map[string]interface{}, invoker Invoker) (*ResultSet, error) {
+		reply := &ExecuteReply{}
+		err := interceptor(ctx, ExecuteMethod, &ExecuteRequest{Stmt: stmt, Params: params}, reply,
+			func(ctx context.Context, method string, req, rep interface{}) error {
+				r, ok := req.(*ExecuteRequest)
+				if !ok {
+					return fmt.Errorf("failed to execute: invalid request type %T", req)
+				}
+				resultSet, err := invoker(ctx, r.Stmt, r.Params)
+				if err != nil {
+					return err
+				}
+				if rp, ok := rep.(*ExecuteReply); ok {
+					rp.ResultSet = resultSet
+				}
+				return nil
+			})
+		if err != nil {
+			return nil, err
+		}
+		return reply.ResultSet, nil
+	}
+}
@@ -0,0 +1,57 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vesoft-inc/nebula-go/v3/nebula/graph"
+)
+
+func TestChainInterceptors(t *testing.T) {
+	var calls []string
+	record := func(name string) Interceptor {
+		return func(ctx context.Context, stmt string, params map[string]interface{}, invoker Invoker) (*ResultSet, error) {
+			calls = append(calls, name)
+			return invoker(ctx, stmt+" /* "+name+" */", params)
+		}
+	}
+	invoker := chainInterceptors([]Interceptor{record("first"), record("second")},
+		func(ctx context.Context, stmt string, params map[string]interface{}) (*ResultSet, error) {
+			calls = append(calls, stmt)
+			return &ResultSet{}, nil
+		})
+	_, err := invoker(context.Background(), "YIELD 1", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"first", "second", "YIELD 1 /* first */ /* second */"}, calls)
+}
+
+func TestFromUnaryClientInterceptor(t *testing.T) {
+	type key struct{}
+	var method string
+	unary := func(ctx context.Context, m string, req, reply interface{}, invoker UnaryInvoker) error {
+		method = m
+		r := req.(*ExecuteRequest)
+		r.Stmt = "YIELD 2"
+		return invoker(context.WithValue(ctx, key{}, "token"), m, r, reply)
+	}
+	expected := &ResultSet{resp: &graph.ExecutionResponse{}}
+	interceptor := FromUnaryClientInterceptor(unary)
+	resultSet, err := interceptor(context.Background(), "YIELD 1", nil,
+		func(ctx context.Context, stmt string, params map[string]interface{}) (*ResultSet, error) {
+			assert.Equal(t, "YIELD 2", stmt)
+			assert.Equal(t, "token", ctx.Value(key{}))
+			return expected, nil
+		})
+	assert.Nil(t, err)
+	assert.Equal(t, expected, resultSet)
+	assert.Equal(t, ExecuteMethod, method)
+}
@@ -0,0 +1,54 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"context"
+)
+
+// Executor executes statements, it is implemented by Session
+type Executor interface {
+	Execute(stmt string, opts ...ExecOption) (*ResultSet, error)
+	ExecuteWithParameter(stmt string, params map[string]interface{}, opts ...ExecOption) (*ResultSet, error)
+	ExecuteWithContext(ctx context.Context, stmt string, params map[string]interface{}, opts ...ExecOption) (*ResultSet, error)
+}
+
+// Sessioner is a session of the graph service, it is implemented by Session
+type Sessioner interface {
+	Executor
+	ExecuteJson(stmt string) ([]byte, error)
+	ExecuteJsonWithParameter(stmt string, params map[string]interface{}) ([]byte, error)
+	GetSessionID() int64
+	Release()
+}
+
+// Pooler acquires sessions, it is implemented by ConnectionPool.
+// Wrapping a Pooler, e.g. to decorate the sessions it returns, and passing it to NewClient
+// changes the behavior of the client without forking the driver.
+type Pooler interface {
+	AcquireSession(ctx context.Context) (Sessioner, error)
+	Stats() PoolStats
+	IsClosed() bool
+	Close()
+}
+
+var (
+	_ Executor  = (*Session)(nil)
+	_ Sessioner = (*Session)(nil)
+	_ Pooler    = (*ConnectionPool)(nil)
+)
+
+// AcquireSession is Acquire returning the session as a Sessioner
+func (pool *ConnectionPool) AcquireSession(ctx context.Context) (Sessioner, error) {
+	session, err := pool.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return session, nil
+}
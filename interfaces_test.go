@@ -0,0 +1,54 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeSession struct {
+	Sessioner
+	rs       *ResultSet
+	stmts    []string
+	released bool
+}
+
+func (s *fakeSession) ExecuteWithContext(ctx context.Context, stmt string, params map[string]interface{},
+	opts ...ExecOption) (*ResultSet, error) {
+	s.stmts = append(s.stmts, stmt)
+	return s.rs, nil
+}
+
+func (s *fakeSession) Release() {
+	s.released = true
+}
+
+type fakePooler struct {
+	Pooler
+	session *fakeSession
+}
+
+func (p *fakePooler) AcquireSession(ctx context.Context) (Sessioner, error) {
+	return p.session, nil
+}
+
+func TestClientWithPooler(t *testing.T) {
+	session := &fakeSession{rs: newTestResultSet(t, []string{"n"}, []interface{}{1})}
+	client := NewClient(&fakePooler{session: session})
+
+	rs, err := client.Query(context.Background(), "YIELD 1 AS n", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, rs.GetRowSize())
+	assert.Equal(t, []string{"YIELD 1 AS n"}, session.stmts)
+	assert.True(t, session.released)
+	assert.Nil(t, client.Pool())
+}
@@ -0,0 +1,120 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// JsonResponse is the typed envelope of the json returned by ExecuteJson.
+// The json returned by the graph service is kept in Raw so that it can be passed through as is.
+type JsonResponse struct {
+	Results []JsonResult `json:"results"`
+	Errors  []JsonError  `json:"errors"`
+	// The json returned by the graph service
+	Raw json.RawMessage `json:"-"`
+}
+
+// JsonResult is a result of a JsonResponse
+type JsonResult struct {
+	Columns     []string   `json:"columns"`
+	Data        []JsonData `json:"data"`
+	LatencyInUs int64      `json:"latencyInUs"`
+	SpaceName   string     `json:"spaceName"`
+}
+
+// JsonData is a row of a JsonResult, values are left undecoded
+type JsonData struct {
+	Row  []json.RawMessage `json:"row"`
+	Meta []json.RawMessage `json:"meta"`
+}
+
+// JsonError is an error of a JsonResponse, a code of 0 means the execution succeeded
+type JsonError struct {
+	Code    ErrorCode `json:"code"`
+	Message string    `json:"message"`
+}
+
+// ParseJsonResponse parses the json returned by ExecuteJson
+func ParseJsonResponse(raw []byte) (*JsonResponse, error) {
+	var resp JsonResponse
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return nil, fmt.Errorf("failed to parse json response: %s", err.Error())
+	}
+	resp.Raw = raw
+	return &resp, nil
+}
+
+// IsSucceed returns true if none of the errors of the response has a non zero code
+func (resp *JsonResponse) IsSucceed() bool {
+	return resp.Err() == nil
+}
+
+// Err returns the first error of the response with a non zero code, or nil
+func (resp *JsonResponse) Err() error {
+	for _, e := range resp.Errors {
+		if e.Code != ErrorCode_SUCCEEDED {
+			return fmt.Errorf("error code: %d, error message: %s", e.Code, e.Message)
+		}
+	}
+	return nil
+}
+
+// ExecuteJsonWithContext returns the result of the given query both as the json returned by the graph service
+// and as its typed envelope. It returns once the context is done even if the query is still running,
+// in which case the session can only be used again after the query has finished.
+func (session *Session) ExecuteJsonWithContext(ctx context.Context, stmt string, params map[string]interface{}) (*JsonResponse, error) {
+	if err := session.checkExplicitSpace(ctx, stmt); err != nil {
+		return nil, err
+	}
+	ctx = session.connPool.withTenant(ctx)
+	tenant := TenantFrom(ctx)
+	stmt = session.correctSpace(ctx, stmt)
+	started := ctx.Err() == nil
+	resp, err := runWithContext(ctx, func() (interface{}, error) {
+		return session.executeJsonWithParameter(tenant, stmt, params)
+	})
+	if err != nil {
+		if started && ctx.Err() != nil {
+			session.killOnCancel(tenant)
+		}
+		return nil, err
+	}
+	return ParseJsonResponse(resp.([]byte))
+}
+
+// runWithContext runs f and returns its result, or the error of the context if it is done first
+func runWithContext(ctx context.Context, f func() (interface{}, error)) (interface{}, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if ctx.Done() == nil {
+		return f()
+	}
+	type result struct {
+		resp interface{}
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		var r result
+		defer func() { done <- r }()
+		// a panic of the goroutine could not be recovered by the caller
+		defer recoverPanic("execute", nil, &r.err)
+		r.resp, r.err = f()
+	}()
+	select {
+	case r := <-done:
+		return r.resp, r.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
@@ -0,0 +1,52 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseJsonResponse(t *testing.T) {
+	raw := []byte(`{"results":[{"columns":["name","age"],"data":[{"row":["Bob",10],"meta":[null,null]}],` +
+		`"latencyInUs":120,"spaceName":"test"}],"errors":[{"code":0}]}`)
+	resp, err := ParseJsonResponse(raw)
+	assert.Nil(t, err)
+	assert.True(t, resp.IsSucceed())
+	assert.Equal(t, []string{"name", "age"}, resp.Results[0].Columns)
+	assert.Equal(t, `"Bob"`, string(resp.Results[0].Data[0].Row[0]))
+	assert.Equal(t, int64(120), resp.Results[0].LatencyInUs)
+	assert.Equal(t, "test", resp.Results[0].SpaceName)
+	assert.Equal(t, raw, []byte(resp.Raw))
+
+	resp, err = ParseJsonResponse([]byte(`{"errors":[{"code":-1004,"message":"SyntaxError"}]}`))
+	assert.Nil(t, err)
+	assert.False(t, resp.IsSucceed())
+	assert.EqualError(t, resp.Err(), "error code: -1004, error message: SyntaxError")
+
+	_, err = ParseJsonResponse([]byte(`not json`))
+	assert.NotNil(t, err)
+}
+
+func TestRunWithContext(t *testing.T) {
+	resp, err := runWithContext(context.Background(), func() (interface{}, error) { return 1, nil })
+	assert.Nil(t, err)
+	assert.Equal(t, 1, resp)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = runWithContext(ctx, func() (interface{}, error) {
+		time.Sleep(time.Second)
+		return nil, nil
+	})
+	assert.Equal(t, context.DeadlineExceeded, err)
+}
@@ -0,0 +1,53 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// DefaultKillTimeout is the deadline of the KILL QUERY issued when the context of an execution is done
+const DefaultKillTimeout = 10 * time.Second
+
+// killOnCancel kills the query in flight of the session in the background if KillOnCancel is set.
+// The session itself can not be used, it is busy until the query returns.
+func (session *Session) killOnCancel(tenant string) {
+	if !session.connPool.conf.KillOnCancel {
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), DefaultKillTimeout)
+		defer cancel()
+		if err := session.connPool.killSessionQuery(ctx, session.GetSessionID()); err != nil {
+			loggerFor(session.log, tenant).Warn(fmt.Sprintf("Failed to kill the canceled query of session %d: %s",
+				session.GetSessionID(), err.Error()))
+		}
+	}()
+}
+
+// killSessionQuery kills the running query of the session, if any, using a session acquired from the pool
+func (pool *ConnectionPool) killSessionQuery(ctx context.Context, sessionID int64) error {
+	side, err := pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer side.Release()
+	queries, err := side.ShowQueries(ctx, false)
+	if err != nil {
+		return err
+	}
+	query := findSessionQuery(queries, sessionID)
+	if query == nil {
+		// the query finished meanwhile
+		return nil
+	}
+	return side.KillQuery(ctx, sessionID, query.PlanID)
+}
@@ -0,0 +1,47 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import "time"
+
+// LatencyBreakdown splits the latency of a statement between the graph service, the network and the driver
+type LatencyBreakdown struct {
+	// The time from sending the request to receiving the response, measured by the driver
+	RoundTrip time.Duration
+	// The execution time reported by the graph service
+	Server time.Duration
+	// The time spent by the graph service optimizing the plan, only reported for PROFILE and EXPLAIN statements
+	Planning time.Duration
+	// The time spent by the driver decoding the response
+	Decode time.Duration
+}
+
+// Network returns the part of the round trip which was not spent executing the statement,
+// i.e. the network, the queueing in the graph service and the serialization of the response
+func (l LatencyBreakdown) Network() time.Duration {
+	if l.RoundTrip < l.Server {
+		return 0
+	}
+	return l.RoundTrip - l.Server
+}
+
+// GetLatencyBreakdown returns the latencies of the statement, e.g. for an interceptor to tell apart
+// the server time from the network time. The driver measured latencies are 0 if the result set
+// was not returned by a session, e.g. when it comes from a cache.
+func (res ResultSet) GetLatencyBreakdown() LatencyBreakdown {
+	l := LatencyBreakdown{
+		RoundTrip: res.roundTrip,
+		Server:    time.Duration(res.resp.LatencyInUs) * time.Microsecond,
+		Decode:    res.decode,
+	}
+	if res.resp.PlanDesc != nil {
+		l.Planning = time.Duration(res.resp.PlanDesc.OptimizeTimeInUs) * time.Microsecond
+	}
+	return l
+}
@@ -0,0 +1,233 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"math/bits"
+	"sort"
+	"sync"
+	"time"
+)
+
+// OtherStatementsTemplate is the template of the histogram of the statements
+// whose fingerprint exceeds the max number of histograms of WithLatencyHistograms
+const OtherStatementsTemplate = "other"
+
+// the number of buckets per power of two of the histograms, bounding their relative error to 1/16
+const histogramSubBuckets = 16
+
+// WithLatencyHistograms tracks the latency of the statements executed by the sessions of the pool
+// in a histogram per statement fingerprint, see FingerprintStatement and LatencyHistograms.
+// At most maxStatements fingerprints are tracked, the latencies of the other statements
+// are recorded in a single histogram whose template is OtherStatementsTemplate.
+func WithLatencyHistograms(maxStatements int) PoolConfOption {
+	return func(conf *PoolConfig) {
+		conf.LatencyHistograms = maxStatements
+	}
+}
+
+// HistogramBucket is a bucket of a LatencyHistogram
+type HistogramBucket struct {
+	// The exclusive upper bound of the latencies of the bucket
+	UpperBound time.Duration
+	Count      int64
+}
+
+// LatencyHistogram is a snapshot of the latencies of the statements sharing a fingerprint.
+// The latencies are recorded with a microsecond resolution and a relative error of at most 1/16.
+type LatencyHistogram struct {
+	StatementFingerprint
+	// The tenant of the statements, see WithTenantFromContext
+	Tenant string
+	Count  int64
+	Sum    time.Duration
+	Min    time.Duration
+	Max    time.Duration
+	// The non empty buckets in increasing order
+	Buckets []HistogramBucket
+}
+
+// Quantile returns the latency below which the fraction q of the statements completed, e.g. 0.99 for the p99,
+// or 0 if the histogram is empty
+func (h LatencyHistogram) Quantile(q float64) time.Duration {
+	if h.Count == 0 {
+		return 0
+	}
+	rank := int64(q*float64(h.Count) + 0.5)
+	if rank < 1 {
+		rank = 1
+	}
+	var seen int64
+	for _, b := range h.Buckets {
+		if seen += b.Count; seen >= rank {
+			if b.UpperBound > h.Max {
+				return h.Max
+			}
+			return b.UpperBound
+		}
+	}
+	return h.Max
+}
+
+// Merge adds the counts of another histogram, e.g. to compute the latencies of several pools
+func (h *LatencyHistogram) Merge(other LatencyHistogram) {
+	if other.Count == 0 {
+		return
+	}
+	if h.Count == 0 || other.Min < h.Min {
+		h.Min = other.Min
+	}
+	if other.Max > h.Max {
+		h.Max = other.Max
+	}
+	h.Count += other.Count
+	h.Sum += other.Sum
+	counts := make(map[time.Duration]int64, len(h.Buckets)+len(other.Buckets))
+	for _, b := range append(h.Buckets, other.Buckets...) {
+		counts[b.UpperBound] += b.Count
+	}
+	h.Buckets = make([]HistogramBucket, 0, len(counts))
+	for upper, count := range counts {
+		h.Buckets = append(h.Buckets, HistogramBucket{UpperBound: upper, Count: count})
+	}
+	sort.Slice(h.Buckets, func(i, j int) bool { return h.Buckets[i].UpperBound < h.Buckets[j].UpperBound })
+}
+
+// histogramBucket returns the index of the bucket of a latency in microseconds:
+// the values below histogramSubBuckets have their own bucket, each following power of two
+// is split in histogramSubBuckets buckets
+func histogramBucket(us uint64) int {
+	if us < histogramSubBuckets {
+		return int(us)
+	}
+	shift := bits.Len64(us) - 5
+	return (shift+1)*histogramSubBuckets + int(us>>uint(shift)) - histogramSubBuckets
+}
+
+// histogramUpperBound returns the exclusive upper bound in microseconds of a bucket
+func histogramUpperBound(bucket int) uint64 {
+	if bucket < histogramSubBuckets {
+		return uint64(bucket) + 1
+	}
+	shift := uint(bucket/histogramSubBuckets - 1)
+	return uint64(bucket%histogramSubBuckets+histogramSubBuckets+1) << shift
+}
+
+type latencyHistogram struct {
+	fingerprint StatementFingerprint
+	tenant      string
+	count       int64
+	sum         time.Duration
+	min         time.Duration
+	max         time.Duration
+	// sparse, most statements only spread over a few buckets
+	buckets map[int]int64
+}
+
+func (h *latencyHistogram) record(latency time.Duration) {
+	if latency < 0 {
+		latency = 0
+	}
+	if h.count == 0 || latency < h.min {
+		h.min = latency
+	}
+	if latency > h.max {
+		h.max = latency
+	}
+	h.count++
+	h.sum += latency
+	h.buckets[histogramBucket(uint64(latency/time.Microsecond))]++
+}
+
+func (h *latencyHistogram) snapshot() LatencyHistogram {
+	s := LatencyHistogram{
+		StatementFingerprint: h.fingerprint,
+		Tenant:               h.tenant,
+		Count:                h.count,
+		Sum:                  h.sum,
+		Min:                  h.min,
+		Max:                  h.max,
+		Buckets:              make([]HistogramBucket, 0, len(h.buckets)),
+	}
+	for bucket, count := range h.buckets {
+		upper := time.Duration(histogramUpperBound(bucket)) * time.Microsecond
+		s.Buckets = append(s.Buckets, HistogramBucket{UpperBound: upper, Count: count})
+	}
+	sort.Slice(s.Buckets, func(i, j int) bool { return s.Buckets[i].UpperBound < s.Buckets[j].UpperBound })
+	return s
+}
+
+// latencyHistograms are the latency histograms of a pool by tenant and statement digest
+type latencyHistograms struct {
+	mu         sync.Mutex
+	histograms map[histogramKey]*latencyHistogram
+	other      *latencyHistogram
+}
+
+type histogramKey struct {
+	tenant string
+	digest string
+}
+
+func (l *latencyHistograms) record(max int, tenant, stmt string, latency time.Duration) {
+	fingerprint := FingerprintStatement(stmt)
+	key := histogramKey{tenant: tenant, digest: fingerprint.Digest}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.histograms == nil {
+		l.histograms = make(map[histogramKey]*latencyHistogram)
+	}
+	h, ok := l.histograms[key]
+	if !ok {
+		if len(l.histograms) >= max {
+			if l.other == nil {
+				l.other = &latencyHistogram{
+					fingerprint: StatementFingerprint{Template: OtherStatementsTemplate},
+					buckets:     make(map[int]int64),
+				}
+			}
+			h = l.other
+		} else {
+			h = &latencyHistogram{fingerprint: fingerprint, tenant: tenant, buckets: make(map[int]int64)}
+			l.histograms[key] = h
+		}
+	}
+	h.record(latency)
+}
+
+// LatencyHistograms returns a snapshot of the latency histograms of the pool ordered by template and tenant,
+// see WithLatencyHistograms. The latency of a statement is measured from the call to the session
+// to the decoded result, it includes the waits for the session lock and the reconnections.
+func (pool *ConnectionPool) LatencyHistograms() []LatencyHistogram {
+	l := &pool.latencyHistograms
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	snapshots := make([]LatencyHistogram, 0, len(l.histograms)+1)
+	for _, h := range l.histograms {
+		snapshots = append(snapshots, h.snapshot())
+	}
+	if l.other != nil {
+		snapshots = append(snapshots, l.other.snapshot())
+	}
+	sort.Slice(snapshots, func(i, j int) bool {
+		if snapshots[i].Template != snapshots[j].Template {
+			return snapshots[i].Template < snapshots[j].Template
+		}
+		return snapshots[i].Tenant < snapshots[j].Tenant
+	})
+	return snapshots
+}
+
+// ResetLatencyHistograms clears the latency histograms of the pool, e.g. after they have been exported
+func (pool *ConnectionPool) ResetLatencyHistograms() {
+	l := &pool.latencyHistograms
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.histograms, l.other = nil, nil
+}
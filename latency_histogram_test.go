@@ -0,0 +1,63 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHistogramBuckets(t *testing.T) {
+	for _, us := range []uint64{0, 1, 15, 16, 17, 31, 32, 33, 1000, 123456, 1 << 40} {
+		bucket := histogramBucket(us)
+		assert.True(t, us < histogramUpperBound(bucket), "%d below the upper bound of its bucket", us)
+		if bucket > 0 {
+			assert.True(t, us >= histogramUpperBound(bucket-1), "%d above the upper bound of the previous bucket", us)
+		}
+	}
+	assert.Equal(t, histogramBucket(32), histogramBucket(33))
+	assert.NotEqual(t, histogramBucket(33), histogramBucket(34))
+}
+
+func TestLatencyHistograms(t *testing.T) {
+	pool := &ConnectionPool{conf: NewPoolConf(WithLatencyHistograms(2))}
+	l := &pool.latencyHistograms
+	for i := 1; i <= 100; i++ {
+		l.record(2, "", "FETCH PROP ON player 'p1' YIELD vertex AS v", time.Duration(i)*time.Millisecond)
+	}
+	l.record(2, "", "FETCH PROP ON player 'p2' YIELD vertex AS v", 5*time.Millisecond)
+	l.record(2, "", "GO FROM 'p1' OVER follow YIELD dst(edge)", time.Second)
+	l.record(2, "", "SHOW HOSTS", time.Microsecond)
+
+	histograms := pool.LatencyHistograms()
+	assert.Len(t, histograms, 3)
+	fetch := histograms[0]
+	assert.Equal(t, "fetch prop on player ? yield vertex as v", fetch.Template)
+	assert.Equal(t, int64(101), fetch.Count)
+	assert.Equal(t, time.Millisecond, fetch.Min)
+	assert.Equal(t, 100*time.Millisecond, fetch.Max)
+	p50 := fetch.Quantile(0.5)
+	assert.True(t, p50 >= 50*time.Millisecond && p50 <= 54*time.Millisecond, "p50 %s", p50)
+	assert.Equal(t, 100*time.Millisecond, fetch.Quantile(1))
+
+	assert.Equal(t, "go from ? over follow yield dst(edge)", histograms[1].Template)
+	assert.Equal(t, OtherStatementsTemplate, histograms[2].Template)
+	assert.Equal(t, int64(1), histograms[2].Count)
+
+	merged := histograms[1]
+	merged.Merge(histograms[2])
+	assert.Equal(t, int64(2), merged.Count)
+	assert.Equal(t, time.Microsecond, merged.Min)
+	assert.Len(t, merged.Buckets, 2)
+
+	pool.ResetLatencyHistograms()
+	assert.Empty(t, pool.LatencyHistograms())
+}
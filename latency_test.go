@@ -0,0 +1,38 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vesoft-inc/nebula-go/v3/nebula/graph"
+)
+
+func TestLatencyBreakdown(t *testing.T) {
+	resultSet := newTestResultSet(t, []string{"n"})
+	resultSet.resp.LatencyInUs = 1500
+	resultSet.resp.PlanDesc = &graph.PlanDescription{OptimizeTimeInUs: 200}
+	resultSet.roundTrip = 4 * time.Millisecond
+	resultSet.decode = time.Millisecond
+
+	l := resultSet.GetLatencyBreakdown()
+	assert.Equal(t, LatencyBreakdown{
+		RoundTrip: 4 * time.Millisecond,
+		Server:    1500 * time.Microsecond,
+		Planning:  200 * time.Microsecond,
+		Decode:    time.Millisecond,
+	}, l)
+	assert.Equal(t, 2500*time.Microsecond, l.Network())
+
+	// not measured by the driver
+	resultSet.roundTrip = 0
+	assert.Equal(t, time.Duration(0), resultSet.GetLatencyBreakdown().Network())
+}
@@ -0,0 +1,174 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ListenerType is the type of a listener of a space
+type ListenerType string
+
+const (
+	// ListenerElasticsearch syncs the data of a space to the full-text indexes of Elasticsearch
+	ListenerElasticsearch ListenerType = "ELASTICSEARCH"
+)
+
+// Listener is a listener of a partition of a space, as shown by SHOW LISTENER
+type Listener struct {
+	PartID int64
+	Type   ListenerType
+	Host   HostAddress
+	// ONLINE or OFFLINE
+	Status string
+}
+
+// Drainer is a drainer receiving the data of a space replicated from another cluster, as shown by SHOW DRAINERS
+type Drainer struct {
+	Host HostAddress
+	// ONLINE or OFFLINE
+	Status string
+}
+
+// validListenerType returns an error unless the type is one of the listener types of the graph service
+func validListenerType(listenerType ListenerType) error {
+	switch listenerType {
+	case ListenerElasticsearch:
+		return nil
+	}
+	return fmt.Errorf("unknown listener type %q", string(listenerType))
+}
+
+// AddListener adds listeners of the given type to the space
+func (session *Session) AddListener(ctx context.Context, space string, listenerType ListenerType, hosts ...HostAddress) error {
+	if err := validListenerType(listenerType); err != nil {
+		return fmt.Errorf("failed to add listener: %s", err.Error())
+	}
+	_, err := session.executeAdmin(ctx, fmt.Sprintf("USE %s; ADD LISTENER %s %s",
+		QuoteIdentifier(space), listenerType, formatHosts(hosts)), "add listener to space "+space)
+	return err
+}
+
+// RemoveListener removes the listeners of the given type from the space
+func (session *Session) RemoveListener(ctx context.Context, space string, listenerType ListenerType) error {
+	if err := validListenerType(listenerType); err != nil {
+		return fmt.Errorf("failed to remove listener: %s", err.Error())
+	}
+	_, err := session.executeAdmin(ctx, fmt.Sprintf("USE %s; REMOVE LISTENER %s",
+		QuoteIdentifier(space), listenerType), "remove listener from space "+space)
+	return err
+}
+
+// ShowListeners returns the listeners of the space
+func (session *Session) ShowListeners(ctx context.Context, space string) ([]Listener, error) {
+	resp, err := session.executeAdmin(ctx, fmt.Sprintf("USE %s; SHOW LISTENER", QuoteIdentifier(space)),
+		"show listeners of space "+space)
+	if err != nil {
+		return nil, err
+	}
+	return parseListeners(resp)
+}
+
+func parseListeners(resp *ResultSet) ([]Listener, error) {
+	listeners := make([]Listener, 0, resp.GetRowSize())
+	for i := 0; i < resp.GetRowSize(); i++ {
+		record, err := resp.GetRowValuesByIndex(i)
+		if err != nil {
+			return nil, err
+		}
+		var l Listener
+		var listenerType, host string
+		r := recordReader{record: record}
+		r.int("PartId", &l.PartID)
+		r.string("Type", &listenerType)
+		r.string("Host", &host)
+		r.string("Host Status", &l.Status)
+		if r.err == nil {
+			l.Host, r.err = parseHost(host)
+		}
+		if r.err != nil {
+			return nil, fmt.Errorf("failed to show listeners: %s", r.err.Error())
+		}
+		l.Type = ListenerType(strings.ToUpper(listenerType))
+		listeners = append(listeners, l)
+	}
+	return listeners, nil
+}
+
+// AddDrainers adds drainers to the space, drainers are only available in the enterprise edition
+func (session *Session) AddDrainers(ctx context.Context, space string, hosts ...HostAddress) error {
+	_, err := session.executeAdmin(ctx, fmt.Sprintf("USE %s; ADD DRAINER %s",
+		QuoteIdentifier(space), formatHosts(hosts)), "add drainers to space "+space)
+	return err
+}
+
+// RemoveDrainers removes the drainers of the space
+func (session *Session) RemoveDrainers(ctx context.Context, space string) error {
+	_, err := session.executeAdmin(ctx, fmt.Sprintf("USE %s; REMOVE DRAINER", QuoteIdentifier(space)),
+		"remove drainers from space "+space)
+	return err
+}
+
+// ShowDrainers returns the drainers of the space
+func (session *Session) ShowDrainers(ctx context.Context, space string) ([]Drainer, error) {
+	resp, err := session.executeAdmin(ctx, fmt.Sprintf("USE %s; SHOW DRAINERS", QuoteIdentifier(space)),
+		"show drainers of space "+space)
+	if err != nil {
+		return nil, err
+	}
+	return parseDrainers(resp)
+}
+
+func parseDrainers(resp *ResultSet) ([]Drainer, error) {
+	drainers := make([]Drainer, 0, resp.GetRowSize())
+	for i := 0; i < resp.GetRowSize(); i++ {
+		record, err := resp.GetRowValuesByIndex(i)
+		if err != nil {
+			return nil, err
+		}
+		var d Drainer
+		var host string
+		r := recordReader{record: record}
+		r.string("Host", &host)
+		r.string("Status", &d.Status)
+		if r.err == nil {
+			d.Host, r.err = parseHost(host)
+		}
+		if r.err != nil {
+			return nil, fmt.Errorf("failed to show drainers: %s", r.err.Error())
+		}
+		drainers = append(drainers, d)
+	}
+	return drainers, nil
+}
+
+// formatHosts formats hosts as a comma separated list of quoted host:port
+func formatHosts(hosts []HostAddress) string {
+	formatted := make([]string, len(hosts))
+	for i, h := range hosts {
+		formatted[i] = fmt.Sprintf("%s:%d", quoteString(h.Host), h.Port)
+	}
+	return strings.Join(formatted, ", ")
+}
+
+// parseHost parses a host shown by the graph service such as "192.168.8.5":9789
+func parseHost(s string) (HostAddress, error) {
+	i := strings.LastIndexByte(s, ':')
+	if i < 0 {
+		return HostAddress{}, fmt.Errorf("invalid host address: %s", s)
+	}
+	port, err := strconv.Atoi(s[i+1:])
+	if err != nil {
+		return HostAddress{}, fmt.Errorf("invalid host address: %s", s)
+	}
+	return HostAddress{Host: strings.Trim(s[:i], `"`), Port: port}, nil
+}
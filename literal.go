@@ -0,0 +1,94 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FormatValue formats a go value as an nGQL literal which can be interpolated into a statement.
+// It supports nil, booleans, integers, floats, strings, VIDs and the types of RegisterValueEncoder.
+func FormatValue(v interface{}) (string, error) {
+	switch val := v.(type) {
+	case nil:
+		return "NULL", nil
+	case bool:
+		return strconv.FormatBool(val), nil
+	case int:
+		return strconv.FormatInt(int64(val), 10), nil
+	case int8:
+		return strconv.FormatInt(int64(val), 10), nil
+	case int16:
+		return strconv.FormatInt(int64(val), 10), nil
+	case int32:
+		return strconv.FormatInt(int64(val), 10), nil
+	case int64:
+		return strconv.FormatInt(val, 10), nil
+	case uint8:
+		return strconv.FormatUint(uint64(val), 10), nil
+	case uint16:
+		return strconv.FormatUint(uint64(val), 10), nil
+	case uint32:
+		return strconv.FormatUint(uint64(val), 10), nil
+	case float32:
+		return formatFloat(float64(val)), nil
+	case float64:
+		return formatFloat(val), nil
+	case string:
+		return quoteString(val), nil
+	case VID:
+		return val.String(), nil
+	default:
+		if encoded, ok, err := encodeValue(v); ok {
+			if err != nil {
+				return "", err
+			}
+			return FormatValue(encoded)
+		}
+		return "", fmt.Errorf("failed to format value of type %T as a literal", v)
+	}
+}
+
+func formatFloat(f float64) string {
+	s := strconv.FormatFloat(f, 'g', -1, 64)
+	if !strings.ContainsAny(s, ".eEnN") {
+		s += ".0"
+	}
+	return s
+}
+
+// QuoteIdentifier returns name quoted with backticks so that it can be used as a space, tag, edge or property name
+func QuoteIdentifier(name string) string {
+	return "`" + strings.Replace(name, "`", "", -1) + "`"
+}
+
+// quoteString returns s as a double quoted nGQL string literal
+func quoteString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"', '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
@@ -0,0 +1,44 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatValue(t *testing.T) {
+	cases := []struct {
+		value    interface{}
+		expected string
+	}{
+		{nil, "NULL"},
+		{true, "true"},
+		{42, "42"},
+		{int64(-7), "-7"},
+		{1.5, "1.5"},
+		{float32(2), "2.0"},
+		{"a\"b\\c\n", `"a\"b\\c\n"`},
+		{StringVID("x"), `"x"`},
+		{IntVID(3), "3"},
+	}
+	for _, c := range cases {
+		s, err := FormatValue(c.value)
+		assert.Nil(t, err)
+		assert.Equal(t, c.expected, s)
+	}
+	_, err := FormatValue([]int{1})
+	assert.NotNil(t, err)
+}
+
+func TestQuoteIdentifier(t *testing.T) {
+	assert.Equal(t, "`player`", QuoteIdentifier("player"))
+	assert.Equal(t, "`player`", QuoteIdentifier("play`er"))
+}
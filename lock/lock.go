@@ -0,0 +1,189 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+// Package lock provides advisory locks stored as vertices of a graph space,
+// to coordinate jobs sharing a space.
+//
+// A lock is a vertex of a dedicated tag whose vid is the lock name, the space must have a FIXED_STRING vid type.
+// The tag is created with the statement returned by CreateTagStatement.
+// Locks are acquired, renewed and released with conditional upserts, which are atomic per vertex,
+// and expire after their TTL which is evaluated with the clock of the graph service.
+package lock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"strconv"
+	"time"
+
+	nebula "github.com/vesoft-inc/nebula-go/v3"
+)
+
+var (
+	// ErrLocked is returned by Acquire when the lock is held by another owner
+	ErrLocked = errors.New("lock is held by another owner")
+	// ErrNotHeld is returned by Renew and Release when the lock is no longer held by its owner
+	ErrNotHeld = errors.New("lock is not held")
+)
+
+// DefaultTag is the tag used when none is given to NewLocker
+const DefaultTag = "nebula_lock"
+
+// CreateTagStatement returns the statement creating the tag storing the locks
+func CreateTagStatement(tag string) string {
+	return fmt.Sprintf("CREATE TAG IF NOT EXISTS %s(owner string, expires_at int64)", nebula.QuoteIdentifier(tag))
+}
+
+// Locker acquires locks on behalf of an owner
+type Locker struct {
+	pool  *nebula.ConnectionPool
+	space string
+	tag   string
+	owner string
+}
+
+// NewLocker returns a locker storing the locks in the tag of the space.
+// The sessions are acquired from the pool with the credentials of its config.
+// If owner is empty, a unique owner is generated from the hostname and the process ID.
+func NewLocker(pool *nebula.ConnectionPool, space, tag, owner string) *Locker {
+	if tag == "" {
+		tag = DefaultTag
+	}
+	if owner == "" {
+		owner = defaultOwner()
+	}
+	return &Locker{pool: pool, space: space, tag: tag, owner: owner}
+}
+
+func defaultOwner() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	return fmt.Sprintf("%s-%d-%d", host, os.Getpid(), rand.Int63())
+}
+
+// Owner returns the owner of the locks acquired by the locker
+func (l *Locker) Owner() string {
+	return l.owner
+}
+
+// Lock is a lock held by a Locker
+type Lock struct {
+	locker *Locker
+	// The name of the lock
+	Name string
+	// The time the lock expires if it is not renewed, according to the clock of the graph service
+	ExpiresAt time.Time
+}
+
+// Acquire acquires the named lock for ttl, rounded up to the second.
+// It returns ErrLocked if the lock is held by another owner and has not expired.
+// Acquiring a lock already held by the same owner renews it.
+func (l *Locker) Acquire(ctx context.Context, name string, ttl time.Duration) (*Lock, error) {
+	cond := fmt.Sprintf("owner == %s OR expires_at < timestamp()", quote(l.owner))
+	expiresAt, err := l.upsert(ctx, name, l.owner, ttlExpr(ttl), cond)
+	if err == ErrNotHeld {
+		return nil, ErrLocked
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &Lock{locker: l, Name: name, ExpiresAt: expiresAt}, nil
+}
+
+// Renew extends the lock for ttl from now, rounded up to the second.
+// It returns ErrNotHeld if the lock was released or acquired by another owner.
+func (lk *Lock) Renew(ctx context.Context, ttl time.Duration) error {
+	l := lk.locker
+	expiresAt, err := l.upsert(ctx, lk.Name, l.owner, ttlExpr(ttl), fmt.Sprintf("owner == %s", quote(l.owner)))
+	if err != nil {
+		return err
+	}
+	lk.ExpiresAt = expiresAt
+	return nil
+}
+
+// Release releases the lock.
+// It returns ErrNotHeld if the lock was acquired by another owner after it expired.
+func (lk *Lock) Release(ctx context.Context) error {
+	l := lk.locker
+	_, err := l.upsert(ctx, lk.Name, "", "0", fmt.Sprintf("owner == %s", quote(l.owner)))
+	return err
+}
+
+// upsert sets the owner and the expiration of the lock vertex if cond holds, or inserts the vertex if it does not exist,
+// and returns ErrNotHeld if the resulting owner is not the given one
+func (l *Locker) upsert(ctx context.Context, name, owner, expires, cond string) (time.Time, error) {
+	session, err := l.pool.Acquire(ctx)
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer session.Release()
+
+	resp, err := session.ExecuteWithContext(ctx, upsertStatement(l.space, l.tag, name, owner, expires, cond), nil)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if !resp.IsSucceed() {
+		return time.Time{}, fmt.Errorf("failed to upsert lock %s: %s", name, resp.GetErrorMsg())
+	}
+	if resp.GetRowSize() != 1 {
+		return time.Time{}, fmt.Errorf("failed to upsert lock %s: unexpected row count %d", name, resp.GetRowSize())
+	}
+	record, err := resp.GetRowValuesByIndex(0)
+	if err != nil {
+		return time.Time{}, err
+	}
+	val, err := record.GetValueByColName("owner")
+	if err != nil {
+		return time.Time{}, err
+	}
+	if val.IsNull() {
+		return time.Time{}, ErrNotHeld
+	}
+	current, err := val.AsString()
+	if err != nil {
+		return time.Time{}, err
+	}
+	if current != owner {
+		return time.Time{}, ErrNotHeld
+	}
+	val, err = record.GetValueByColName("expires_at")
+	if err != nil {
+		return time.Time{}, err
+	}
+	sec, err := val.AsInt()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(sec, 0), nil
+}
+
+func upsertStatement(space, tag, name, owner, expires, cond string) string {
+	return fmt.Sprintf("USE %s; UPSERT VERTEX ON %s %s SET owner = %s, expires_at = %s WHEN %s "+
+		"YIELD owner AS owner, expires_at AS expires_at",
+		nebula.QuoteIdentifier(space), nebula.QuoteIdentifier(tag), quote(name), quote(owner), expires, cond)
+}
+
+// ttlExpr returns the expression of the expiration of a lock acquired for ttl
+func ttlExpr(ttl time.Duration) string {
+	sec := int64((ttl + time.Second - 1) / time.Second)
+	if sec < 1 {
+		sec = 1
+	}
+	return "timestamp() + " + strconv.FormatInt(sec, 10)
+}
+
+func quote(s string) string {
+	lit, _ := nebula.FormatValue(s)
+	return lit
+}
@@ -0,0 +1,40 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package lock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpsertStatement(t *testing.T) {
+	assert.Equal(t,
+		"USE `jobs`; UPSERT VERTEX ON `nebula_lock` \"daily\\\"import\" SET owner = \"worker-1\", expires_at = timestamp() + 30 "+
+			"WHEN owner == \"worker-1\" OR expires_at < timestamp() YIELD owner AS owner, expires_at AS expires_at",
+		upsertStatement("jobs", DefaultTag, "daily\"import", "worker-1", ttlExpr(30*time.Second),
+			"owner == \"worker-1\" OR expires_at < timestamp()"))
+}
+
+func TestTTLExpr(t *testing.T) {
+	assert.Equal(t, "timestamp() + 1", ttlExpr(0))
+	assert.Equal(t, "timestamp() + 1", ttlExpr(time.Millisecond))
+	assert.Equal(t, "timestamp() + 2", ttlExpr(1500*time.Millisecond))
+	assert.Equal(t, "timestamp() + 60", ttlExpr(time.Minute))
+}
+
+func TestNewLocker(t *testing.T) {
+	l := NewLocker(nil, "jobs", "", "")
+	assert.Equal(t, DefaultTag, l.tag)
+	assert.NotEmpty(t, l.Owner())
+	assert.NotEqual(t, l.Owner(), NewLocker(nil, "jobs", "", "").Owner())
+
+	assert.Equal(t, "CREATE TAG IF NOT EXISTS `nebula_lock`(owner string, expires_at int64)", CreateTagStatement(DefaultTag))
+}
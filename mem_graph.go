@@ -0,0 +1,329 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"sort"
+)
+
+// MemGraph is an in-memory directed multigraph built from query results,
+// for running graph algorithms on the client side.
+//
+// Every vertex has a dense int64 id, in the order the vertices were added, so that the graph can be
+// adapted to the interfaces of graph libraries such as gonum.org/v1/gonum/graph, e.g.
+//
+//	func (g gonumGraph) From(id int64) graph.Nodes {
+//		var nodes []graph.Node
+//		for _, vid := range g.Neighbors(g.VID(id), nebula.DirectionOut) {
+//			nodes = append(nodes, simple.Node(g.ID(vid)))
+//		}
+//		return iterator.NewOrderedNodes(nodes)
+//	}
+type MemGraph struct {
+	vids  []VID
+	ids   map[VID]int64
+	nodes map[VID]*Node
+	out   map[VID][]*Relationship
+	in    map[VID][]*Relationship
+	seen  map[memEdgeID]bool
+	edges []*Relationship
+}
+
+type memEdgeID struct {
+	name string
+	key  EdgeKey
+}
+
+// NewMemGraph returns an empty graph
+func NewMemGraph() *MemGraph {
+	return &MemGraph{
+		ids:   make(map[VID]int64),
+		nodes: make(map[VID]*Node),
+		out:   make(map[VID][]*Relationship),
+		in:    make(map[VID][]*Relationship),
+		seen:  make(map[memEdgeID]bool),
+	}
+}
+
+// NewMemGraphFromResultSet returns the graph of the vertices, edges and paths of the result,
+// including the ones nested in lists and sets such as the columns of GET SUBGRAPH
+func NewMemGraphFromResultSet(resp *ResultSet) (*MemGraph, error) {
+	g := NewMemGraph()
+	for i := 0; i < resp.GetRowSize(); i++ {
+		record, err := resp.GetRowValuesByIndex(i)
+		if err != nil {
+			return nil, err
+		}
+		for j := 0; j < resp.GetColSize(); j++ {
+			val, err := record.GetValueByIndex(j)
+			if err != nil {
+				return nil, err
+			}
+			if err = g.addValue(*val); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return g, nil
+}
+
+// Graph returns the in-memory graph of the subgraph. The vertices are added in the order of their ids,
+// so that the ids of the graph do not depend on the iteration order of the map of the subgraph.
+func (subgraph *Subgraph) Graph() (*MemGraph, error) {
+	g := NewMemGraph()
+	vids := make([]VID, 0, len(subgraph.Nodes))
+	for vid := range subgraph.Nodes {
+		vids = append(vids, vid)
+	}
+	sort.Slice(vids, func(i, j int) bool { return vidLess(vids[i], vids[j]) })
+	for _, vid := range vids {
+		if err := g.AddNode(subgraph.Nodes[vid]); err != nil {
+			return nil, err
+		}
+	}
+	for _, relationship := range subgraph.Relationships {
+		if err := g.AddRelationship(relationship); err != nil {
+			return nil, err
+		}
+	}
+	return g, nil
+}
+
+// vidLess orders the integer vids numerically before the string vids
+func vidLess(a, b VID) bool {
+	if a.isInt != b.isInt {
+		return a.isInt
+	}
+	if a.isInt {
+		return a.intVal < b.intVal
+	}
+	return a.strVal < b.strVal
+}
+
+func (g *MemGraph) addValue(val ValueWrapper) error {
+	switch {
+	case val.IsVertex():
+		node, err := val.AsNode()
+		if err != nil {
+			return err
+		}
+		return g.AddNode(node)
+	case val.IsEdge():
+		relationship, err := val.AsRelationship()
+		if err != nil {
+			return err
+		}
+		return g.AddRelationship(relationship)
+	case val.IsPath():
+		path, err := val.AsPath()
+		if err != nil {
+			return err
+		}
+		return g.AddPath(path)
+	case val.IsList() || val.IsSet():
+		list, err := val.AsList()
+		if val.IsSet() {
+			list, err = val.AsDedupList()
+		}
+		if err != nil {
+			return err
+		}
+		for _, item := range list {
+			if err = g.addValue(item); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// AddNode adds the vertex, replacing the properties of a vertex with the same id
+func (g *MemGraph) AddNode(node *Node) error {
+	vid, err := node.GetID().AsVID()
+	if err != nil {
+		return err
+	}
+	g.addVID(vid)
+	g.nodes[vid] = node
+	return nil
+}
+
+// AddRelationship adds the edge and its vertices, an edge with the same type, ends and rank is only added once
+func (g *MemGraph) AddRelationship(relationship *Relationship) error {
+	src, err := relationship.GetSrcVertexID().AsVID()
+	if err != nil {
+		return err
+	}
+	dst, err := relationship.GetDstVertexID().AsVID()
+	if err != nil {
+		return err
+	}
+	id := memEdgeID{relationship.GetEdgeName(), EdgeKey{Src: src, Dst: dst, Rank: relationship.GetRanking()}}
+	if g.seen[id] {
+		return nil
+	}
+	g.seen[id] = true
+	g.addVID(src)
+	g.addVID(dst)
+	g.out[src] = append(g.out[src], relationship)
+	g.in[dst] = append(g.in[dst], relationship)
+	g.edges = append(g.edges, relationship)
+	return nil
+}
+
+// AddPath adds the vertices and the edges of the path
+func (g *MemGraph) AddPath(path *PathWrapper) error {
+	for _, node := range path.GetNodes() {
+		if err := g.AddNode(node); err != nil {
+			return err
+		}
+	}
+	for _, relationship := range path.GetRelationships() {
+		if err := g.AddRelationship(relationship); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (g *MemGraph) addVID(vid VID) {
+	if _, ok := g.ids[vid]; !ok {
+		g.ids[vid] = int64(len(g.vids))
+		g.vids = append(g.vids, vid)
+	}
+}
+
+// VIDs returns the ids of the vertices in the order they were added
+func (g *MemGraph) VIDs() []VID {
+	return append([]VID(nil), g.vids...)
+}
+
+// Node returns the vertex of the id, or nil if it is only known as the end of an edge
+func (g *MemGraph) Node(vid VID) *Node {
+	return g.nodes[vid]
+}
+
+// Relationships returns the edges in the order they were added
+func (g *MemGraph) Relationships() []*Relationship {
+	return append([]*Relationship(nil), g.edges...)
+}
+
+// ID returns the dense id of the vertex, or -1 if the vertex is not in the graph
+func (g *MemGraph) ID(vid VID) int64 {
+	if id, ok := g.ids[vid]; ok {
+		return id
+	}
+	return -1
+}
+
+// VID returns the vertex of the dense id, which must be in the graph
+func (g *MemGraph) VID(id int64) VID {
+	return g.vids[id]
+}
+
+// Edges returns the edges of the vertex in the direction
+func (g *MemGraph) Edges(vid VID, direction Direction) []*Relationship {
+	switch direction {
+	case DirectionOut:
+		return append([]*Relationship(nil), g.out[vid]...)
+	case DirectionIn:
+		return append([]*Relationship(nil), g.in[vid]...)
+	default:
+		return append(append([]*Relationship(nil), g.out[vid]...), g.in[vid]...)
+	}
+}
+
+// Degree returns the number of edges of the vertex in the direction
+func (g *MemGraph) Degree(vid VID, direction Direction) int {
+	switch direction {
+	case DirectionOut:
+		return len(g.out[vid])
+	case DirectionIn:
+		return len(g.in[vid])
+	default:
+		return len(g.out[vid]) + len(g.in[vid])
+	}
+}
+
+// Neighbors returns the distinct vertices connected to the vertex in the direction, ordered by dense id
+func (g *MemGraph) Neighbors(vid VID, direction Direction) []VID {
+	ids := make(map[int64]bool)
+	if direction != DirectionIn {
+		for _, r := range g.out[vid] {
+			ids[g.ID(relationshipVID(r, false))] = true
+		}
+	}
+	if direction != DirectionOut {
+		for _, r := range g.in[vid] {
+			ids[g.ID(relationshipVID(r, true))] = true
+		}
+	}
+	return g.sortedVIDs(ids)
+}
+
+// HasEdgeBetween returns true if an edge goes from src to dst
+func (g *MemGraph) HasEdgeBetween(src, dst VID) bool {
+	for _, r := range g.out[src] {
+		if relationshipVID(r, false) == dst {
+			return true
+		}
+	}
+	return false
+}
+
+// ConnectedComponents returns the weakly connected components of the graph, ordered by dense id
+func (g *MemGraph) ConnectedComponents() [][]VID {
+	visited := make([]bool, len(g.vids))
+	var components [][]VID
+	for start := range g.vids {
+		if visited[start] {
+			continue
+		}
+		component := make(map[int64]bool)
+		stack := []int64{int64(start)}
+		visited[start] = true
+		for len(stack) > 0 {
+			id := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			component[id] = true
+			for _, neighbor := range g.Neighbors(g.vids[id], DirectionBoth) {
+				if nid := g.ids[neighbor]; !visited[nid] {
+					visited[nid] = true
+					stack = append(stack, nid)
+				}
+			}
+		}
+		components = append(components, g.sortedVIDs(component))
+	}
+	return components
+}
+
+func (g *MemGraph) sortedVIDs(ids map[int64]bool) []VID {
+	sorted := make([]int64, 0, len(ids))
+	for id := range ids {
+		sorted = append(sorted, id)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	vids := make([]VID, len(sorted))
+	for i, id := range sorted {
+		vids[i] = g.vids[id]
+	}
+	return vids
+}
+
+// relationshipVID returns the source vertex of the edge if src is true, its destination vertex otherwise.
+// The ids were validated when the edge was added.
+func relationshipVID(r *Relationship, src bool) VID {
+	val := r.GetDstVertexID()
+	if src {
+		val = r.GetSrcVertexID()
+	}
+	vid, _ := val.AsVID()
+	return vid
+}
@@ -0,0 +1,83 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vesoft-inc/nebula-go/v3/nebula"
+)
+
+func TestMemGraph(t *testing.T) {
+	// GET SUBGRAPH returns lists of vertices and edges
+	resp := newTestGraphResultSet(t, "relationships",
+		&nebula.Value{LVal: &nebula.NList{Values: []*nebula.Value{
+			{EVal: getEdge("a", "b", 0)},
+			{EVal: getEdge("b", "c", 0)},
+			{EVal: getEdge("a", "b", 0)},
+			{EVal: getEdge("x", "y", 0)},
+		}}},
+		&nebula.Value{VVal: getVertex("a", 1, 1)},
+		&nebula.Value{PVal: getPath("p", 2)})
+	g, err := NewMemGraphFromResultSet(resp)
+	assert.Nil(t, err)
+
+	a, b, c := StringVID("a"), StringVID("b"), StringVID("c")
+	assert.Len(t, g.Relationships(), 5)
+	assert.NotNil(t, g.Node(a))
+	assert.Nil(t, g.Node(b))
+	assert.Equal(t, int64(0), g.ID(a))
+	assert.Equal(t, b, g.VID(1))
+	assert.Equal(t, int64(-1), g.ID(StringVID("z")))
+
+	assert.Equal(t, []VID{b}, g.Neighbors(a, DirectionOut))
+	assert.Empty(t, g.Neighbors(a, DirectionIn))
+	assert.Equal(t, []VID{a, c}, g.Neighbors(b, DirectionBoth))
+	assert.Equal(t, 2, g.Degree(b, DirectionBoth))
+	assert.Len(t, g.Edges(b, DirectionIn), 1)
+	assert.True(t, g.HasEdgeBetween(a, b))
+	assert.False(t, g.HasEdgeBetween(b, a))
+
+	components := g.ConnectedComponents()
+	assert.Len(t, components, 3)
+	assert.Equal(t, []VID{a, b, c}, components[0])
+	assert.Equal(t, []VID{StringVID("x"), StringVID("y")}, components[1])
+}
+
+func TestSubgraphGraph(t *testing.T) {
+	resp := newTestGraphResultSet(t, "e", &nebula.Value{EVal: getEdge("a", "b", 0)})
+	found := make(map[EdgeKey]*Relationship)
+	assert.Nil(t, collectEdges(resp, found))
+	subgraph := &Subgraph{Nodes: map[VID]*Node{}}
+	for _, r := range found {
+		subgraph.Relationships = append(subgraph.Relationships, r)
+	}
+	g, err := subgraph.Graph()
+	assert.Nil(t, err)
+	assert.True(t, g.HasEdgeBetween(StringVID("a"), StringVID("b")))
+}
+
+func TestSubgraphGraphOrder(t *testing.T) {
+	subgraph := &Subgraph{Nodes: map[VID]*Node{}}
+	for _, vid := range []string{"d", "b", "e", "a", "c"} {
+		node, err := genNode(getVertex(vid, 0, 0), testTimezone)
+		assert.Nil(t, err)
+		subgraph.Nodes[StringVID(vid)] = node
+	}
+	for _, vid := range []int{3, 1, 2} {
+		node, err := genNode(getVertexInt(vid, 0, 0), testTimezone)
+		assert.Nil(t, err)
+		subgraph.Nodes[IntVID(int64(vid))] = node
+	}
+	g, err := subgraph.Graph()
+	assert.Nil(t, err)
+	assert.Equal(t, []VID{IntVID(1), IntVID(2), IntVID(3),
+		StringVID("a"), StringVID("b"), StringVID("c"), StringVID("d"), StringVID("e")}, g.VIDs())
+}
@@ -0,0 +1,113 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"fmt"
+	"runtime"
+	"sync/atomic"
+
+	"github.com/facebook/fbthrift/thrift/lib/go/thrift"
+)
+
+// WithMemorySoftLimit rejects the executions of the pool while its result sets and in-flight decodes
+// hold more than the given number of bytes, as estimated from the size of the responses.
+// The result sets should be closed once consumed, see ResultSet.Close, rather than left to the garbage collector.
+func WithMemorySoftLimit(bytes int64) PoolConfOption {
+	return func(conf *PoolConfig) {
+		conf.MemorySoftLimit = bytes
+	}
+}
+
+// memoryStats is the memory accounting of a pool. The size of a response is the number of bytes
+// read from the connection, it is accounted as in-flight while the response is decoded,
+// then as buffered until its result set is closed or garbage collected.
+type memoryStats struct {
+	inFlight   int64
+	buffered   int64
+	rejections int64
+}
+
+// inUse returns the bytes held by the result sets and the in-flight decodes
+func (m *memoryStats) inUse() int64 {
+	return atomic.LoadInt64(&m.inFlight) + atomic.LoadInt64(&m.buffered)
+}
+
+// admit returns an error if the bytes in use exceed the soft limit, 0 value meaning no limit
+func (m *memoryStats) admit(limit int64) error {
+	if limit <= 0 {
+		return nil
+	}
+	if inUse := m.inUse(); inUse > limit {
+		atomic.AddInt64(&m.rejections, 1)
+		return fmt.Errorf("failed to execute: the result sets of the pool hold %d bytes, more than the soft limit of %d bytes",
+			inUse, limit)
+	}
+	return nil
+}
+
+func (m *memoryStats) startDecode(size int64) {
+	atomic.AddInt64(&m.inFlight, size)
+}
+
+func (m *memoryStats) endDecode(size int64) {
+	atomic.AddInt64(&m.inFlight, -size)
+}
+
+// track accounts the size of the result set until it is closed, the finalizer releasing it
+// if the result set is garbage collected without being closed
+func (m *memoryStats) track(res *ResultSet, size int64) {
+	if size <= 0 {
+		return
+	}
+	atomic.AddInt64(&m.buffered, size)
+	res.memory = &trackedMemory{stats: m, size: size}
+	runtime.SetFinalizer(res, func(res *ResultSet) {
+		res.memory.release()
+	})
+}
+
+// trackedMemory is the size of a result set accounted as buffered by a pool
+type trackedMemory struct {
+	stats    *memoryStats
+	size     int64
+	released int32
+}
+
+// release removes the size from the buffered bytes, once
+func (t *trackedMemory) release() {
+	if t != nil && atomic.CompareAndSwapInt32(&t.released, 0, 1) {
+		atomic.AddInt64(&t.stats.buffered, -t.size)
+	}
+}
+
+// Close drops the rows of the result set and releases at once their memory accounted by WithMemorySoftLimit.
+// The result set and its copies have no rows once it is closed.
+func (res ResultSet) Close() {
+	res.memory.release()
+	if res.resp != nil {
+		res.resp.Data = nil
+	}
+}
+
+// countingTransport counts the bytes read from the underlying transport
+type countingTransport struct {
+	read int64 // first for its alignment on 32-bit platforms
+	thrift.Transport
+}
+
+func (t *countingTransport) Read(p []byte) (int, error) {
+	n, err := t.Transport.Read(p)
+	atomic.AddInt64(&t.read, int64(n))
+	return n, err
+}
+
+func (t *countingTransport) readBytes() int64 {
+	return atomic.LoadInt64(&t.read)
+}
@@ -0,0 +1,75 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"bytes"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/facebook/fbthrift/thrift/lib/go/thrift"
+	"github.com/stretchr/testify/assert"
+	"github.com/vesoft-inc/nebula-go/v3/nebula"
+	"github.com/vesoft-inc/nebula-go/v3/nebula/graph"
+)
+
+func TestMemoryStats(t *testing.T) {
+	var m memoryStats
+	assert.Nil(t, m.admit(0))
+	assert.Nil(t, m.admit(100))
+
+	m.startDecode(80)
+	assert.Nil(t, m.admit(100))
+	m.track(&ResultSet{}, 50)
+	err := m.admit(100)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "hold 130 bytes, more than the soft limit of 100 bytes")
+	}
+	assert.Equal(t, int64(1), m.rejections)
+	m.endDecode(80)
+	assert.Equal(t, int64(50), m.inUse())
+
+	// the result set is not reachable anymore
+	for i := 0; i < 50 && m.inUse() != 0; i++ {
+		runtime.GC()
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Equal(t, int64(0), m.inUse())
+}
+
+func TestResultSetClose(t *testing.T) {
+	var m memoryStats
+	res := &ResultSet{resp: &graph.ExecutionResponse{Data: &nebula.DataSet{Rows: []*nebula.Row{{}}}}}
+	m.track(res, 50)
+	assert.Equal(t, int64(50), m.inUse())
+
+	copied := *res
+	res.Close()
+	assert.Equal(t, int64(0), m.inUse())
+	assert.Equal(t, 0, copied.GetRowSize())
+	// closing again or collecting the result set does not release its memory twice
+	copied.Close()
+	res = nil
+	runtime.GC()
+	time.Sleep(10 * time.Millisecond)
+	assert.Equal(t, int64(0), m.inUse())
+}
+
+func TestCountingTransport(t *testing.T) {
+	buf := thrift.NewMemoryBuffer()
+	buf.Buffer = bytes.NewBufferString("hello world")
+	counter := &countingTransport{Transport: buf}
+	p := make([]byte, 5)
+	_, err := counter.Read(p)
+	assert.Nil(t, err)
+	_, err = counter.Read(p)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(10), counter.readBytes())
+}
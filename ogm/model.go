@@ -0,0 +1,380 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+// Package ogm maps go structs to the vertices of a tag.
+//
+// The fields of a struct are mapped with the nebula tag:
+//
+//	type Player struct {
+//		ID   string  `nebula:",vid"`
+//		Name string  `nebula:"name"`
+//		Age  *int64  `nebula:"age"`
+//	}
+//
+// The field marked with the vid option holds the vertex ID and can be a string, an integer or a nebula.VID.
+// Other tagged fields are mapped to the property of the same name, pointer fields are nullable.
+// Fields of the types registered with nebula.RegisterValueEncoder and nebula.RegisterValueDecoder
+// are encoded and decoded by their encoder and decoder.
+// Untagged fields and fields tagged with "-" are ignored.
+package ogm
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	nebula "github.com/vesoft-inc/nebula-go/v3"
+)
+
+// TagName is the struct tag used to map the fields of a struct
+const TagName = "nebula"
+
+// Field is a struct field mapped to a property
+type Field struct {
+	// The name of the struct field
+	Name string
+	// The name of the property
+	Prop string
+	// The type of the struct field
+	Type reflect.Type
+	// True if the struct field is a pointer, in which case the property is nullable
+	Nullable bool
+
+	index int
+}
+
+// Model is the mapping of a struct type to the properties of a tag
+type Model struct {
+	Type   reflect.Type
+	Fields []Field
+
+	vidIndex int
+}
+
+var (
+	modelsMu sync.RWMutex
+	models   = make(map[reflect.Type]*Model)
+)
+
+var vidType = reflect.TypeOf(nebula.VID{})
+
+// ModelOf returns the model of the struct type of v, which can be a struct, a pointer to a struct or a reflect.Type.
+// Models are cached per type.
+func ModelOf(v interface{}) (*Model, error) {
+	t, ok := v.(reflect.Type)
+	if !ok {
+		t = reflect.TypeOf(v)
+	}
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("failed to map %v: not a struct", t)
+	}
+
+	modelsMu.RLock()
+	model, ok := models[t]
+	modelsMu.RUnlock()
+	if ok {
+		return model, nil
+	}
+
+	model, err := parseModel(t)
+	if err != nil {
+		return nil, err
+	}
+	modelsMu.Lock()
+	models[t] = model
+	modelsMu.Unlock()
+	return model, nil
+}
+
+func parseModel(t reflect.Type) (*Model, error) {
+	model := &Model{Type: t, vidIndex: -1}
+	props := make(map[string]bool)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag, ok := f.Tag.Lookup(TagName)
+		if !ok || tag == "-" {
+			continue
+		}
+		if f.PkgPath != "" {
+			return nil, fmt.Errorf("failed to map %s.%s: field is not exported", t.Name(), f.Name)
+		}
+		parts := strings.Split(tag, ",")
+		name := parts[0]
+		isVID := false
+		for _, opt := range parts[1:] {
+			switch opt {
+			case "vid":
+				isVID = true
+			default:
+				return nil, fmt.Errorf("failed to map %s.%s: unknown option %s", t.Name(), f.Name, opt)
+			}
+		}
+		if isVID {
+			if model.vidIndex >= 0 {
+				return nil, fmt.Errorf("failed to map %s: more than one vid field", t.Name())
+			}
+			if !isVIDType(f.Type) {
+				return nil, fmt.Errorf("failed to map %s.%s: vid field must be a string, an integer or a nebula.VID",
+					t.Name(), f.Name)
+			}
+			model.vidIndex = i
+			continue
+		}
+		if name == "" {
+			return nil, fmt.Errorf("failed to map %s.%s: missing property name", t.Name(), f.Name)
+		}
+		if props[name] {
+			return nil, fmt.Errorf("failed to map %s.%s: duplicated property %s", t.Name(), f.Name, name)
+		}
+		props[name] = true
+		fieldType := f.Type
+		nullable := fieldType.Kind() == reflect.Ptr
+		if nullable {
+			fieldType = fieldType.Elem()
+		}
+		if !isPropType(fieldType) {
+			return nil, fmt.Errorf("failed to map %s.%s: unsupported type %s", t.Name(), f.Name, f.Type)
+		}
+		model.Fields = append(model.Fields, Field{
+			Name:     f.Name,
+			Prop:     name,
+			Type:     f.Type,
+			Nullable: nullable,
+			index:    i,
+		})
+	}
+	if model.vidIndex < 0 {
+		return nil, fmt.Errorf("failed to map %s: missing vid field", t.Name())
+	}
+	return model, nil
+}
+
+func isVIDType(t reflect.Type) bool {
+	if t == vidType {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.String, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return true
+	}
+	return false
+}
+
+func isPropType(t reflect.Type) bool {
+	if nebula.HasValueEncoder(t) || nebula.HasValueDecoder(t) {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.Bool, reflect.String, reflect.Float32, reflect.Float64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return true
+	}
+	return false
+}
+
+// Props returns the names of the mapped properties
+func (m *Model) Props() []string {
+	props := make([]string, len(m.Fields))
+	for i, f := range m.Fields {
+		props[i] = f.Prop
+	}
+	return props
+}
+
+// Field returns the field mapped to the given property
+func (m *Model) Field(prop string) (Field, bool) {
+	for _, f := range m.Fields {
+		if f.Prop == prop {
+			return f, true
+		}
+	}
+	return Field{}, false
+}
+
+// VID returns the vertex ID of v, which must be a struct or a pointer to a struct of the model type
+func (m *Model) VID(v interface{}) (nebula.VID, error) {
+	rv, err := m.structValue(v)
+	if err != nil {
+		return nebula.VID{}, err
+	}
+	f := rv.Field(m.vidIndex)
+	if f.Type() == vidType {
+		return f.Interface().(nebula.VID), nil
+	}
+	if f.Kind() == reflect.String {
+		return nebula.StringVID(f.String()), nil
+	}
+	return nebula.IntVID(f.Int()), nil
+}
+
+// Values returns the values of the mapped properties of v, in the order of the fields.
+// Nil pointers are returned as nil.
+func (m *Model) Values(v interface{}) ([]interface{}, error) {
+	rv, err := m.structValue(v)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]interface{}, len(m.Fields))
+	for i, f := range m.Fields {
+		fv := rv.Field(f.index)
+		if f.Nullable {
+			if fv.IsNil() {
+				continue
+			}
+			fv = fv.Elem()
+		}
+		values[i] = fv.Interface()
+	}
+	return values, nil
+}
+
+// Decode sets the vid field and the mapped fields of dst, a pointer to a struct of the model type,
+// from the properties of the tag of the node.
+func (m *Model) Decode(node *nebula.Node, tag string, dst interface{}) error {
+	return m.DecodeMasked(node, tag, dst, nil)
+}
+
+// DecodeMasked is Decode applying the masker to the properties, e.g. to redact the PII properties
+// the caller may not read. The masker can be nil.
+func (m *Model) DecodeMasked(node *nebula.Node, tag string, dst interface{}, masker nebula.FieldMasker) error {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Type() != m.Type {
+		return fmt.Errorf("failed to decode: %T is not a pointer to %s", dst, m.Type)
+	}
+	rv = rv.Elem()
+
+	id := node.GetID()
+	vid, err := id.AsVID()
+	if err != nil {
+		return err
+	}
+	if err = setVID(rv.Field(m.vidIndex), vid); err != nil {
+		return err
+	}
+
+	props, err := node.Properties(tag)
+	if err != nil {
+		return err
+	}
+	for _, f := range m.Fields {
+		val, ok := props[f.Prop]
+		if !ok {
+			continue
+		}
+		if val, err = masker.Mask(nebula.MaskedField{Tag: tag, Prop: f.Prop}, val); err != nil {
+			return err
+		}
+		if err := decodeValue(rv.Field(f.index), val); err != nil {
+			return fmt.Errorf("failed to decode property %s into %s.%s: %s", f.Prop, m.Type.Name(), f.Name, err.Error())
+		}
+	}
+	return nil
+}
+
+func (m *Model) structValue(v interface{}) (reflect.Value, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return reflect.Value{}, fmt.Errorf("nil %s", m.Type)
+		}
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() || rv.Type() != m.Type {
+		return reflect.Value{}, fmt.Errorf("%T is not a %s", v, m.Type)
+	}
+	return rv, nil
+}
+
+func setVID(f reflect.Value, vid nebula.VID) error {
+	if f.Type() == vidType {
+		f.Set(reflect.ValueOf(vid))
+		return nil
+	}
+	if f.Kind() == reflect.String {
+		f.SetString(vid.Raw())
+		return nil
+	}
+	i, err := vid.Int()
+	if err != nil {
+		return err
+	}
+	if f.OverflowInt(i) {
+		return fmt.Errorf("vid %d overflows %s", i, f.Type())
+	}
+	f.SetInt(i)
+	return nil
+}
+
+func decodeValue(f reflect.Value, val *nebula.ValueWrapper) error {
+	if val.IsNull() || val.IsEmpty() {
+		f.Set(reflect.Zero(f.Type()))
+		return nil
+	}
+	if nebula.HasValueDecoder(f.Type()) {
+		return nebula.DecodeValue(val, f.Addr().Interface())
+	}
+	if f.Kind() == reflect.Ptr {
+		ptr := reflect.New(f.Type().Elem())
+		if err := decodeValue(ptr.Elem(), val); err != nil {
+			return err
+		}
+		f.Set(ptr)
+		return nil
+	}
+	switch f.Kind() {
+	case reflect.Bool:
+		b, err := val.AsBool()
+		if err != nil {
+			return err
+		}
+		f.SetBool(b)
+	case reflect.String:
+		s, err := val.AsString()
+		if err != nil {
+			return err
+		}
+		f.SetString(s)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := val.AsInt()
+		if err != nil {
+			return err
+		}
+		if f.OverflowInt(i) {
+			return fmt.Errorf("value %d overflows %s", i, f.Type())
+		}
+		f.SetInt(i)
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		i, err := val.AsInt()
+		if err != nil {
+			return err
+		}
+		if i < 0 || f.OverflowUint(uint64(i)) {
+			return fmt.Errorf("value %d overflows %s", i, f.Type())
+		}
+		f.SetUint(uint64(i))
+	case reflect.Float32, reflect.Float64:
+		if val.IsInt() {
+			i, _ := val.AsInt()
+			f.SetFloat(float64(i))
+			return nil
+		}
+		fl, err := val.AsFloat()
+		if err != nil {
+			return err
+		}
+		f.SetFloat(fl)
+	default:
+		return fmt.Errorf("unsupported type %s", f.Type())
+	}
+	return nil
+}
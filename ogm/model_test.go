@@ -0,0 +1,110 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package ogm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	nebula "github.com/vesoft-inc/nebula-go/v3"
+)
+
+type player struct {
+	ID      string  `nebula:",vid"`
+	Name    string  `nebula:"name"`
+	Age     *int64  `nebula:"age"`
+	Score   float64 `nebula:"score"`
+	Ignored string
+	Skipped string `nebula:"-"`
+}
+
+func TestModelOf(t *testing.T) {
+	model, err := ModelOf(&player{})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"name", "age", "score"}, model.Props())
+	field, ok := model.Field("age")
+	assert.True(t, ok)
+	assert.True(t, field.Nullable)
+	assert.Equal(t, "Age", field.Name)
+
+	cached, err := ModelOf(player{})
+	assert.Nil(t, err)
+	assert.True(t, model == cached)
+}
+
+func TestModelOfInvalid(t *testing.T) {
+	_, err := ModelOf(1)
+	assert.NotNil(t, err)
+
+	type noVID struct {
+		Name string `nebula:"name"`
+	}
+	_, err = ModelOf(noVID{})
+	assert.EqualError(t, err, "failed to map noVID: missing vid field")
+
+	type badType struct {
+		ID   int64          `nebula:",vid"`
+		Tags map[string]int `nebula:"tags"`
+	}
+	_, err = ModelOf(badType{})
+	assert.NotNil(t, err)
+
+	type duplicated struct {
+		ID int64  `nebula:",vid"`
+		A  string `nebula:"name"`
+		B  string `nebula:"name"`
+	}
+	_, err = ModelOf(duplicated{})
+	assert.NotNil(t, err)
+}
+
+func TestModelValues(t *testing.T) {
+	model, err := ModelOf(player{})
+	assert.Nil(t, err)
+	age := int64(33)
+	p := &player{ID: "Tim", Name: "Tim Duncan", Age: &age, Score: 9.5}
+
+	vid, err := model.VID(p)
+	assert.Nil(t, err)
+	assert.Equal(t, nebula.StringVID("Tim"), vid)
+
+	values, err := model.Values(p)
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{"Tim Duncan", int64(33), 9.5}, values)
+
+	p.Age = nil
+	values, err = model.Values(*p)
+	assert.Nil(t, err)
+	assert.Nil(t, values[1])
+
+	_, err = model.Values(1)
+	assert.NotNil(t, err)
+}
+
+type rating string
+
+type rated struct {
+	ID     int64  `nebula:",vid"`
+	Rating rating `nebula:"rating"`
+}
+
+func TestModelEncodedField(t *testing.T) {
+	nebula.RegisterValueEncoder(rating(""), func(v interface{}) (interface{}, error) {
+		return string(v.(rating)), nil
+	})
+	defer nebula.RegisterValueEncoder(rating(""), nil)
+	model, err := ModelOf(rated{})
+	assert.Nil(t, err)
+	values, err := model.Values(rated{ID: 1, Rating: "AAA"})
+	assert.Nil(t, err)
+	lit, err := nebula.FormatValue(values[0])
+	assert.Nil(t, err)
+	assert.Equal(t, `"AAA"`, lit)
+	assert.Empty(t, model.Validate("rated", []TagProp{{Name: "rating", Type: "int64"}}))
+}
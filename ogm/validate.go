@@ -0,0 +1,162 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package ogm
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	nebula "github.com/vesoft-inc/nebula-go/v3"
+)
+
+// TagModel binds a mapped struct to the tag of a space
+type TagModel struct {
+	Space string
+	Tag   string
+	// A struct or a pointer to a struct mapped with the nebula tag
+	Model interface{}
+}
+
+// ValidationError lists the differences found between struct mappings and the schema
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%d struct mapping problem(s): %s", len(e.Problems), strings.Join(e.Problems, "; "))
+}
+
+// TagProp is a property of a tag as shown by DESCRIBE TAG
+type TagProp = nebula.SchemaProp
+
+// ValidateModels compares the struct mappings of the models with the tags of the live schema.
+// It returns a *ValidationError listing every mapped property which does not exist in the tag,
+// has an incompatible type or is nullable while its field is not a pointer,
+// and every property of the tag which is required on insert but is not mapped.
+func ValidateModels(ctx context.Context, pool *nebula.ConnectionPool, models ...TagModel) error {
+	session, err := pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer session.Release()
+
+	var problems []string
+	for _, m := range models {
+		model, err := ModelOf(m.Model)
+		if err != nil {
+			problems = append(problems, err.Error())
+			continue
+		}
+		props, err := DescribeTag(ctx, session, m.Space, m.Tag)
+		if err != nil {
+			problems = append(problems, err.Error())
+			continue
+		}
+		problems = append(problems, model.Validate(m.Tag, props)...)
+	}
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+// DescribeTag returns the properties of the tag of the space, from the schema cache of the pool if it is enabled
+func DescribeTag(ctx context.Context, session *nebula.Session, space, tag string) ([]TagProp, error) {
+	return session.DescribeTag(ctx, space, tag)
+}
+
+// Validate compares the model with the properties of a tag and returns the problems found
+func (m *Model) Validate(tag string, props []TagProp) []string {
+	var problems []string
+	byName := make(map[string]TagProp, len(props))
+	for _, p := range props {
+		byName[p.Name] = p
+	}
+	for _, f := range m.Fields {
+		p, ok := byName[f.Prop]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("%s.%s: property %s does not exist in tag %s",
+				m.Type.Name(), f.Name, f.Prop, tag))
+			continue
+		}
+		fieldType := f.Type
+		if f.Nullable {
+			fieldType = fieldType.Elem()
+		}
+		// the schema type of an encoded value is only known by its encoder and decoder
+		if !nebula.HasValueEncoder(fieldType) && !nebula.HasValueDecoder(fieldType) && !compatibleType(fieldType, p.Type) {
+			problems = append(problems, fmt.Sprintf("%s.%s: type %s is not compatible with %s.%s of type %s",
+				m.Type.Name(), f.Name, f.Type, tag, p.Name, p.Type))
+		}
+		if p.Nullable && !f.Nullable {
+			problems = append(problems, fmt.Sprintf("%s.%s: %s.%s is nullable, use a pointer type instead of %s",
+				m.Type.Name(), f.Name, tag, p.Name, f.Type))
+		}
+	}
+	for _, p := range props {
+		if _, ok := m.Field(p.Name); !ok && !p.Nullable && !p.HasDefault {
+			problems = append(problems, fmt.Sprintf("%s: %s.%s is not nullable and has no default value but is not mapped",
+				m.Type.Name(), tag, p.Name))
+		}
+	}
+	return problems
+}
+
+// compatibleType returns true if values of the schema type can be stored in t without loss
+func compatibleType(t reflect.Type, schemaType string) bool {
+	schemaType = strings.ToLower(schemaType)
+	switch t.Kind() {
+	case reflect.Bool:
+		return schemaType == "bool"
+	case reflect.String:
+		return schemaType == "string" || strings.HasPrefix(schemaType, "fixed_string")
+	case reflect.Float32:
+		return schemaType == "float"
+	case reflect.Float64:
+		return schemaType == "float" || schemaType == "double"
+	case reflect.Int, reflect.Int64:
+		return isIntType(schemaType, 64)
+	case reflect.Int32:
+		return isIntType(schemaType, 32)
+	case reflect.Int16:
+		return isIntType(schemaType, 16)
+	case reflect.Int8:
+		return isIntType(schemaType, 8)
+	// the schema has no unsigned type, the values of the field must fit in a wider signed one
+	case reflect.Uint32:
+		return intTypeBits(schemaType) > 32
+	case reflect.Uint16:
+		return intTypeBits(schemaType) > 16
+	case reflect.Uint8:
+		return intTypeBits(schemaType) > 8
+	}
+	return false
+}
+
+func isIntType(schemaType string, bits int) bool {
+	size := intTypeBits(schemaType)
+	return size > 0 && size <= bits
+}
+
+// intTypeBits returns the size of the integer schema type, 0 if it is not an integer type
+func intTypeBits(schemaType string) int {
+	switch schemaType {
+	case "int8":
+		return 8
+	case "int16":
+		return 16
+	case "int32":
+		return 32
+	case "int64", "int", "timestamp":
+		return 64
+	}
+	return 0
+}
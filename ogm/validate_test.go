@@ -0,0 +1,60 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package ogm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestModelValidate(t *testing.T) {
+	model, err := ModelOf(player{})
+	assert.Nil(t, err)
+
+	props := []TagProp{
+		{Name: "name", Type: "fixed_string(32)"},
+		{Name: "age", Type: "int64", Nullable: true},
+		{Name: "score", Type: "double"},
+	}
+	assert.Empty(t, model.Validate("player", props))
+
+	props = []TagProp{
+		{Name: "name", Type: "string", Nullable: true},
+		{Name: "score", Type: "int64"},
+		{Name: "team", Type: "string"},
+		{Name: "created", Type: "timestamp", HasDefault: true},
+	}
+	assert.Equal(t, []string{
+		"player.Name: player.name is nullable, use a pointer type instead of string",
+		"player.Age: property age does not exist in tag player",
+		"player.Score: type float64 is not compatible with player.score of type int64",
+		"player: player.team is not nullable and has no default value but is not mapped",
+	}, model.Validate("player", props))
+}
+
+func TestValidationError(t *testing.T) {
+	err := &ValidationError{Problems: []string{"a", "b"}}
+	assert.EqualError(t, err, "2 struct mapping problem(s): a; b")
+}
+
+func TestModelValidateUnsigned(t *testing.T) {
+	type sensor struct {
+		ID    int64  `nebula:",vid"`
+		Level uint8  `nebula:"level"`
+		Count uint32 `nebula:"count"`
+	}
+	model, err := ModelOf(sensor{})
+	assert.Nil(t, err)
+	assert.Empty(t, model.Validate("sensor", []TagProp{{Name: "level", Type: "int16"}, {Name: "count", Type: "int64"}}))
+	assert.Equal(t, []string{
+		"sensor.Level: type uint8 is not compatible with sensor.level of type int8",
+		"sensor.Count: type uint32 is not compatible with sensor.count of type int32",
+	}, model.Validate("sensor", []TagProp{{Name: "level", Type: "int8"}, {Name: "count", Type: "int32"}}))
+}
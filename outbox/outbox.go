@@ -0,0 +1,241 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+// Package outbox provides a write queue which persists statements in a local store
+// and executes them once the cluster is reachable, retrying failed statements.
+//
+// Statements sharing a key are executed in the order they were enqueued:
+// when a statement fails, the following statements of its key wait for it to be retried.
+// Statements of different keys do not block each other.
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	nebula "github.com/vesoft-inc/nebula-go/v3"
+)
+
+// Executor executes the statements of a flush, in order, and stops at the first error.
+// It returns the number of statements which were executed and the error.
+// If the error is a *StatementError, the statement following the executed ones was rejected and
+// the flush goes on with the statements of the other keys, any other error stops the flush.
+type Executor func(ctx context.Context, stmts []string) (int, error)
+
+// StatementError is returned by an Executor when a statement was rejected by the graph service
+type StatementError struct {
+	Stmt string
+	Msg  string
+}
+
+func (e *StatementError) Error() string {
+	return fmt.Sprintf("failed to execute %s: %s", e.Stmt, e.Msg)
+}
+
+// PoolExecutor returns an executor running the statements in the space with a session acquired from the pool.
+// The sessions are acquired with the credentials of the config of the pool.
+func PoolExecutor(pool *nebula.ConnectionPool, space string) Executor {
+	return func(ctx context.Context, stmts []string) (int, error) {
+		session, err := pool.Acquire(ctx)
+		if err != nil {
+			return 0, err
+		}
+		defer session.Release()
+		if err := execute(ctx, session, "USE "+nebula.QuoteIdentifier(space)); err != nil {
+			// not a *StatementError, as no statement of the flush was rejected
+			return 0, fmt.Errorf("failed to use space %s: %s", space, err.Error())
+		}
+		for i, stmt := range stmts {
+			if err := execute(ctx, session, stmt); err != nil {
+				return i, err
+			}
+		}
+		return len(stmts), nil
+	}
+}
+
+func execute(ctx context.Context, session *nebula.Session, stmt string) error {
+	resp, err := session.ExecuteWithContext(ctx, stmt, nil)
+	if err != nil {
+		return err
+	}
+	if !resp.IsSucceed() {
+		return &StatementError{Stmt: stmt, Msg: resp.GetErrorMsg()}
+	}
+	return nil
+}
+
+// Queue is a persistent write queue
+type Queue struct {
+	store Store
+	exec  Executor
+	clock nebula.Clock
+	flush chan struct{}
+	// serializes the flushes
+	mu sync.Mutex
+}
+
+// New returns a queue persisting the statements in the store and executing them with exec
+func New(store Store, exec Executor) *Queue {
+	return NewWithClock(store, exec, nebula.RealClock())
+}
+
+// NewWithClock returns a queue like New whose flush interval and backoff are timed by the clock,
+// e.g. the clock of the pool config
+func NewWithClock(store Store, exec Executor, clock nebula.Clock) *Queue {
+	return &Queue{store: store, exec: exec, clock: clock, flush: make(chan struct{}, 1)}
+}
+
+// Enqueue persists the statement, which will be executed after the previous statements of the same key.
+// The statement must be idempotent as it is executed again if its acknowledgement is lost.
+func (q *Queue) Enqueue(key, stmt string) error {
+	if _, err := q.store.Append(key, stmt); err != nil {
+		return err
+	}
+	select {
+	case q.flush <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Pending returns the number of statements which have not been executed yet
+func (q *Queue) Pending() (int, error) {
+	entries, err := q.store.Pending()
+	return len(entries), err
+}
+
+// FlushError is returned by Flush when some statements could not be executed
+type FlushError struct {
+	// The number of statements which were executed
+	Executed int
+	// The number of statements which are still pending
+	Pending int
+	// The errors of the failed statements
+	Errors []error
+}
+
+func (e *FlushError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msgs[i] = err.Error()
+	}
+	return fmt.Sprintf("failed to flush outbox, %d statement(s) executed, %d pending: %s",
+		e.Executed, e.Pending, strings.Join(msgs, "; "))
+}
+
+// Flush executes the pending statements and acknowledges them in the store.
+// The statements are executed in rounds of at most one statement per key, so that a rejected
+// statement only delays the statements of its key. Flush stops when the executor fails for
+// another reason, and returns a *FlushError if any statement is left for the next flush.
+func (q *Queue) Flush(ctx context.Context) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	entries, err := q.store.Pending()
+	if err != nil {
+		return err
+	}
+	executed := 0
+	var errs []error
+	blocked := make(map[string]bool)
+	for len(entries) > 0 {
+		// take the first pending entry of every key which is not blocked
+		var round, rest []Entry
+		seen := make(map[string]bool)
+		for _, e := range entries {
+			if blocked[e.Key] {
+				continue
+			}
+			if seen[e.Key] {
+				rest = append(rest, e)
+				continue
+			}
+			seen[e.Key] = true
+			round = append(round, e)
+		}
+		if len(round) == 0 {
+			break
+		}
+		stmts := make([]string, len(round))
+		for i, e := range round {
+			stmts[i] = e.Stmt
+		}
+		n, err := q.exec(ctx, stmts)
+		for _, e := range round[:n] {
+			if err := q.store.Ack(e.Seq); err != nil {
+				return err
+			}
+		}
+		executed += n
+		if err != nil {
+			errs = append(errs, err)
+			var stmtErr *StatementError
+			if !errors.As(err, &stmtErr) || n >= len(round) {
+				// the cluster is unreachable or the flush is canceled
+				break
+			}
+			blocked[round[n].Key] = true
+			// the statements of the round after the rejected one are executed with the next round
+			rest = append(rest, round[n+1:]...)
+			sort.Slice(rest, func(i, j int) bool { return rest[i].Seq < rest[j].Seq })
+		}
+		entries = rest
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	pending, err := q.Pending()
+	if err != nil {
+		return err
+	}
+	return &FlushError{Executed: executed, Pending: pending, Errors: errs}
+}
+
+// Run flushes the queue when statements are enqueued and every interval until the context is done.
+// After a failed flush it waits for an exponential backoff starting at interval and capped at maxBackoff.
+// Errors are passed to onError, which can be nil.
+func (q *Queue) Run(ctx context.Context, interval, maxBackoff time.Duration, onError func(error)) {
+	backoff := interval
+	for {
+		wait := interval
+		if err := q.Flush(ctx); err != nil {
+			if onError != nil {
+				onError(err)
+			}
+			wait = backoff
+			if backoff *= 2; backoff > maxBackoff {
+				backoff = maxBackoff
+			}
+		} else {
+			backoff = interval
+		}
+		timer := q.clock.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-q.flush:
+			if wait != interval {
+				// wait for the backoff before retrying
+				select {
+				case <-ctx.Done():
+					timer.Stop()
+					return
+				case <-timer.C():
+				}
+			}
+			timer.Stop()
+		case <-timer.C():
+		}
+	}
+}
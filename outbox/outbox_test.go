@@ -0,0 +1,177 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package outbox
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	nebula "github.com/vesoft-inc/nebula-go/v3"
+)
+
+// fakeExecutor records the executed statements and rejects the statements containing "bad"
+type fakeExecutor struct {
+	executed    []string
+	unreachable bool
+}
+
+func (f *fakeExecutor) execute(ctx context.Context, stmts []string) (int, error) {
+	if f.unreachable {
+		return 0, errors.New("connection refused")
+	}
+	for i, stmt := range stmts {
+		if strings.Contains(stmt, "bad") {
+			return i, &StatementError{Stmt: stmt, Msg: "syntax error"}
+		}
+		f.executed = append(f.executed, stmt)
+	}
+	return len(stmts), nil
+}
+
+func TestQueueFlushOrdering(t *testing.T) {
+	exec := &fakeExecutor{}
+	q := New(NewMemoryStore(), exec.execute)
+	assert.Nil(t, q.Enqueue("a", "a1"))
+	assert.Nil(t, q.Enqueue("b", "b1 bad"))
+	assert.Nil(t, q.Enqueue("a", "a2"))
+	assert.Nil(t, q.Enqueue("b", "b2"))
+	assert.Nil(t, q.Enqueue("c", "c1"))
+
+	err := q.Flush(context.Background())
+	var flushErr *FlushError
+	assert.True(t, errors.As(err, &flushErr))
+	assert.Equal(t, 3, flushErr.Executed)
+	assert.Equal(t, 2, flushErr.Pending)
+	// b2 waits for b1 which was rejected
+	assert.Equal(t, []string{"a1", "a2", "c1"}, exec.executed)
+
+	exec.unreachable = true
+	err = q.Flush(context.Background())
+	assert.True(t, errors.As(err, &flushErr))
+	assert.Equal(t, 0, flushErr.Executed)
+	assert.Equal(t, 2, flushErr.Pending)
+}
+
+func TestQueueFlush(t *testing.T) {
+	exec := &fakeExecutor{}
+	q := New(NewMemoryStore(), exec.execute)
+	assert.Nil(t, q.Flush(context.Background()))
+	assert.Nil(t, q.Enqueue("a", "a1"))
+	assert.Nil(t, q.Enqueue("a", "a2"))
+	assert.Nil(t, q.Flush(context.Background()))
+	assert.Equal(t, []string{"a1", "a2"}, exec.executed)
+	pending, err := q.Pending()
+	assert.Nil(t, err)
+	assert.Equal(t, 0, pending)
+}
+
+// timerClock is a ManualClock reporting the duration of every timer it creates
+type timerClock struct {
+	*nebula.ManualClock
+	timers chan time.Duration
+}
+
+func (c *timerClock) NewTimer(d time.Duration) nebula.Timer {
+	timer := c.ManualClock.NewTimer(d)
+	c.timers <- d
+	return timer
+}
+
+func TestQueueRunBackoff(t *testing.T) {
+	exec := &fakeExecutor{unreachable: true}
+	clock := &timerClock{ManualClock: nebula.NewManualClock(time.Now()), timers: make(chan time.Duration)}
+	q := NewWithClock(NewMemoryStore(), exec.execute, clock)
+	assert.Nil(t, q.Enqueue("a", "a1"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		q.Run(ctx, time.Second, 4*time.Second, nil)
+	}()
+	var waits []time.Duration
+	for i := 0; i < 4; i++ {
+		wait := <-clock.timers
+		waits = append(waits, wait)
+		if i == 3 {
+			exec.unreachable = false
+		}
+		clock.Advance(wait)
+	}
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 4 * time.Second}, waits)
+	// the interval is restored once a flush succeeds
+	assert.Equal(t, time.Second, <-clock.timers)
+	cancel()
+	<-done
+	assert.Equal(t, []string{"a1"}, exec.executed)
+}
+
+func TestFileStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "outbox")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "outbox.log")
+
+	store, err := OpenFileStore(path)
+	assert.Nil(t, err)
+	e1, err := store.Append("a", "a1")
+	assert.Nil(t, err)
+	e2, err := store.Append("b", "b1")
+	assert.Nil(t, err)
+	assert.Nil(t, store.Ack(e1.Seq))
+	assert.Nil(t, store.Close())
+
+	// simulate a crash during an append
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0600)
+	assert.Nil(t, err)
+	_, err = f.WriteString(`{"seq":3,"ke`)
+	assert.Nil(t, err)
+	assert.Nil(t, f.Close())
+
+	store, err = OpenFileStore(path)
+	assert.Nil(t, err)
+	pending, err := store.Pending()
+	assert.Nil(t, err)
+	assert.Equal(t, []Entry{e2}, pending)
+
+	// the partially written line is discarded, so the next append can be read after a restart
+	e3, err := store.Append("c", "c1")
+	assert.Nil(t, err)
+	assert.Nil(t, store.Close())
+	store, err = OpenFileStore(path)
+	assert.Nil(t, err)
+	pending, err = store.Pending()
+	assert.Nil(t, err)
+	assert.Equal(t, []Entry{e2, e3}, pending)
+	assert.Nil(t, store.Ack(e3.Seq))
+
+	// the file is truncated once every entry is acknowledged but the sequence numbers keep increasing
+	assert.Nil(t, store.Ack(e2.Seq))
+	info, err := os.Stat(path)
+	assert.Nil(t, err)
+	assert.True(t, info.Size() < 64)
+	assert.Nil(t, store.Close())
+
+	store, err = OpenFileStore(path)
+	assert.Nil(t, err)
+	defer store.Close()
+	pending, err = store.Pending()
+	assert.Nil(t, err)
+	assert.Empty(t, pending)
+	e4, err := store.Append("a", "a2")
+	assert.Nil(t, err)
+	assert.Equal(t, e3.Seq+1, e4.Seq)
+}
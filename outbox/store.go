@@ -0,0 +1,205 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package outbox
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+)
+
+// Entry is a write statement persisted in a store
+type Entry struct {
+	// The sequence number of the entry, assigned by the store in increasing order
+	Seq uint64 `json:"seq"`
+	// The key of the entry, entries of the same key are executed in order
+	Key  string `json:"key"`
+	Stmt string `json:"stmt"`
+}
+
+// Store persists the entries of a queue until they are acknowledged.
+// Implementations must be safe for concurrent use.
+type Store interface {
+	// Append persists a new entry and returns it with its sequence number
+	Append(key, stmt string) (Entry, error)
+	// Pending returns the entries which have not been acknowledged, ordered by sequence number
+	Pending() ([]Entry, error)
+	// Ack removes the entry with the given sequence number
+	Ack(seq uint64) error
+}
+
+// MemoryStore is a Store keeping the entries in memory, entries are lost when the process exits
+type MemoryStore struct {
+	mu      sync.Mutex
+	seq     uint64
+	entries map[uint64]Entry
+}
+
+// NewMemoryStore returns an empty memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{entries: make(map[uint64]Entry)}
+}
+
+// Append implements Store
+func (s *MemoryStore) Append(key, stmt string) (Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.seq++
+	entry := Entry{Seq: s.seq, Key: key, Stmt: stmt}
+	s.entries[entry.Seq] = entry
+	return entry, nil
+}
+
+// Pending implements Store
+func (s *MemoryStore) Pending() ([]Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return sortedEntries(s.entries), nil
+}
+
+// Ack implements Store
+func (s *MemoryStore) Ack(seq uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.entries, seq)
+	return nil
+}
+
+func sortedEntries(m map[uint64]Entry) []Entry {
+	entries := make([]Entry, 0, len(m))
+	for _, e := range m {
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Seq < entries[j].Seq })
+	return entries
+}
+
+// FileStore is a Store keeping the entries in an append only log file.
+// Appends and acknowledgements are synced to disk before returning,
+// and the file is truncated once every entry has been acknowledged.
+type FileStore struct {
+	mu      sync.Mutex
+	file    *os.File
+	seq     uint64
+	entries map[uint64]Entry
+}
+
+// record is a line of the log file of a FileStore
+type record struct {
+	Entry
+	Ack bool `json:"ack,omitempty"`
+}
+
+// OpenFileStore opens or creates the log file at path and loads its pending entries
+func OpenFileStore(path string) (*FileStore, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open outbox file %s: %s", path, err.Error())
+	}
+	s := &FileStore{file: file, entries: make(map[uint64]Entry)}
+	reader := bufio.NewReader(file)
+	// the length of the complete lines, the appends must not be glued onto a partially written last line
+	var complete int64
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			file.Close()
+			return nil, fmt.Errorf("failed to read outbox file %s: %s", path, err.Error())
+		}
+		complete += int64(len(line))
+		var rec record
+		if err := json.Unmarshal(line, &rec); err != nil {
+			continue
+		}
+		if rec.Seq > s.seq {
+			s.seq = rec.Seq
+		}
+		if rec.Ack {
+			delete(s.entries, rec.Seq)
+		} else {
+			s.entries[rec.Seq] = rec.Entry
+		}
+	}
+	// a partially written last line is the trace of a crash during an append which was not acknowledged
+	if err := file.Truncate(complete); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to truncate outbox file %s: %s", path, err.Error())
+	}
+	return s, nil
+}
+
+// Append implements Store
+func (s *FileStore) Append(key, stmt string) (Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry := Entry{Seq: s.seq + 1, Key: key, Stmt: stmt}
+	if err := s.write(record{Entry: entry}); err != nil {
+		return Entry{}, err
+	}
+	s.seq = entry.Seq
+	s.entries[entry.Seq] = entry
+	return entry, nil
+}
+
+// Pending implements Store
+func (s *FileStore) Pending() ([]Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return sortedEntries(s.entries), nil
+}
+
+// Ack implements Store
+func (s *FileStore) Ack(seq uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.entries[seq]; !ok {
+		return nil
+	}
+	if len(s.entries) == 1 {
+		// the last pending entry is acknowledged, the log can be discarded
+		if err := s.file.Truncate(0); err != nil {
+			return fmt.Errorf("failed to truncate outbox file: %s", err.Error())
+		}
+		if err := s.write(record{Entry: Entry{Seq: s.seq}, Ack: true}); err != nil {
+			return err
+		}
+	} else if err := s.write(record{Entry: Entry{Seq: seq}, Ack: true}); err != nil {
+		return err
+	}
+	delete(s.entries, seq)
+	return nil
+}
+
+func (s *FileStore) write(rec record) error {
+	b, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	if _, err := s.file.Write(append(b, '\n')); err != nil {
+		return fmt.Errorf("failed to write outbox file: %s", err.Error())
+	}
+	if err := s.file.Sync(); err != nil {
+		return fmt.Errorf("failed to sync outbox file: %s", err.Error())
+	}
+	return nil
+}
+
+// Close closes the log file
+func (s *FileStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.file.Close()
+}
@@ -0,0 +1,72 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// PanicError is the error returned instead of a panic raised while executing a statement
+// or decoding its response, e.g. because the graph service returned a malformed payload
+type PanicError struct {
+	// The operation which panicked, e.g. "execute"
+	Op string
+	// The value passed to panic
+	Value interface{}
+	// The stack of the goroutine which panicked
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("failed to %s: recovered from panic: %v", e.Op, e.Value)
+}
+
+// PanicHook is called with every panic recovered by the sessions of a pool, e.g. to report it
+type PanicHook func(*PanicError)
+
+// WithPanicHook sets the hook called with every panic recovered by the sessions of the pool
+func WithPanicHook(hook PanicHook) PoolConfOption {
+	return func(conf *PoolConfig) {
+		conf.PanicHook = hook
+	}
+}
+
+// recoverPanic converts a panic into a *PanicError assigned to err, it must be deferred
+func recoverPanic(op string, hook PanicHook, err *error) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	pe := &PanicError{Op: op, Value: r, Stack: debug.Stack()}
+	if hook != nil {
+		hook(pe)
+	}
+	*err = pe
+}
+
+// safeCall calls f, converting a panic into a *PanicError reported to the panic hook of the pool
+func (session *Session) safeCall(op string, f func() (interface{}, error)) (resp interface{}, err error) {
+	var hook PanicHook
+	if session.connPool != nil {
+		hook = session.connPool.conf.PanicHook
+	}
+	defer recoverPanic(op, hook, &err)
+	return f()
+}
+
+// safeExecute calls f like safeCall. After a panic the response may have been partially read,
+// so the connection of the session is closed instead of being reused once it is released.
+func (session *Session) safeExecute(f func() (interface{}, error)) (interface{}, error) {
+	resp, err := session.safeCall("execute", f)
+	if _, ok := err.(*PanicError); ok && session.connection != nil {
+		session.connection.broken = true
+	}
+	return resp, err
+}
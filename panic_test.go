@@ -0,0 +1,66 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSafeCall(t *testing.T) {
+	var recovered []*PanicError
+	conf := NewPoolConf(WithPanicHook(func(e *PanicError) {
+		recovered = append(recovered, e)
+	}))
+	session := &Session{connPool: &ConnectionPool{conf: conf}}
+
+	_, err := session.executeWithReconnect(func() (interface{}, error) {
+		var m map[string]int
+		m["a"] = 1
+		return nil, nil
+	})
+	if assert.IsType(t, &PanicError{}, err) {
+		assert.Equal(t, "execute", err.(*PanicError).Op)
+		assert.Contains(t, err.Error(), "failed to execute: recovered from panic: assignment to entry in nil map")
+		assert.NotEmpty(t, err.(*PanicError).Stack)
+	}
+	assert.Len(t, recovered, 1)
+
+	resp, err := session.safeCall("decode", func() (interface{}, error) {
+		return 1, nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, resp)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	_, err = runWithContext(ctx, func() (interface{}, error) {
+		panic("boom")
+	})
+	assert.IsType(t, &PanicError{}, err)
+}
+
+func TestSafeExecuteClosesConnection(t *testing.T) {
+	host := HostAddress{Host: "10.0.0.1", Port: 9669}
+	pool := &ConnectionPool{addresses: []HostAddress{host}, conf: NewPoolConf(), log: DefaultLogger{}}
+	conn := newDrainTestConn(host)
+	pool.activeConnectionQueue.PushBack(conn)
+	session := &Session{connection: conn, connPool: pool, log: pool.log}
+
+	_, err := session.executeWithReconnect(func() (interface{}, error) {
+		panic("malformed response")
+	})
+	assert.IsType(t, &PanicError{}, err)
+	assert.True(t, conn.broken)
+	session.Release()
+	assert.Equal(t, 0, pool.idleConnectionQueue.Len())
+	assert.Equal(t, 0, pool.activeConnectionQueue.Len())
+}
@@ -0,0 +1,93 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// DecodeFunc decodes the record of the row at the index into an application value
+type DecodeFunc func(index int, record *Record) (interface{}, error)
+
+// DecodeRows decodes every row of the result set with decode, in parallel on the given number of workers,
+// and returns the decoded values in the order of the rows. Each worker decodes a contiguous range of rows.
+// A number of workers lower than 1 means GOMAXPROCS workers, 1 decodes the rows on the calling goroutine.
+// If decode fails or panics, the rows after the failed one are skipped and the error of the lowest failed row
+// is returned, a panic being returned as a *PanicError.
+func (res ResultSet) DecodeRows(workers int, decode DecodeFunc) ([]interface{}, error) {
+	n := res.GetRowSize()
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > n {
+		workers = n
+	}
+	values := make([]interface{}, n)
+	if n == 0 {
+		return values, nil
+	}
+
+	errs := make([]error, workers)
+	// the lowest failed row, the rows after it are not decoded
+	failedAt := int64(n)
+	fail := func(i int) {
+		for {
+			current := atomic.LoadInt64(&failedAt)
+			if int64(i) >= current || atomic.CompareAndSwapInt64(&failedAt, current, int64(i)) {
+				return
+			}
+		}
+	}
+	var wg sync.WaitGroup
+	chunk := (n + workers - 1) / workers
+	for w := 0; w < workers; w++ {
+		start, end := w*chunk, (w+1)*chunk
+		if end > n {
+			end = n
+		}
+		decodeRange := func(w, start, end int) {
+			i := start
+			defer func() {
+				if errs[w] != nil {
+					fail(i)
+				}
+			}()
+			defer recoverPanic("decode", nil, &errs[w])
+			for ; i < end && int64(i) < atomic.LoadInt64(&failedAt); i++ {
+				record, err := res.GetRowValuesByIndex(i)
+				if err == nil {
+					values[i], err = decode(i, record)
+				}
+				if err != nil {
+					errs[w] = err
+					return
+				}
+			}
+		}
+		if workers == 1 {
+			decodeRange(w, start, end)
+			break
+		}
+		wg.Add(1)
+		go func(w, start, end int) {
+			defer wg.Done()
+			decodeRange(w, start, end)
+		}(w, start, end)
+	}
+	wg.Wait()
+	// the workers decode increasing ranges of rows, the error of the first failed worker is the lowest one
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
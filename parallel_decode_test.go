@@ -0,0 +1,61 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeRows(t *testing.T) {
+	var rows [][]interface{}
+	for i := 0; i < 100; i++ {
+		rows = append(rows, []interface{}{i})
+	}
+	rs := newTestResultSet(t, []string{"n"}, rows...)
+	double := func(index int, record *Record) (interface{}, error) {
+		v, err := record.GetValueByIndex(0)
+		if err != nil {
+			return nil, err
+		}
+		n, err := v.AsInt()
+		return n * 2, err
+	}
+
+	for _, workers := range []int{0, 1, 3, 7, 200} {
+		values, err := rs.DecodeRows(workers, double)
+		assert.Nil(t, err)
+		assert.Len(t, values, 100)
+		for i, v := range values {
+			assert.Equal(t, int64(2*i), v)
+		}
+	}
+
+	_, err := rs.DecodeRows(4, func(index int, record *Record) (interface{}, error) {
+		if index == 30 || index == 80 {
+			return nil, fmt.Errorf("bad row %d", index)
+		}
+		return nil, nil
+	})
+	assert.EqualError(t, err, "bad row 30")
+
+	_, err = rs.DecodeRows(4, func(index int, record *Record) (interface{}, error) {
+		if index == 60 {
+			panic("boom")
+		}
+		return nil, nil
+	})
+	assert.IsType(t, &PanicError{}, err)
+
+	values, err := newTestResultSet(t, []string{"n"}).DecodeRows(4, double)
+	assert.Nil(t, err)
+	assert.Empty(t, values)
+}
@@ -0,0 +1,235 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultCallerLabel is the label of acquires made without a caller label in their context
+const DefaultCallerLabel = "default"
+
+// the number of most recent wait times kept per caller label to compute percentiles
+const waitSampleSize = 1024
+
+type callerLabelKey struct{}
+
+// WithCallerLabel returns a context carrying the caller label used to group the acquire statistics of the pool
+func WithCallerLabel(ctx context.Context, label string) context.Context {
+	return context.WithValue(ctx, callerLabelKey{}, label)
+}
+
+// callerLabel returns the caller label of the context
+func callerLabel(ctx context.Context) string {
+	if ctx != nil {
+		if label, ok := ctx.Value(callerLabelKey{}).(string); ok && label != "" {
+			return label
+		}
+	}
+	return DefaultCallerLabel
+}
+
+// PoolStats is a snapshot of the statistics of a connection pool
+type PoolStats struct {
+	// The number of connections in use
+	ActiveConns int
+	// The number of idle connections
+	IdleConns int
+	// The number of callers waiting for a connection
+	Waiting int
+	// The max number of connections, which varies between MinConnPoolSize and MaxConnPoolSize if autoscaling is enabled
+	Capacity int
+	// The acquire statistics grouped by caller label
+	Callers map[string]CallerStats
+	// The name of the pool in the registry of OpenNamed, empty if the pool is not registered
+	Name string
+	// The client identity of the pool config
+	ClientName    string
+	ClientVersion string
+	// The estimated bytes of the responses being decoded and of the result sets not garbage collected yet
+	InFlightBytes int64
+	BufferedBytes int64
+	// The number of executions rejected because of the MemorySoftLimit of the pool config
+	MemoryRejections int64
+	// The number of statements whose session had moved to another space than the space of the pool config,
+	// see WithSpaceValidation
+	SpaceCorrections int64
+	// The number of connections used by each workload partition, see WithWorkloadPartition
+	Workloads map[string]int
+	// The sessions in use ordered by ID, to correlate them with SHOW SESSIONS, see also WithSessionHook
+	Sessions []PooledSession
+}
+
+// CallerStats is the acquire statistics of a caller label
+type CallerStats struct {
+	// The number of successful acquires
+	Acquires int64
+	// The number of callers currently waiting for a connection
+	Waiting int
+	// The percentiles of the wait time of the most recent acquires
+	WaitP50 time.Duration
+	WaitP90 time.Duration
+	WaitP99 time.Duration
+	// The longest wait time observed
+	MaxWait time.Duration
+	// The number of acquires which gave up waiting or waited longer than the StarvationThreshold
+	Starvations int64
+}
+
+type callerStats struct {
+	acquires    int64
+	waiting     int
+	samples     []time.Duration
+	next        int
+	maxWait     time.Duration
+	starvations int64
+}
+
+// acquireStats collects the acquire statistics of a pool
+type acquireStats struct {
+	mu      sync.Mutex
+	callers map[string]*callerStats
+	// the longest wait and the number of failed acquires since the last autoscaling evaluation
+	windowMaxWait  time.Duration
+	windowFailures int
+	// the outcomes of the most recent acquires and executions, for the error rate of Pressure
+	outcomes outcomeWindow
+}
+
+func (s *acquireStats) get(label string) *callerStats {
+	if s.callers == nil {
+		s.callers = make(map[string]*callerStats)
+	}
+	stats, ok := s.callers[label]
+	if !ok {
+		stats = &callerStats{}
+		s.callers[label] = stats
+	}
+	return stats
+}
+
+// startWait records a caller starting to wait for a connection
+func (s *acquireStats) startWait(label string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.get(label).waiting++
+}
+
+// endWait records the end of a wait at now, starved is true if the caller gave up or waited too long
+func (s *acquireStats) endWait(label string, now time.Time, wait time.Duration, acquired, starved bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stats := s.get(label)
+	stats.waiting--
+	if starved {
+		stats.starvations++
+	}
+	if wait > stats.maxWait {
+		stats.maxWait = wait
+	}
+	if wait > s.windowMaxWait {
+		s.windowMaxWait = wait
+	}
+	s.outcomes.record(now, !acquired)
+	if !acquired {
+		s.windowFailures++
+		return
+	}
+	stats.acquires++
+	if len(stats.samples) < waitSampleSize {
+		stats.samples = append(stats.samples, wait)
+	} else {
+		stats.samples[stats.next] = wait
+		stats.next = (stats.next + 1) % waitSampleSize
+	}
+}
+
+// resetWindow returns the longest wait and the number of failed acquires since the last call
+func (s *acquireStats) resetWindow() (time.Duration, int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	maxWait, failures := s.windowMaxWait, s.windowFailures
+	s.windowMaxWait, s.windowFailures = 0, 0
+	return maxWait, failures
+}
+
+func (s *acquireStats) snapshot() (int, map[string]CallerStats) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	waiting := 0
+	callers := make(map[string]CallerStats, len(s.callers))
+	for label, stats := range s.callers {
+		samples := make([]time.Duration, len(stats.samples))
+		copy(samples, stats.samples)
+		sort.Slice(samples, func(i, j int) bool { return samples[i] < samples[j] })
+		callers[label] = CallerStats{
+			Acquires:    stats.acquires,
+			Waiting:     stats.waiting,
+			WaitP50:     percentile(samples, 50),
+			WaitP90:     percentile(samples, 90),
+			WaitP99:     percentile(samples, 99),
+			MaxWait:     stats.maxWait,
+			Starvations: stats.starvations,
+		}
+		waiting += stats.waiting
+	}
+	return waiting, callers
+}
+
+// percentile returns the p-th percentile of the sorted samples
+func percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	i := (len(sorted)*p+99)/100 - 1
+	if i < 0 {
+		i = 0
+	}
+	return sorted[i]
+}
+
+// Stats returns a snapshot of the statistics of the pool
+func (pool *ConnectionPool) Stats() PoolStats {
+	pool.rwLock.RLock()
+	active := pool.activeConnectionQueue.Len()
+	idle := pool.idleConnectionQueue.Len()
+	capacity := pool.capacityLocked()
+	var workloads map[string]int
+	if len(pool.conf.WorkloadPartitions) > 0 {
+		workloads = make(map[string]int, len(pool.conf.WorkloadPartitions))
+		for workload := range pool.conf.WorkloadPartitions {
+			workloads[workload] = pool.workloadConns[workload]
+		}
+	}
+	sessions := pool.pooledSessionsLocked()
+	pool.rwLock.RUnlock()
+
+	waiting, callers := pool.acquireStats.snapshot()
+	return PoolStats{
+		ActiveConns:   active,
+		IdleConns:     idle,
+		Waiting:       waiting,
+		Capacity:      capacity,
+		Callers:       callers,
+		Name:          pool.name,
+		ClientName:    pool.conf.ClientName,
+		ClientVersion: pool.conf.ClientVersion,
+
+		InFlightBytes:    atomic.LoadInt64(&pool.memory.inFlight),
+		BufferedBytes:    atomic.LoadInt64(&pool.memory.buffered),
+		MemoryRejections: atomic.LoadInt64(&pool.memory.rejections),
+		SpaceCorrections: atomic.LoadInt64(&pool.spaceCorrections),
+		Workloads:        workloads,
+		Sessions:         sessions,
+	}
+}
@@ -0,0 +1,59 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCallerLabel(t *testing.T) {
+	assert.Equal(t, DefaultCallerLabel, callerLabel(context.Background()))
+	assert.Equal(t, "batch", callerLabel(WithCallerLabel(context.Background(), "batch")))
+}
+
+func TestAcquireStats(t *testing.T) {
+	var stats acquireStats
+	for i := 1; i <= 100; i++ {
+		stats.startWait("api")
+		stats.endWait("api", time.Time{}, time.Duration(i)*time.Millisecond, true, false)
+	}
+	stats.startWait("api")
+	stats.endWait("api", time.Time{}, 2*time.Second, false, true)
+	stats.startWait("batch")
+
+	waiting, callers := stats.snapshot()
+	assert.Equal(t, 1, waiting)
+	api := callers["api"]
+	assert.Equal(t, int64(100), api.Acquires)
+	assert.Equal(t, 50*time.Millisecond, api.WaitP50)
+	assert.Equal(t, 90*time.Millisecond, api.WaitP90)
+	assert.Equal(t, 99*time.Millisecond, api.WaitP99)
+	assert.Equal(t, 2*time.Second, api.MaxWait)
+	assert.Equal(t, int64(1), api.Starvations)
+	assert.Equal(t, 1, callers["batch"].Waiting)
+}
+
+func TestGetSessionCallerLabel(t *testing.T) {
+	host := HostAddress{Host: "10.0.0.1", Port: 9669}
+	conf := NewPoolConf()
+	conf.MaxConnPoolSize = 1
+	conf.Clock = realClock{}
+	pool := &ConnectionPool{addresses: []HostAddress{host}, conf: conf, log: DefaultLogger{}}
+	pool.activeConnectionQueue.PushBack(newDrainTestConn(host))
+
+	_, err := pool.GetSessionWithContext(WithCallerLabel(context.Background(), "batch"), "root", "nebula")
+	assert.NotNil(t, err)
+	callers := pool.Stats().Callers
+	assert.Contains(t, callers, "batch")
+	assert.NotContains(t, callers, DefaultCallerLabel)
+}
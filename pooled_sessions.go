@@ -0,0 +1,92 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import "sort"
+
+// PooledSession is a session of the pool in use, its ID is the ID of the ServerSession
+// returned by ShowSessions
+type PooledSession struct {
+	ID   int64
+	Host HostAddress
+	// The workload partition of its connection, see WithWorkloadPartition
+	Workload string
+}
+
+// SessionEventKind is the kind of a SessionEvent
+type SessionEventKind int
+
+const (
+	// The session has been authenticated by the graph service
+	SessionCreated SessionEventKind = iota
+	// The session has been authenticated again after its connection was reset, see WithSessionRepin
+	SessionRecreated
+	// The session has been signed out and its connection returned to the pool
+	SessionReleased
+)
+
+func (k SessionEventKind) String() string {
+	switch k {
+	case SessionCreated:
+		return "created"
+	case SessionRecreated:
+		return "recreated"
+	case SessionReleased:
+		return "released"
+	default:
+		return "unknown"
+	}
+}
+
+// SessionEvent is a change of the server sessions of a pool
+type SessionEvent struct {
+	Kind      SessionEventKind
+	SessionID int64
+	// The ID of the session replaced by a recreated session
+	PreviousSessionID int64
+	Host              HostAddress
+}
+
+// SessionHook is called with every SessionEvent of a pool, it must not block
+type SessionHook func(SessionEvent)
+
+// WithSessionHook sets the hook called when a session of the pool is created, recreated or released,
+// e.g. to log the session IDs and correlate them with SHOW SESSIONS while tracking a session leak
+func WithSessionHook(hook SessionHook) PoolConfOption {
+	return func(conf *PoolConfig) {
+		conf.SessionHook = hook
+	}
+}
+
+// bindSessionLocked records the server session using the connection. The caller must hold the lock.
+func (pool *ConnectionPool) bindSessionLocked(conn *connection, sessionID int64) {
+	conn.sessionID = sessionID
+}
+
+// pooledSessionsLocked returns the sessions of the active connections ordered by ID.
+// The caller must hold the lock.
+func (pool *ConnectionPool) pooledSessionsLocked() []PooledSession {
+	var sessions []PooledSession
+	for ele := pool.activeConnectionQueue.Front(); ele != nil; ele = ele.Next() {
+		conn := ele.Value.(*connection)
+		if conn.sessionID == 0 {
+			continue
+		}
+		sessions = append(sessions, PooledSession{ID: conn.sessionID, Host: conn.severAddress, Workload: conn.workload})
+	}
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].ID < sessions[j].ID })
+	return sessions
+}
+
+// sessionEvent calls the session hook of the pool config, if any
+func (pool *ConnectionPool) sessionEvent(event SessionEvent) {
+	if pool.conf.SessionHook != nil {
+		pool.conf.SessionHook(event)
+	}
+}
@@ -0,0 +1,45 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPooledSessions(t *testing.T) {
+	host1, host2 := HostAddress{Host: "10.0.0.1", Port: 9669}, HostAddress{Host: "10.0.0.2", Port: 9669}
+	var events []SessionEvent
+	pool := &ConnectionPool{
+		addresses: []HostAddress{host1, host2},
+		conf: NewPoolConf(WithSessionHook(func(e SessionEvent) {
+			events = append(events, e)
+		})),
+		log: DefaultLogger{},
+	}
+	conn1, conn2, idle := newDrainTestConn(host1), newDrainTestConn(host2), newDrainTestConn(host2)
+	pool.activeConnectionQueue.PushBack(conn1)
+	pool.activeConnectionQueue.PushBack(conn2)
+	pool.idleConnectionQueue.PushBack(idle)
+	pool.bindSessionLocked(conn1, 42)
+	pool.bindSessionLocked(conn2, 7)
+	conn2.workload = "batch"
+
+	assert.Equal(t, []PooledSession{
+		{ID: 7, Host: host2, Workload: "batch"},
+		{ID: 42, Host: host1},
+	}, pool.Stats().Sessions)
+
+	session := &Session{sessionID: 42, connection: conn1, connPool: pool, log: pool.log}
+	session.Release()
+	assert.Equal(t, []PooledSession{{ID: 7, Host: host2, Workload: "batch"}}, pool.Stats().Sessions)
+	assert.Equal(t, []SessionEvent{{Kind: SessionReleased, SessionID: 42, Host: host1}}, events)
+	assert.Equal(t, int64(0), conn1.sessionID)
+}
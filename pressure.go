@@ -0,0 +1,132 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import "time"
+
+// the number of most recent acquires and executions whose outcome is kept to compute the error rate of Pressure
+const outcomeSampleSize = 128
+
+// the age after which an outcome no longer counts in the error rate of Pressure,
+// so that the rate falls back to 0 once the failures stop, even without traffic
+const outcomeMaxAge = time.Minute
+
+type outcome struct {
+	at     time.Time
+	failed bool
+}
+
+// outcomeWindow keeps the outcomes of the most recent acquires and executions
+type outcomeWindow struct {
+	outcomes [outcomeSampleSize]outcome
+	size     int
+	next     int
+}
+
+func (w *outcomeWindow) record(now time.Time, failed bool) {
+	if w.size < outcomeSampleSize {
+		w.size++
+	}
+	w.outcomes[w.next] = outcome{at: now, failed: failed}
+	w.next = (w.next + 1) % outcomeSampleSize
+}
+
+// rate returns the ratio of the failures among the outcomes younger than outcomeMaxAge
+func (w *outcomeWindow) rate(now time.Time) float64 {
+	recent, failures := 0, 0
+	for i := 0; i < w.size; i++ {
+		o := w.outcomes[i]
+		if now.Sub(o.at) >= outcomeMaxAge {
+			continue
+		}
+		recent++
+		if o.failed {
+			failures++
+		}
+	}
+	if recent == 0 {
+		return 0
+	}
+	return float64(failures) / float64(recent)
+}
+
+// recordOutcome records the outcome of an execution
+func (s *acquireStats) recordOutcome(now time.Time, failed bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.outcomes.record(now, failed)
+}
+
+// PressureSignals are the signals of the pressure of a pool, between 0 and 1
+type PressureSignals struct {
+	// The ratio of the connections in use to the capacity of the pool
+	Saturation float64
+	// The ratio of the callers waiting for a connection to the capacity of the pool, capped to 1
+	Queue float64
+	// The ratio of the failed acquires and executions among the most recent ones of the last minute.
+	// Executions failing because their context is done or with an error of the graph service,
+	// e.g. a syntax error, are not failures.
+	Errors float64
+}
+
+// Score returns the highest signal, so that any exhausted resource reports the pool under pressure
+func (p PressureSignals) Score() float64 {
+	score := p.Saturation
+	if p.Queue > score {
+		score = p.Queue
+	}
+	if p.Errors > score {
+		score = p.Errors
+	}
+	return score
+}
+
+// PressureSignals returns the signals of the pressure of the pool
+func (pool *ConnectionPool) PressureSignals() PressureSignals {
+	pool.rwLock.RLock()
+	active := pool.activeConnectionQueue.Len()
+	capacity := pool.capacityLocked()
+	pool.rwLock.RUnlock()
+
+	s := &pool.acquireStats
+	s.mu.Lock()
+	waiting := 0
+	for _, stats := range s.callers {
+		waiting += stats.waiting
+	}
+	errors := s.outcomes.rate(pool.Clock().Now())
+	s.mu.Unlock()
+
+	var signals PressureSignals
+	if capacity > 0 {
+		signals.Saturation = minFloat(float64(active)/float64(capacity), 1)
+		signals.Queue = minFloat(float64(waiting)/float64(capacity), 1)
+	}
+	signals.Errors = errors
+	return signals
+}
+
+// Pressure returns a score between 0 for an idle pool and 1 for an exhausted or failing pool,
+// derived from the saturation of the pool, the callers waiting for a connection and the error rate.
+// HTTP servers can use it to shed load before the requests pile up behind the pool, e.g.
+//
+//	if pool.Pressure() > 0.9 {
+//		http.Error(w, "overloaded", http.StatusServiceUnavailable)
+//		return
+//	}
+func (pool *ConnectionPool) Pressure() float64 {
+	return pool.PressureSignals().Score()
+}
+
+func minFloat(a, b float64) float64 {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,62 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPressure(t *testing.T) {
+	clock := NewManualClock(time.Unix(0, 0))
+	conf := NewPoolConf(WithClock(clock))
+	conf.MaxConnPoolSize = 4
+	pool := &ConnectionPool{conf: conf}
+	assert.Equal(t, 0.0, pool.Pressure())
+
+	pool.activeConnectionQueue.PushBack(&connection{})
+	assert.Equal(t, PressureSignals{Saturation: 0.25}, pool.PressureSignals())
+
+	for i := 0; i < 6; i++ {
+		pool.acquireStats.startWait("batch")
+	}
+	assert.Equal(t, 1.0, pool.PressureSignals().Queue)
+	assert.Equal(t, 1.0, pool.Pressure())
+	for i := 0; i < 6; i++ {
+		pool.acquireStats.endWait("batch", clock.Now(), 0, i%2 == 0, false)
+	}
+	assert.Equal(t, 0.5, pool.PressureSignals().Errors)
+	assert.Equal(t, 0.5, pool.Pressure())
+
+	// the failures leave the window as new outcomes are recorded
+	for i := 0; i < outcomeSampleSize; i++ {
+		pool.acquireStats.recordOutcome(clock.Now(), false)
+	}
+	assert.Equal(t, 0.25, pool.Pressure())
+}
+
+func TestPressureErrorsRecover(t *testing.T) {
+	clock := NewManualClock(time.Unix(0, 0))
+	pool := &ConnectionPool{conf: NewPoolConf(WithClock(clock))}
+	for i := 0; i < outcomeSampleSize; i++ {
+		pool.acquireStats.recordOutcome(clock.Now(), true)
+	}
+	assert.Equal(t, 1.0, pool.PressureSignals().Errors)
+
+	// the failures age out without any new traffic
+	clock.Advance(outcomeMaxAge / 2)
+	pool.acquireStats.recordOutcome(clock.Now(), false)
+	assert.Equal(t, float64(outcomeSampleSize-1)/outcomeSampleSize, pool.PressureSignals().Errors)
+	clock.Advance(outcomeMaxAge / 2)
+	assert.Equal(t, 0.0, pool.PressureSignals().Errors)
+	clock.Advance(outcomeMaxAge / 2)
+	assert.Equal(t, 0.0, pool.Pressure())
+}
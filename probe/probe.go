@@ -0,0 +1,134 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+// Package probe provides liveness and readiness probes of a connection pool,
+// as plain functions and as http handlers for Kubernetes.
+package probe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	nebula "github.com/vesoft-inc/nebula-go/v3"
+)
+
+const (
+	// DefaultTimeout is the deadline of a readiness check
+	DefaultTimeout = 3 * time.Second
+	// DefaultCacheTTL is the duration the result of a readiness check is reused
+	DefaultCacheTTL = 5 * time.Second
+)
+
+// Prober checks the liveness and the readiness of a pool
+type Prober struct {
+	pool *nebula.ConnectionPool
+	// The deadline of a readiness check
+	timeout time.Duration
+	// The duration the result of a readiness check is reused, so that frequent probes do not load the cluster
+	cacheTTL time.Duration
+	now      func() time.Time
+	check    func(ctx context.Context) error
+
+	mu        sync.Mutex
+	checkedAt time.Time
+	lastErr   error
+}
+
+// New returns a prober of the pool, 0 values mean DefaultTimeout and DefaultCacheTTL are used
+func New(pool *nebula.ConnectionPool, timeout, cacheTTL time.Duration) *Prober {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	if cacheTTL <= 0 {
+		cacheTTL = DefaultCacheTTL
+	}
+	p := &Prober{pool: pool, timeout: timeout, cacheTTL: cacheTTL, now: time.Now}
+	if pool != nil {
+		// the cache of the checks follows the clock of the pool config
+		p.now = pool.Clock().Now
+	}
+	p.check = p.checkPool
+	return p
+}
+
+// Live returns an error if the pool has not been constructed or has been closed
+func (p *Prober) Live() error {
+	if p.pool == nil {
+		return errors.New("connection pool is not constructed")
+	}
+	if p.pool.IsClosed() {
+		return errors.New("connection pool is closed")
+	}
+	return nil
+}
+
+// Ready returns an error if a session can not be acquired from the pool and run YIELD 1 within the timeout.
+// The sessions are acquired with the credentials of the config of the pool.
+// The result is cached for the cache TTL of the prober, concurrent calls wait for the same check.
+// A check interrupted because the context of the caller is done is not cached.
+func (p *Prober) Ready(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.checkedAt.IsZero() && p.now().Sub(p.checkedAt) < p.cacheTTL {
+		return p.lastErr
+	}
+	checkCtx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+	err := p.check(checkCtx)
+	if err != nil && ctx.Err() != nil {
+		// the caller gave up, e.g. the probe request was canceled, which says nothing of the pool
+		return err
+	}
+	p.lastErr, p.checkedAt = err, p.now()
+	return err
+}
+
+func (p *Prober) checkPool(ctx context.Context) error {
+	if err := p.Live(); err != nil {
+		return err
+	}
+	session, err := p.pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer session.Release()
+	resp, err := session.ExecuteWithContext(ctx, "YIELD 1", nil)
+	if err != nil {
+		return err
+	}
+	if !resp.IsSucceed() {
+		return fmt.Errorf("failed to execute YIELD 1: %s", resp.GetErrorMsg())
+	}
+	return nil
+}
+
+// LivenessHandler returns a handler responding 200 if the pool is live and 503 otherwise
+func (p *Prober) LivenessHandler() http.Handler {
+	return handler(func(*http.Request) error { return p.Live() })
+}
+
+// ReadinessHandler returns a handler responding 200 if the pool is ready and 503 otherwise
+func (p *Prober) ReadinessHandler() http.Handler {
+	return handler(func(r *http.Request) error { return p.Ready(r.Context()) })
+}
+
+func handler(check func(*http.Request) error) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		if err := check(r); err != nil {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprintln(w, err.Error())
+			return
+		}
+		fmt.Fprintln(w, "ok")
+	})
+}
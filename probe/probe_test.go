@@ -0,0 +1,90 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package probe
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLiveness(t *testing.T) {
+	p := New(nil, 0, 0)
+	assert.Equal(t, DefaultTimeout, p.timeout)
+	assert.Equal(t, DefaultCacheTTL, p.cacheTTL)
+	assert.EqualError(t, p.Live(), "connection pool is not constructed")
+	assert.EqualError(t, p.Ready(context.Background()), "connection pool is not constructed")
+
+	rec := httptest.NewRecorder()
+	p.LivenessHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/livez", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, "connection pool is not constructed\n", rec.Body.String())
+}
+
+func TestHandler(t *testing.T) {
+	var err error
+	h := handler(func(*http.Request) error { return err })
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "ok\n", rec.Body.String())
+
+	err = errors.New("failed to acquire")
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+}
+
+func TestReadyCache(t *testing.T) {
+	now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	p := New(nil, time.Second, 5*time.Second)
+	p.now = func() time.Time { return now }
+	checks := 0
+	var err error
+	p.check = func(ctx context.Context) error {
+		checks++
+		_, ok := ctx.Deadline()
+		assert.True(t, ok)
+		return err
+	}
+
+	assert.Nil(t, p.Ready(context.Background()))
+	err = errors.New("failed to acquire")
+	now = now.Add(4 * time.Second)
+	assert.Nil(t, p.Ready(context.Background()))
+	assert.Equal(t, 1, checks)
+
+	now = now.Add(time.Second)
+	assert.EqualError(t, p.Ready(context.Background()), "failed to acquire")
+	assert.Equal(t, 2, checks)
+}
+
+func TestReadyCanceled(t *testing.T) {
+	p := New(nil, time.Second, time.Minute)
+	checks := 0
+	p.check = func(ctx context.Context) error {
+		checks++
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		return nil
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Equal(t, context.Canceled, p.Ready(ctx))
+	// the canceled check is not cached
+	assert.Nil(t, p.Ready(context.Background()))
+	assert.Equal(t, 2, checks)
+}
@@ -0,0 +1,89 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"fmt"
+	"sync"
+)
+
+// WithQueryHistory keeps the last n statements executed by every session, redacted to their template
+// with FingerprintStatement so that their literals are not leaked. The history is attached to the errors
+// of the executions as a *QueryHistoryError, and to the failed result sets, see ResultSet.QueryHistory,
+// to give context to errors like a SemanticError raised by a dynamically generated statement.
+func WithQueryHistory(n int) PoolConfOption {
+	return func(conf *PoolConfig) {
+		conf.QueryHistory = n
+	}
+}
+
+// QueryHistoryError is an error of an execution with the statements last executed by its session
+type QueryHistoryError struct {
+	Err error
+	// The redacted statements, from the oldest to the one which failed
+	History []string
+}
+
+func (e *QueryHistoryError) Error() string {
+	return fmt.Sprintf("%s, recent statements: %q", e.Err.Error(), e.History)
+}
+
+// Unwrap returns the error of the execution
+func (e *QueryHistoryError) Unwrap() error {
+	return e.Err
+}
+
+// queryHistory is a ring buffer of the redacted statements of a session
+type queryHistory struct {
+	mu    sync.Mutex
+	stmts []string
+	next  int
+}
+
+// record adds the statement to the history, dropping the oldest one once it holds max statements
+func (h *queryHistory) record(max int, stmt string) {
+	redacted := FingerprintStatement(stmt).Template
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if len(h.stmts) < max {
+		h.stmts = append(h.stmts, redacted)
+		return
+	}
+	h.stmts[h.next] = redacted
+	h.next = (h.next + 1) % len(h.stmts)
+}
+
+// snapshot returns the statements from the oldest to the most recent one
+func (h *queryHistory) snapshot() []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	stmts := make([]string, 0, len(h.stmts))
+	stmts = append(stmts, h.stmts[h.next:]...)
+	return append(stmts, h.stmts[:h.next]...)
+}
+
+// QueryHistory returns the redacted statements last executed by the session, from the oldest one,
+// empty unless WithQueryHistory is set in the pool config
+func (session *Session) QueryHistory() []string {
+	return session.history.snapshot()
+}
+
+// withHistory attaches the history of the session to the error or to the failed result set of an execution
+func (session *Session) withHistory(resp *ResultSet, err error) (*ResultSet, error) {
+	if session.connPool.conf.QueryHistory <= 0 {
+		return resp, err
+	}
+	if err != nil {
+		return nil, &QueryHistoryError{Err: err, History: session.history.snapshot()}
+	}
+	if resp != nil && resp.resp != nil && !resp.IsSucceed() {
+		resp.history = session.history.snapshot()
+	}
+	return resp, nil
+}
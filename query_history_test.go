@@ -0,0 +1,77 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vesoft-inc/nebula-go/v3/nebula"
+)
+
+func TestQueryHistory(t *testing.T) {
+	var h queryHistory
+	assert.Empty(t, h.snapshot())
+	for i := 0; i < 5; i++ {
+		h.record(3, fmt.Sprintf("FETCH PROP ON player 'p%d' YIELD vertex AS v", i))
+	}
+	assert.Equal(t, []string{
+		"fetch prop on player ? yield vertex as v",
+		"fetch prop on player ? yield vertex as v",
+		"fetch prop on player ? yield vertex as v",
+	}, h.snapshot())
+	h.record(3, "YIELD 1")
+	assert.Equal(t, "yield ?", h.snapshot()[2])
+}
+
+func TestQueryHistoryErrors(t *testing.T) {
+	broken := errors.New("connection closed")
+	var session *Session
+	// the interceptor fakes the executions of the session and records them, as the invoker does
+	conf := NewPoolConf(WithQueryHistory(2), WithInterceptors(func(ctx context.Context, stmt string, p map[string]interface{}, invoker Invoker) (*ResultSet, error) {
+		session.history.record(2, stmt)
+		switch stmt {
+		case "broken":
+			return nil, broken
+		case "GO FROM 'p1' OVER follow YIELD $$.team.name":
+			resp := newTestResultSet(t, nil)
+			resp.resp.ErrorCode = nebula.ErrorCode_E_SEMANTIC_ERROR
+			resp.resp.ErrorMsg = []byte("SemanticError: `$$.team.name', not exist tag `team'")
+			return resp, nil
+		}
+		return newTestResultSet(t, nil), nil
+	}))
+	session = &Session{connPool: &ConnectionPool{conf: conf}}
+
+	resp, err := session.Execute("MATCH (v:player) WHERE v.player.name == 'Tim' RETURN v")
+	assert.Nil(t, err)
+	assert.Nil(t, resp.QueryHistory())
+
+	resp, err = session.Execute("GO FROM 'p1' OVER follow YIELD $$.team.name")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{
+		"match (v:player) where v.player.name == ? return v",
+		"go from ? over follow yield $$.team.name",
+	}, resp.QueryHistory())
+
+	_, err = session.Execute("broken")
+	var historyErr *QueryHistoryError
+	assert.True(t, errors.As(err, &historyErr))
+	assert.True(t, errors.Is(err, broken))
+	assert.Equal(t, []string{"go from ? over follow yield $$.team.name", "broken"}, historyErr.History)
+	assert.EqualError(t, err, `connection closed, recent statements: ["go from ? over follow yield $$.team.name" "broken"]`)
+	assert.Equal(t, historyErr.History, session.QueryHistory())
+
+	session.connPool.conf.QueryHistory = 0
+	_, err = session.Execute("broken")
+	assert.Equal(t, broken, err)
+}
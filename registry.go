@@ -0,0 +1,145 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// the process-wide registry of the pools opened with OpenNamed
+var registry = struct {
+	sync.Mutex
+	pools map[string]*namedPool
+}{pools: make(map[string]*namedPool)}
+
+type namedPool struct {
+	pool *ConnectionPool
+	cfg  *ConnectionConfig
+}
+
+// OpenNamed opens a pool for the connection string and registers it under the name,
+// so that it can be looked up with Pool and its stats are labeled with the name.
+// If a pool is already registered under the name with an equal config it is returned,
+// if its config differs an error is returned.
+func OpenNamed(name, dsn string, log Logger) (*ConnectionPool, error) {
+	if name == "" {
+		return nil, fmt.Errorf("failed to open pool: empty name")
+	}
+	cfg, err := ParseConnectionString(dsn)
+	if err != nil {
+		return nil, err
+	}
+	if pool, err := lookupNamed(name, cfg); pool != nil || err != nil {
+		return pool, err
+	}
+
+	pool, err := cfg.NewPool(log)
+	if err != nil {
+		return nil, err
+	}
+	pool.name = name
+	registered, err := registerNamed(name, cfg, pool)
+	if registered != pool {
+		// another goroutine opened the pool meanwhile
+		pool.Close()
+	}
+	return registered, err
+}
+
+// Pool returns the pool registered under the name, or nil if there is none
+func Pool(name string) *ConnectionPool {
+	pool, _ := lookupNamed(name, nil)
+	return pool
+}
+
+// NamedPools returns the sorted names of the registered pools
+func NamedPools() []string {
+	registry.Lock()
+	defer registry.Unlock()
+	names := make([]string, 0, len(registry.pools))
+	for name, p := range registry.pools {
+		if !p.pool.IsClosed() {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NamedPoolStats returns the stats of the registered pools by name
+func NamedPoolStats() map[string]PoolStats {
+	stats := make(map[string]PoolStats)
+	for _, name := range NamedPools() {
+		if pool := Pool(name); pool != nil {
+			stats[name] = pool.Stats()
+		}
+	}
+	return stats
+}
+
+// CloseNamed closes the pool registered under the name and unregisters it
+func CloseNamed(name string) error {
+	registry.Lock()
+	p, ok := registry.pools[name]
+	delete(registry.pools, name)
+	registry.Unlock()
+	if !ok {
+		return fmt.Errorf("failed to close pool: no pool named %s", name)
+	}
+	p.pool.Close()
+	return nil
+}
+
+// CloseAllNamed closes and unregisters every registered pool, e.g. when the application shuts down
+func CloseAllNamed() {
+	registry.Lock()
+	pools := registry.pools
+	registry.pools = make(map[string]*namedPool)
+	registry.Unlock()
+	for _, p := range pools {
+		p.pool.Close()
+	}
+}
+
+// lookupNamed returns the pool registered under the name. If cfg is not nil,
+// an error is returned when the registered pool has a different config.
+// Pools closed without CloseNamed are unregistered.
+func lookupNamed(name string, cfg *ConnectionConfig) (*ConnectionPool, error) {
+	registry.Lock()
+	defer registry.Unlock()
+	p, ok := registry.pools[name]
+	if !ok {
+		return nil, nil
+	}
+	if p.pool.IsClosed() {
+		delete(registry.pools, name)
+		return nil, nil
+	}
+	if cfg != nil && !p.cfg.Equal(cfg) {
+		return nil, fmt.Errorf("failed to open pool: the pool %s is already open with a different config", name)
+	}
+	return p.pool, nil
+}
+
+// registerNamed registers the pool under the name, unless an open pool is already registered.
+// It returns the registered pool.
+func registerNamed(name string, cfg *ConnectionConfig, pool *ConnectionPool) (*ConnectionPool, error) {
+	registry.Lock()
+	defer registry.Unlock()
+	if p, ok := registry.pools[name]; ok && !p.pool.IsClosed() {
+		if !p.cfg.Equal(cfg) {
+			return nil, fmt.Errorf("failed to open pool: the pool %s is already open with a different config", name)
+		}
+		return p.pool, nil
+	}
+	registry.pools[name] = &namedPool{pool: pool, cfg: cfg}
+	return pool, nil
+}
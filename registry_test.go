@@ -0,0 +1,56 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistry(t *testing.T) {
+	defer CloseAllNamed()
+
+	cfg, err := ParseConnectionString("nebula://graphd0,graphd1/test")
+	assert.Nil(t, err)
+	analytics := &ConnectionPool{name: "analytics"}
+	registered, err := registerNamed("analytics", cfg, analytics)
+	assert.Nil(t, err)
+	assert.Equal(t, analytics, registered)
+
+	// an equal config returns the registered pool, a different one fails
+	same, _ := ParseConnectionString("nebula://graphd1,graphd0:9669/test")
+	registered, err = registerNamed("analytics", same, &ConnectionPool{})
+	assert.Nil(t, err)
+	assert.Equal(t, analytics, registered)
+	other, _ := ParseConnectionString("nebula://graphd0/test")
+	_, err = lookupNamed("analytics", other)
+	assert.NotNil(t, err)
+
+	assert.Equal(t, analytics, Pool("analytics"))
+	assert.Nil(t, Pool("oltp"))
+	assert.Equal(t, []string{"analytics"}, NamedPools())
+	assert.Equal(t, "analytics", NamedPoolStats()["analytics"].Name)
+
+	assert.Nil(t, CloseNamed("analytics"))
+	assert.True(t, analytics.IsClosed())
+	assert.Nil(t, Pool("analytics"))
+	assert.NotNil(t, CloseNamed("analytics"))
+
+	// pools closed directly are unregistered
+	oltp := &ConnectionPool{}
+	_, err = registerNamed("oltp", cfg, oltp)
+	assert.Nil(t, err)
+	oltp.Close()
+	assert.Nil(t, Pool("oltp"))
+	assert.Empty(t, NamedPools())
+
+	_, err = OpenNamed("", "nebula://graphd0", DefaultLogger{})
+	assert.NotNil(t, err)
+}
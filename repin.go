@@ -0,0 +1,161 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"github.com/facebook/fbthrift/thrift/lib/go/thrift"
+)
+
+// the first keywords of the statements which do not write
+var readOnlyKeywords = map[string]bool{
+	"match": true, "optional": true, "go": true, "fetch": true, "lookup": true, "find": true, "get": true,
+	"show": true, "describe": true, "desc": true, "yield": true, "return": true, "unwind": true, "with": true,
+	"explain": true, "use": true,
+}
+
+// WithSessionRepin recreates the sessions whose connection was reset, e.g. by a restart of the graph service,
+// or which are no longer known by the graph service: a session is authenticated again on another connection
+// with its credentials, the OnConnectStmts, the charset, the timezone and the space of the pool config
+// are applied to it again, and it is switched back to the space it was using.
+// If replayReadOnly is set, the statement which failed is executed again if it is read-only,
+// either because its StatementMeta says so or because all its clauses are reads, e.g. MATCH, GO or FETCH.
+// Otherwise its error is returned once the session has been recreated.
+func WithSessionRepin(replayReadOnly bool) PoolConfOption {
+	return func(conf *PoolConfig) {
+		conf.SessionRepin = true
+		conf.ReplayReadOnly = replayReadOnly
+	}
+}
+
+// shouldRepin returns true if the execution failed because the connection was reset or the session was lost
+func (session *Session) shouldRepin(ctx context.Context, resp interface{}, err error) bool {
+	if !session.connPool.conf.SessionRepin || ctx.Err() != nil || atomic.LoadInt32(&session.repinning) != 0 {
+		return false
+	}
+	if err != nil {
+		return isConnectionReset(err)
+	}
+	return isSessionExpired(resp.(*ResultSet))
+}
+
+// repinAndReplay recreates the session and executes the statement again with run if it is read-only
+// and ReplayReadOnly is set, otherwise it returns the outcome of the failed execution
+func (session *Session) repinAndReplay(ctx context.Context, stmt string, resp interface{}, err error,
+	run func() (interface{}, error)) (interface{}, error) {
+	if rerr := session.repin(); rerr != nil {
+		return nil, fmt.Errorf("failed to recreate the session after %s: %s", repinCause(resp, err), rerr.Error())
+	}
+	meta, _ := StatementMetaFrom(ctx)
+	if session.connPool.conf.ReplayReadOnly && (meta.ReadOnly || isReadOnlyStatement(stmt)) {
+		return run()
+	}
+	return resp, err
+}
+
+// repin authenticates the session again on another connection and applies the session settings to it
+func (session *Session) repin() error {
+	atomic.StoreInt32(&session.repinning, 1)
+	defer atomic.StoreInt32(&session.repinning, 0)
+
+	pool := session.connPool
+	conn, err := pool.getIdleConn()
+	if err != nil {
+		return err
+	}
+	session.mu.Lock()
+	old, oldID, space := session.connection, session.sessionID, session.space
+	if old == nil {
+		session.mu.Unlock()
+		pool.release(conn)
+		return fmt.Errorf("Session has been released")
+	}
+	// the new connection counts in the workload partition of the old one
+	pool.rwLock.Lock()
+	pool.assignWorkloadLocked(conn, old.workload)
+	pool.rwLock.Unlock()
+	auth, err := conn.authenticate(session.username, session.password)
+	if err != nil {
+		session.mu.Unlock()
+		pool.release(conn)
+		return err
+	}
+	// the graph service may still know the old session, e.g. if only the connection was reset
+	conn.signOut(oldID)
+	pool.release(old)
+	pool.rwLock.Lock()
+	pool.bindSessionLocked(conn, auth.GetSessionID())
+	pool.rwLock.Unlock()
+	session.connection, session.sessionID, session.space = conn, auth.GetSessionID(), ""
+	session.timezoneInfo = timezoneInfo{auth.GetTimeZoneOffsetSeconds(), auth.GetTimeZoneName()}
+	session.mu.Unlock()
+	pool.sessionEvent(SessionEvent{Kind: SessionRecreated, SessionID: auth.GetSessionID(), PreviousSessionID: oldID,
+		Host: conn.severAddress})
+
+	if err := session.onAcquire(pool.conf); err != nil {
+		return err
+	}
+	if space != "" && space != session.GetSpaceName() {
+		resp, err := session.Execute("USE " + QuoteIdentifier(space))
+		if err != nil {
+			return err
+		}
+		if !resp.IsSucceed() {
+			return fmt.Errorf("failed to use space %s: %s", space, resp.GetErrorMsg())
+		}
+	}
+	session.log.Info(fmt.Sprintf("Session %d has been recreated as session %d on host: %s, port: %d",
+		oldID, session.GetSessionID(), conn.severAddress.Host, conn.severAddress.Port))
+	return nil
+}
+
+// isConnectionReset returns true if the error is caused by a closed or reset connection
+func isConnectionReset(err error) bool {
+	if e, ok := err.(thrift.TransportException); ok {
+		switch e.TypeID() {
+		case thrift.END_OF_FILE, thrift.NOT_OPEN:
+			return true
+		}
+	}
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "connection reset") || strings.Contains(msg, "broken pipe") ||
+		strings.Contains(msg, "failed to reconnect")
+}
+
+func repinCause(resp interface{}, err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	return resp.(*ResultSet).GetErrorMsg()
+}
+
+// isReadOnlyStatement returns true if all the statements and the piped clauses of stmt start with a read keyword
+func isReadOnlyStatement(stmt string) bool {
+	first := true
+	words := 0
+	for _, t := range lexStatement(stmt) {
+		switch {
+		case t.kind == tokenPunct && (t.text == ";" || t.text == "|"):
+			first = true
+		case t.kind == tokenWord && first:
+			if !readOnlyKeywords[strings.ToLower(t.text)] {
+				return false
+			}
+			first = false
+			words++
+		case t.kind != tokenSpace && t.kind != tokenPunct:
+			first = false
+		}
+	}
+	return words > 0
+}
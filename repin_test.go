@@ -0,0 +1,67 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/facebook/fbthrift/thrift/lib/go/thrift"
+	"github.com/stretchr/testify/assert"
+	"github.com/vesoft-inc/nebula-go/v3/nebula"
+)
+
+func TestIsReadOnlyStatement(t *testing.T) {
+	for _, stmt := range []string{
+		"MATCH (v:player) RETURN v LIMIT 10",
+		"USE nba; GO FROM 'p1' OVER follow YIELD dst(edge) AS id | FETCH PROP ON player $-.id YIELD vertex",
+		"/* client_name=svc */ LOOKUP ON player WHERE player.name == 'a;b|c' YIELD id(vertex)",
+		"SHOW HOSTS",
+	} {
+		assert.True(t, isReadOnlyStatement(stmt), stmt)
+	}
+	for _, stmt := range []string{
+		"INSERT VERTEX player(name) VALUES 'p1':('Tim')",
+		"GO FROM 'p1' OVER follow YIELD dst(edge) AS id | DELETE VERTEX $-.id",
+		"USE nba; UPDATE VERTEX ON player 'p1' SET age = 1",
+		"MATCH (v) WHERE v.player.age > 1 || v.player.age < 0 RETURN v",
+		"",
+	} {
+		assert.False(t, isReadOnlyStatement(stmt), stmt)
+	}
+}
+
+func TestIsConnectionReset(t *testing.T) {
+	assert.True(t, isConnectionReset(thrift.NewTransportException(thrift.END_OF_FILE, "EOF")))
+	assert.True(t, isConnectionReset(thrift.NewTransportException(thrift.UNKNOWN_TRANSPORT_EXCEPTION,
+		"read tcp 10.0.0.1:9669: connection reset by peer")))
+	assert.True(t, isConnectionReset(fmt.Errorf("failed to reconnect, failed to get connection")))
+	assert.False(t, isConnectionReset(fmt.Errorf("failed to execute: Session has been released")))
+}
+
+func TestShouldRepin(t *testing.T) {
+	session := &Session{connPool: &ConnectionPool{conf: NewPoolConf(WithSessionRepin(true))}}
+	eof := thrift.NewTransportException(thrift.END_OF_FILE, "EOF")
+	assert.True(t, session.shouldRepin(context.Background(), nil, eof))
+
+	expired := newTestResultSet(t, nil)
+	expired.resp.ErrorCode = nebula.ErrorCode_E_SESSION_NOT_FOUND
+	assert.True(t, session.shouldRepin(context.Background(), expired, nil))
+	assert.False(t, session.shouldRepin(context.Background(), newTestResultSet(t, nil), nil))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.False(t, session.shouldRepin(ctx, nil, eof))
+	session.repinning = 1
+	assert.False(t, session.shouldRepin(context.Background(), nil, eof))
+
+	session = &Session{connPool: &ConnectionPool{conf: NewPoolConf()}}
+	assert.False(t, session.shouldRepin(context.Background(), nil, eof))
+}
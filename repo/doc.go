@@ -0,0 +1,11 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+// Package repo provides a generic data access layer for the structs mapped with the ogm package.
+// It requires go 1.18 or later.
+package repo
@@ -0,0 +1,89 @@
+//go:build go1.18
+// +build go1.18
+
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package repo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	nebula "github.com/vesoft-inc/nebula-go/v3"
+)
+
+// Predicate is a condition of a MATCH statement on the vertex variable v, e.g. "v.player.tenant_id == $tenant",
+// with the parameters it refers to
+type Predicate struct {
+	Expr   string
+	Params map[string]interface{}
+}
+
+// Policy returns the predicate restricting the vertices of the tag read with the context, e.g. to the tenant
+// of the caller, so that the restriction is enforced by the repository instead of every query.
+// It is appended to the statements of Get, List and LoadRelated. The custom statements of Query and Find
+// are not rewritten, the parameters of the predicate are passed to them so that they can refer to them.
+// An empty predicate reads all the vertices, an error fails the read.
+type Policy func(ctx context.Context, tag string) (Predicate, error)
+
+// ContextValuePolicy returns a policy restricting the vertices to those whose property equals the value
+// of the context for key, e.g. the tenant set by an authentication middleware.
+// The reads fail if the context has no value for key.
+func ContextValuePolicy(prop string, key interface{}) Policy {
+	param := "policy_" + prop
+	return func(ctx context.Context, tag string) (Predicate, error) {
+		value := ctx.Value(key)
+		if value == nil {
+			return Predicate{}, fmt.Errorf("failed to apply policy: no %v in context", key)
+		}
+		return Predicate{
+			Expr:   fmt.Sprintf("v.%s.%s == $%s", nebula.QuoteIdentifier(tag), nebula.QuoteIdentifier(prop), param),
+			Params: map[string]interface{}{param: value},
+		}, nil
+	}
+}
+
+// conditions returns the conditions of the reads of the context, the policy and the soft delete filter,
+// and the parameters of the policy merged into params
+func (r *Repository[T]) conditions(ctx context.Context, tag string, params map[string]interface{}) ([]string, map[string]interface{}, error) {
+	var conds []string
+	if prop := r.softDelete(); prop != "" {
+		conds = append(conds, fmt.Sprintf("v.%s.%s IS NULL", nebula.QuoteIdentifier(tag), nebula.QuoteIdentifier(prop)))
+	}
+	if r.mapping.Policy == nil {
+		return conds, params, nil
+	}
+	pred, err := r.mapping.Policy(ctx, tag)
+	if err != nil {
+		return nil, nil, err
+	}
+	if pred.Expr == "" {
+		return conds, params, nil
+	}
+	merged := make(map[string]interface{}, len(params)+len(pred.Params))
+	for k, v := range params {
+		merged[k] = v
+	}
+	for k, v := range pred.Params {
+		if _, ok := merged[k]; ok {
+			return nil, nil, fmt.Errorf("failed to apply policy: parameter %s is already set", k)
+		}
+		merged[k] = v
+	}
+	return append(conds, "("+pred.Expr+")"), merged, nil
+}
+
+// whereClause returns the WHERE clause of the conditions, empty if there are none
+func whereClause(conds []string) string {
+	if len(conds) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(conds, " AND ")
+}
@@ -0,0 +1,453 @@
+//go:build go1.18
+// +build go1.18
+
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	nebula "github.com/vesoft-inc/nebula-go/v3"
+	"github.com/vesoft-inc/nebula-go/v3/ogm"
+)
+
+// ErrNotFound is returned by Get when the vertex does not exist
+var ErrNotFound = errors.New("vertex not found")
+
+// DefaultSoftDeleteProp is the conventional property of the soft deleted vertices, see Mapping.SoftDelete
+const DefaultSoftDeleteProp = "deleted_at"
+
+// Direction is the direction of the edges of a relation
+type Direction int
+
+const (
+	// Outgoing follows the edges from their source to their destination
+	Outgoing Direction = iota
+	// Incoming follows the edges from their destination to their source
+	Incoming
+	// Both follows the edges in both directions
+	Both
+)
+
+// Relation is an edge type leading from the vertices of a repository to other vertices
+type Relation struct {
+	Edge      string
+	Direction Direction
+}
+
+// Mapping describes where the vertices of a repository are stored
+type Mapping struct {
+	// The space of the vertices
+	Space string
+	// The tag of the vertices
+	Tag string
+	// The vid type of the space, it is resolved with DESCRIBE SPACE if unknown
+	VIDType nebula.VIDType
+	// The relations which can be loaded with LoadRelated, by name
+	Relations map[string]Relation
+	// The custom queries which can be run with Find, by name.
+	// The queries must return the vertices in a column, e.g. "LOOKUP ON player WHERE player.age > $age YIELD vertex AS v"
+	Queries map[string]string
+	// The property of the tag marking the soft deleted vertices, e.g. DefaultSoftDeleteProp,
+	// defined as "deleted_at timestamp NULL". If set, Delete sets it to the current timestamp instead of
+	// deleting the vertex, and the vertices where it is not null are filtered out of the reads.
+	// See Unscoped to read them or to delete them for good.
+	SoftDelete string
+	// The policy restricting the vertices read, e.g. to the tenant of the caller, see Policy
+	Policy Policy
+}
+
+// Repository provides Get, List, Save and Delete for the vertices of a tag mapped to T
+type Repository[T any] struct {
+	pool    *nebula.ConnectionPool
+	mapping Mapping
+	model   *ogm.Model
+
+	// the soft deleted vertices are neither filtered out nor soft deleted, see Unscoped
+	unscoped bool
+
+	mu      sync.Mutex
+	vidType nebula.VIDType
+}
+
+// New returns a repository of the vertices of the mapping tag, mapped to the struct T.
+// The sessions are acquired from the pool with the credentials of its config.
+func New[T any](pool *nebula.ConnectionPool, mapping Mapping) (*Repository[T], error) {
+	var zero T
+	model, err := ogm.ModelOf(&zero)
+	if err != nil {
+		return nil, err
+	}
+	if mapping.Space == "" || mapping.Tag == "" {
+		return nil, fmt.Errorf("failed to create repository: space and tag are required")
+	}
+	return &Repository[T]{
+		pool:    pool,
+		mapping: mapping,
+		model:   model,
+		vidType: mapping.VIDType,
+	}, nil
+}
+
+// Unscoped returns a repository of the same vertices which reads the soft deleted vertices
+// and whose Delete deletes the vertices for good. The Policy of the mapping still applies.
+func (r *Repository[T]) Unscoped() *Repository[T] {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return &Repository[T]{
+		pool:     r.pool,
+		mapping:  r.mapping,
+		model:    r.model,
+		unscoped: true,
+		vidType:  r.vidType,
+	}
+}
+
+// softDelete returns the soft delete property, empty if the vertices are deleted for good
+func (r *Repository[T]) softDelete() string {
+	if r.unscoped {
+		return ""
+	}
+	return r.mapping.SoftDelete
+}
+
+// Model returns the struct mapping of the repository
+func (r *Repository[T]) Model() *ogm.Model {
+	return r.model
+}
+
+// Get returns the vertex with the given ID, or ErrNotFound
+func (r *Repository[T]) Get(ctx context.Context, vid nebula.VID) (*T, error) {
+	var result []*T
+	err := r.withSession(ctx, func(session *nebula.Session, vidType nebula.VIDType) error {
+		id, err := vid.Format(vidType)
+		if err != nil {
+			return err
+		}
+		stmt, params, err := r.getStmt(ctx, id)
+		if err != nil {
+			return err
+		}
+		result, err = r.query(ctx, session, stmt, params)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		return nil, ErrNotFound
+	}
+	return result[0], nil
+}
+
+func (r *Repository[T]) getStmt(ctx context.Context, id string) (string, map[string]interface{}, error) {
+	if r.mapping.Policy == nil {
+		// the soft deleted vertex is filtered out by query
+		return fmt.Sprintf("FETCH PROP ON %s %s YIELD vertex AS v", nebula.QuoteIdentifier(r.mapping.Tag), id), nil, nil
+	}
+	conds, params, err := r.conditions(ctx, r.mapping.Tag, nil)
+	if err != nil {
+		return "", nil, err
+	}
+	conds = append([]string{fmt.Sprintf("id(v) == %s", id)}, conds...)
+	return fmt.Sprintf("MATCH (v:%s)%s RETURN v", nebula.QuoteIdentifier(r.mapping.Tag), whereClause(conds)), params, nil
+}
+
+// List returns at most limit vertices of the tag, the tag must be indexed
+func (r *Repository[T]) List(ctx context.Context, limit int) ([]*T, error) {
+	var result []*T
+	err := r.withSession(ctx, func(session *nebula.Session, _ nebula.VIDType) error {
+		stmt, params, err := r.listStmt(ctx, limit)
+		if err != nil {
+			return err
+		}
+		result, err = r.query(ctx, session, stmt, params)
+		return err
+	})
+	return result, err
+}
+
+func (r *Repository[T]) listStmt(ctx context.Context, limit int) (string, map[string]interface{}, error) {
+	conds, params, err := r.conditions(ctx, r.mapping.Tag, nil)
+	if err != nil {
+		return "", nil, err
+	}
+	return fmt.Sprintf("MATCH (v:%s)%s RETURN v LIMIT %d", nebula.QuoteIdentifier(r.mapping.Tag), whereClause(conds), limit),
+		params, nil
+}
+
+// Save inserts the vertex, replacing the properties of the tag if the vertex exists.
+// Save, Delete and Restore render their statement instead of executing it in dry-run mode, see nebula.WithDryRun.
+func (r *Repository[T]) Save(ctx context.Context, v *T) error {
+	return r.withSession(ctx, func(session *nebula.Session, vidType nebula.VIDType) error {
+		stmt, err := r.insertStmt(v, vidType)
+		if err != nil {
+			return err
+		}
+		return r.write(ctx, session, stmt)
+	})
+}
+
+// Delete deletes the vertex with the given ID and its edges,
+// or only marks the vertex as deleted if the mapping has a SoftDelete property
+func (r *Repository[T]) Delete(ctx context.Context, vid nebula.VID) error {
+	return r.withSession(ctx, func(session *nebula.Session, vidType nebula.VIDType) error {
+		id, err := vid.Format(vidType)
+		if err != nil {
+			return err
+		}
+		return r.write(ctx, session, r.deleteStmt(id))
+	})
+}
+
+func (r *Repository[T]) deleteStmt(id string) string {
+	if prop := r.softDelete(); prop != "" {
+		return fmt.Sprintf("UPDATE VERTEX ON %s %s SET %s = timestamp()",
+			nebula.QuoteIdentifier(r.mapping.Tag), id, nebula.QuoteIdentifier(prop))
+	}
+	return fmt.Sprintf("DELETE VERTEX %s WITH EDGE", id)
+}
+
+// Restore clears the SoftDelete property of a soft deleted vertex
+func (r *Repository[T]) Restore(ctx context.Context, vid nebula.VID) error {
+	if r.mapping.SoftDelete == "" {
+		return fmt.Errorf("failed to restore: the mapping has no soft delete property")
+	}
+	return r.withSession(ctx, func(session *nebula.Session, vidType nebula.VIDType) error {
+		id, err := vid.Format(vidType)
+		if err != nil {
+			return err
+		}
+		return r.write(ctx, session, fmt.Sprintf("UPDATE VERTEX ON %s %s SET %s = NULL",
+			nebula.QuoteIdentifier(r.mapping.Tag), id, nebula.QuoteIdentifier(r.mapping.SoftDelete)))
+	})
+}
+
+// Find runs the custom query of the mapping with the given name and returns its vertices
+func (r *Repository[T]) Find(ctx context.Context, name string, params map[string]interface{}) ([]*T, error) {
+	stmt, ok := r.mapping.Queries[name]
+	if !ok {
+		return nil, fmt.Errorf("failed to find: unknown query %s", name)
+	}
+	return r.Query(ctx, stmt, params)
+}
+
+// Query runs the statement and returns the vertices of the tag found in its result.
+// The properties are masked by the FieldMasker of the context, see nebula.WithFieldMasker.
+func (r *Repository[T]) Query(ctx context.Context, stmt string, params map[string]interface{}) ([]*T, error) {
+	var result []*T
+	err := r.withSession(ctx, func(session *nebula.Session, _ nebula.VIDType) error {
+		_, params, err := r.conditions(ctx, r.mapping.Tag, params)
+		if err != nil {
+			return err
+		}
+		result, err = r.query(ctx, session, stmt, params)
+		return err
+	})
+	return result, err
+}
+
+// LoadRelated returns the vertices of the repository to reached from the vertex vid of the repository from
+// through the named relation of from.
+func LoadRelated[T, U any](ctx context.Context, from *Repository[T], to *Repository[U], relation string, vid nebula.VID) ([]*U, error) {
+	rel, ok := from.mapping.Relations[relation]
+	if !ok {
+		return nil, fmt.Errorf("failed to load related: unknown relation %s", relation)
+	}
+	if from.mapping.Space != to.mapping.Space {
+		return nil, fmt.Errorf("failed to load related: relation %s crosses spaces", relation)
+	}
+	var result []*U
+	err := from.withSession(ctx, func(session *nebula.Session, vidType nebula.VIDType) error {
+		id, err := vid.Format(vidType)
+		if err != nil {
+			return err
+		}
+		stmt, params, err := relatedStmt(ctx, to, rel, id)
+		if err != nil {
+			return err
+		}
+		result, err = to.query(ctx, session, stmt, params)
+		return err
+	})
+	return result, err
+}
+
+func relatedStmt[U any](ctx context.Context, to *Repository[U], rel Relation, id string) (string, map[string]interface{}, error) {
+	if to.mapping.Policy == nil {
+		// the soft deleted vertices are filtered out by query
+		return fmt.Sprintf("GO FROM %s OVER %s%s YIELD $$ AS v",
+			id, nebula.QuoteIdentifier(rel.Edge), directionClause(rel.Direction)), nil, nil
+	}
+	conds, params, err := to.conditions(ctx, to.mapping.Tag, nil)
+	if err != nil {
+		return "", nil, err
+	}
+	conds = append([]string{fmt.Sprintf("id(s) == %s", id)}, conds...)
+	return fmt.Sprintf("MATCH (s)%s(v:%s)%s RETURN v", matchPattern(rel), nebula.QuoteIdentifier(to.mapping.Tag),
+		whereClause(conds)), params, nil
+}
+
+// matchPattern returns the MATCH pattern of the edges of the relation
+func matchPattern(rel Relation) string {
+	edge := nebula.QuoteIdentifier(rel.Edge)
+	switch rel.Direction {
+	case Incoming:
+		return "<-[:" + edge + "]-"
+	case Both:
+		return "-[:" + edge + "]-"
+	default:
+		return "-[:" + edge + "]->"
+	}
+}
+
+func directionClause(d Direction) string {
+	switch d {
+	case Incoming:
+		return " REVERSELY"
+	case Both:
+		return " BIDIRECT"
+	default:
+		return ""
+	}
+}
+
+// withSession acquires a session, resolves the vid type of the space and calls f
+func (r *Repository[T]) withSession(ctx context.Context, f func(*nebula.Session, nebula.VIDType) error) error {
+	session, err := r.pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer session.Release()
+
+	r.mu.Lock()
+	vidType := r.vidType
+	if vidType == nebula.VIDTypeUnknown {
+		vidType, err = session.GetVIDType(r.mapping.Space)
+		if err != nil {
+			r.mu.Unlock()
+			return err
+		}
+		r.vidType = vidType
+	}
+	r.mu.Unlock()
+	return f(session, vidType)
+}
+
+func (r *Repository[T]) execute(ctx context.Context, session *nebula.Session, stmt string, params map[string]interface{}) (*nebula.ResultSet, error) {
+	return r.run(ctx, session.ExecuteWithContext, stmt, params)
+}
+
+// write executes the write statement, or renders it in dry-run mode, see nebula.WithDryRun
+func (r *Repository[T]) write(ctx context.Context, session *nebula.Session, stmt string) error {
+	_, err := r.run(ctx, session.ExecuteWrite, stmt, nil)
+	return err
+}
+
+func (r *Repository[T]) run(ctx context.Context,
+	execute func(context.Context, string, map[string]interface{}, ...nebula.ExecOption) (*nebula.ResultSet, error),
+	stmt string, params map[string]interface{}) (*nebula.ResultSet, error) {
+	resp, err := execute(ctx, fmt.Sprintf("USE %s; %s", nebula.QuoteIdentifier(r.mapping.Space), stmt), params)
+	if err != nil {
+		return nil, err
+	}
+	if !resp.IsSucceed() {
+		return nil, fmt.Errorf("failed to execute %s: %s", stmt, resp.GetErrorMsg())
+	}
+	return resp, nil
+}
+
+// query executes the statement and decodes the vertices of the result having the tag of the repository
+func (r *Repository[T]) query(ctx context.Context, session *nebula.Session, stmt string, params map[string]interface{}) ([]*T, error) {
+	resp, err := r.execute(ctx, session, stmt, params)
+	if err != nil {
+		return nil, err
+	}
+	var result []*T
+	for i := 0; i < resp.GetRowSize(); i++ {
+		record, err := resp.GetRowValuesByIndex(i)
+		if err != nil {
+			return nil, err
+		}
+		for j := 0; j < resp.GetColSize(); j++ {
+			val, err := record.GetValueByIndex(j)
+			if err != nil {
+				return nil, err
+			}
+			if !val.IsVertex() {
+				continue
+			}
+			node, err := val.AsNode()
+			if err != nil {
+				return nil, err
+			}
+			if !node.HasTag(r.mapping.Tag) {
+				continue
+			}
+			deleted, err := r.isDeleted(node)
+			if err != nil {
+				return nil, err
+			}
+			if deleted {
+				break
+			}
+			v := new(T)
+			if err := r.model.DecodeMasked(node, r.mapping.Tag, v, nebula.FieldMaskerFrom(ctx)); err != nil {
+				return nil, err
+			}
+			result = append(result, v)
+			break
+		}
+	}
+	return result, nil
+}
+
+// isDeleted returns true if the vertex is soft deleted and must be filtered out
+func (r *Repository[T]) isDeleted(node *nebula.Node) (bool, error) {
+	prop := r.softDelete()
+	if prop == "" {
+		return false, nil
+	}
+	props, err := node.Properties(r.mapping.Tag)
+	if err != nil {
+		return false, err
+	}
+	val, ok := props[prop]
+	return ok && !val.IsNull() && !val.IsEmpty(), nil
+}
+
+func (r *Repository[T]) insertStmt(v *T, vidType nebula.VIDType) (string, error) {
+	vid, err := r.model.VID(v)
+	if err != nil {
+		return "", err
+	}
+	id, err := vid.Format(vidType)
+	if err != nil {
+		return "", err
+	}
+	values, err := r.model.Values(v)
+	if err != nil {
+		return "", err
+	}
+	props := make([]string, len(values))
+	literals := make([]string, len(values))
+	for i, prop := range r.model.Props() {
+		props[i] = nebula.QuoteIdentifier(prop)
+		if literals[i], err = nebula.FormatValue(values[i]); err != nil {
+			return "", err
+		}
+	}
+	return fmt.Sprintf("INSERT VERTEX %s(%s) VALUES %s:(%s)", nebula.QuoteIdentifier(r.mapping.Tag),
+		strings.Join(props, ", "), id, strings.Join(literals, ", ")), nil
+}
@@ -0,0 +1,92 @@
+//go:build go1.18
+// +build go1.18
+
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package repo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	nebula "github.com/vesoft-inc/nebula-go/v3"
+)
+
+type player struct {
+	ID   int64  `nebula:",vid"`
+	Name string `nebula:"name"`
+	Age  *int64 `nebula:"age"`
+}
+
+func TestInsertStmt(t *testing.T) {
+	r, err := New[player](nil, Mapping{Space: "nba", Tag: "player"})
+	assert.Nil(t, err)
+
+	stmt, err := r.insertStmt(&player{ID: 100, Name: `Tim "The Big Fundamental" Duncan`}, nebula.VIDTypeInt64)
+	assert.Nil(t, err)
+	assert.Equal(t, "INSERT VERTEX `player`(`name`, `age`) VALUES 100:(\"Tim \\\"The Big Fundamental\\\" Duncan\", NULL)", stmt)
+
+	stmt, err = r.insertStmt(&player{ID: 100}, nebula.VIDTypeFixedString)
+	assert.Nil(t, err)
+	assert.Equal(t, "INSERT VERTEX `player`(`name`, `age`) VALUES \"100\":(\"\", NULL)", stmt)
+}
+
+func TestNewInvalidMapping(t *testing.T) {
+	_, err := New[player](nil, Mapping{Space: "nba"})
+	assert.NotNil(t, err)
+	_, err = New[int](nil, Mapping{Space: "nba", Tag: "player"})
+	assert.NotNil(t, err)
+}
+
+func TestSoftDelete(t *testing.T) {
+	r, err := New[player](nil, Mapping{Space: "nba", Tag: "player", SoftDelete: DefaultSoftDeleteProp})
+	assert.Nil(t, err)
+	stmt, _, err := r.listStmt(context.Background(), 10)
+	assert.Nil(t, err)
+	assert.Equal(t, "MATCH (v:`player`) WHERE v.`player`.`deleted_at` IS NULL RETURN v LIMIT 10", stmt)
+	assert.Equal(t, "UPDATE VERTEX ON `player` 100 SET `deleted_at` = timestamp()", r.deleteStmt("100"))
+
+	unscoped := r.Unscoped()
+	stmt, _, err = unscoped.listStmt(context.Background(), 10)
+	assert.Nil(t, err)
+	assert.Equal(t, "MATCH (v:`player`) RETURN v LIMIT 10", stmt)
+	assert.Equal(t, "DELETE VERTEX 100 WITH EDGE", unscoped.deleteStmt("100"))
+	assert.Equal(t, DefaultSoftDeleteProp, r.softDelete(), "the repository must not be changed")
+}
+
+type tenantKey struct{}
+
+func TestPolicy(t *testing.T) {
+	r, err := New[player](nil, Mapping{Space: "nba", Tag: "player", SoftDelete: DefaultSoftDeleteProp,
+		Policy: ContextValuePolicy("tenant_id", tenantKey{})})
+	assert.Nil(t, err)
+	ctx := context.WithValue(context.Background(), tenantKey{}, "acme")
+
+	stmt, params, err := r.getStmt(ctx, "100")
+	assert.Nil(t, err)
+	assert.Equal(t, "MATCH (v:`player`) WHERE id(v) == 100 AND v.`player`.`deleted_at` IS NULL"+
+		" AND (v.`player`.`tenant_id` == $policy_tenant_id) RETURN v", stmt)
+	assert.Equal(t, map[string]interface{}{"policy_tenant_id": "acme"}, params)
+
+	stmt, _, err = r.Unscoped().listStmt(ctx, 5)
+	assert.Nil(t, err)
+	assert.Equal(t, "MATCH (v:`player`) WHERE (v.`player`.`tenant_id` == $policy_tenant_id) RETURN v LIMIT 5", stmt)
+
+	stmt, _, err = relatedStmt(ctx, r.Unscoped(), Relation{Edge: "follow", Direction: Incoming}, "100")
+	assert.Nil(t, err)
+	assert.Equal(t, "MATCH (s)<-[:`follow`]-(v:`player`) WHERE id(s) == 100"+
+		" AND (v.`player`.`tenant_id` == $policy_tenant_id) RETURN v", stmt)
+
+	// the reads fail closed without a tenant
+	_, _, err = r.getStmt(context.Background(), "100")
+	assert.NotNil(t, err)
+	_, _, err = r.conditions(ctx, "player", map[string]interface{}{"policy_tenant_id": "other"})
+	assert.NotNil(t, err)
+}
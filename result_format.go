@@ -0,0 +1,139 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// ResultFormat is the layout used by ResultSet.Format
+type ResultFormat int
+
+const (
+	// FormatTable lays the rows out in a table with borders, like nebula-console
+	FormatTable ResultFormat = iota
+	// FormatMarkdown lays the rows out in a markdown table
+	FormatMarkdown
+	// FormatVertical lays every row out as a list of "column: value" lines
+	FormatVertical
+)
+
+// Format writes the rows of the result to w in the given layout,
+// the values are formatted with ValueWrapper.String. Nothing is written if the result has no columns.
+func (res ResultSet) Format(w io.Writer, format ResultFormat) error {
+	table := res.AsStringTable()
+	if len(table[0]) == 0 && format >= FormatTable && format <= FormatVertical {
+		return nil
+	}
+	for _, row := range table {
+		for i, cell := range row {
+			row[i] = escapeCell(cell, format)
+		}
+	}
+	bw := bufio.NewWriter(w)
+	switch format {
+	case FormatTable:
+		writeTable(bw, table)
+	case FormatMarkdown:
+		writeMarkdown(bw, table)
+	case FormatVertical:
+		writeVertical(bw, table)
+	default:
+		return fmt.Errorf("failed to format result: unknown format %d", format)
+	}
+	return bw.Flush()
+}
+
+// escapeCell keeps every value on a single line, and escapes the pipes of markdown tables
+func escapeCell(cell string, format ResultFormat) string {
+	cell = strings.NewReplacer("\r", `\r`, "\n", `\n`).Replace(cell)
+	if format == FormatMarkdown {
+		cell = strings.Replace(cell, "|", `\|`, -1)
+	}
+	return cell
+}
+
+// columnWidths returns the max number of runes of every column
+func columnWidths(table [][]string) []int {
+	widths := make([]int, len(table[0]))
+	for _, row := range table {
+		for i, cell := range row {
+			if n := utf8.RuneCountInString(cell); i < len(widths) && n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+	return widths
+}
+
+func pad(s string, width int) string {
+	return s + strings.Repeat(" ", width-utf8.RuneCountInString(s))
+}
+
+func writeTable(w *bufio.Writer, table [][]string) {
+	widths := columnWidths(table)
+	border := "+"
+	for _, width := range widths {
+		border += strings.Repeat("-", width+2) + "+"
+	}
+	border += "\n"
+	for i, row := range table {
+		if i <= 1 {
+			w.WriteString(border)
+		}
+		w.WriteString("|")
+		for j, cell := range row {
+			w.WriteString(" " + pad(cell, widths[j]) + " |")
+		}
+		w.WriteString("\n")
+	}
+	w.WriteString(border)
+}
+
+func writeMarkdown(w *bufio.Writer, table [][]string) {
+	widths := columnWidths(table)
+	for i, row := range table {
+		w.WriteString("|")
+		for j, cell := range row {
+			w.WriteString(" " + pad(cell, widths[j]) + " |")
+		}
+		w.WriteString("\n")
+		if i == 0 {
+			w.WriteString("|")
+			for _, width := range widths {
+				// markdown requires at least 3 dashes
+				if width < 3 {
+					width = 3
+				}
+				w.WriteString(" " + strings.Repeat("-", width) + " |")
+			}
+			w.WriteString("\n")
+		}
+	}
+}
+
+func writeVertical(w *bufio.Writer, table [][]string) {
+	header := table[0]
+	width := 0
+	for _, name := range header {
+		if n := utf8.RuneCountInString(name); n > width {
+			width = n
+		}
+	}
+	for i, row := range table[1:] {
+		fmt.Fprintf(w, "*************************** %d. row ***************************\n", i+1)
+		for j, cell := range row {
+			fmt.Fprintf(w, "%s%s: %s\n", strings.Repeat(" ", width-utf8.RuneCountInString(header[j])), header[j], cell)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResultSetFormat(t *testing.T) {
+	resp := newTestResultSet(t, []string{"name", "age"},
+		[]interface{}{"Tim Duncan", 42},
+		[]interface{}{"a|b\nc", nil})
+
+	var buf bytes.Buffer
+	assert.Nil(t, resp.Format(&buf, FormatTable))
+	assert.Equal(t, ""+
+		"+--------------+----------+\n"+
+		"| name         | age      |\n"+
+		"+--------------+----------+\n"+
+		"| \"Tim Duncan\" | 42       |\n"+
+		"| \"a|b\\nc\"     | __NULL__ |\n"+
+		"+--------------+----------+\n", buf.String())
+
+	buf.Reset()
+	assert.Nil(t, resp.Format(&buf, FormatMarkdown))
+	assert.Equal(t, ""+
+		"| name         | age      |\n"+
+		"| ------------ | -------- |\n"+
+		"| \"Tim Duncan\" | 42       |\n"+
+		"| \"a\\|b\\nc\"    | __NULL__ |\n", buf.String())
+
+	buf.Reset()
+	assert.Nil(t, resp.Format(&buf, FormatVertical))
+	assert.Equal(t, ""+
+		"*************************** 1. row ***************************\n"+
+		"name: \"Tim Duncan\"\n"+
+		" age: 42\n"+
+		"*************************** 2. row ***************************\n"+
+		"name: \"a|b\\nc\"\n"+
+		" age: __NULL__\n", buf.String())
+
+	assert.NotNil(t, resp.Format(&buf, ResultFormat(-1)))
+}
@@ -0,0 +1,120 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"context"
+	"hash/fnv"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// ResultCheck configures the profiling of the results of the statements of a fingerprint, see ResultProfileInterceptor
+type ResultCheck struct {
+	// The digest of the fingerprint of the statements, see FingerprintStatement
+	Digest string
+	// The columns whose values are checksummed
+	ChecksumColumns []string
+	// The max number of rows sampled from every result, 0 disables the sampling
+	SampleRows int
+}
+
+// ResultProfile is the profile of the result of a statement, e.g. to detect drifts of the data it reads
+type ResultProfile struct {
+	StatementFingerprint
+	// The tenant of the statement, see WithTenantFromContext
+	Tenant string
+	Rows   int
+	// The checksums of the values of the ChecksumColumns of the check. They do not depend on the order
+	// of the rows, so that they only change when the data changes.
+	Checksums map[string]uint64
+	// The rows sampled uniformly at random from the result
+	Samples []*Record
+}
+
+// ResultProfileInterceptor returns an interceptor emitting the profile of the successful results
+// of the statements matching a check. emit is called from the executing goroutine.
+func ResultProfileInterceptor(emit func(ResultProfile), checks ...ResultCheck) Interceptor {
+	rnd := rand.New(rand.NewSource(time.Now().UnixNano()))
+	var mu sync.Mutex
+	return newResultProfileInterceptor(func(n int) int {
+		mu.Lock()
+		defer mu.Unlock()
+		return rnd.Intn(n)
+	}, emit, checks)
+}
+
+func newResultProfileInterceptor(randIntn func(int) int, emit func(ResultProfile), checks []ResultCheck) Interceptor {
+	byDigest := make(map[string]ResultCheck, len(checks))
+	for _, c := range checks {
+		byDigest[c.Digest] = c
+	}
+	return func(ctx context.Context, stmt string, params map[string]interface{}, invoker Invoker) (*ResultSet, error) {
+		resp, err := invoker(ctx, stmt, params)
+		if err != nil || !resp.IsSucceed() {
+			return resp, err
+		}
+		fingerprint := FingerprintStatement(stmt)
+		if check, ok := byDigest[fingerprint.Digest]; ok {
+			profile := profileResult(resp, check, randIntn)
+			profile.StatementFingerprint, profile.Tenant = fingerprint, TenantFrom(ctx)
+			emit(profile)
+		}
+		return resp, nil
+	}
+}
+
+// profileResult counts the rows of the result, checksums its columns and samples its rows with reservoir sampling
+func profileResult(resp *ResultSet, check ResultCheck, randIntn func(int) int) ResultProfile {
+	profile := ResultProfile{Rows: resp.GetRowSize()}
+	var columns []int
+	if len(check.ChecksumColumns) > 0 {
+		profile.Checksums = make(map[string]uint64, len(check.ChecksumColumns))
+		for _, name := range check.ChecksumColumns {
+			if i := indexOf(resp.GetColNames(), name); i >= 0 {
+				profile.Checksums[name] = 0
+				columns = append(columns, i)
+			}
+		}
+	}
+	for row := 0; row < profile.Rows; row++ {
+		record, err := resp.GetRowValuesByIndex(row)
+		if err != nil {
+			continue
+		}
+		for _, i := range columns {
+			value, err := record.GetValueByIndex(i)
+			if err != nil {
+				continue
+			}
+			h := fnv.New64a()
+			h.Write([]byte(value.String()))
+			// a sum does not depend on the order of the rows, unlike a hash of the whole column
+			profile.Checksums[resp.GetColNames()[i]] += h.Sum64()
+		}
+		if check.SampleRows > 0 {
+			if len(profile.Samples) < check.SampleRows {
+				profile.Samples = append(profile.Samples, record)
+			} else if j := randIntn(row + 1); j < check.SampleRows {
+				profile.Samples[j] = record
+			}
+		}
+	}
+	return profile
+}
+
+func indexOf(list []string, s string) int {
+	for i, item := range list {
+		if item == s {
+			return i
+		}
+	}
+	return -1
+}
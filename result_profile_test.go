@@ -0,0 +1,53 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResultProfileInterceptor(t *testing.T) {
+	stmt := "MATCH (v:player) WHERE v.player.age > 30 RETURN v.player.name AS name, v.player.age AS age"
+	var profiles []ResultProfile
+	interceptor := newResultProfileInterceptor(func(n int) int { return 0 }, func(p ResultProfile) {
+		profiles = append(profiles, p)
+	}, []ResultCheck{{Digest: FingerprintStatement(stmt).Digest, ChecksumColumns: []string{"name", "missing"}, SampleRows: 2}})
+
+	rows := [][]interface{}{{"Tim", 42}, {"Tony", 36}, {"Manu", 41}}
+	result := func(rows ...[]interface{}) Invoker {
+		return func(ctx context.Context, stmt string, params map[string]interface{}) (*ResultSet, error) {
+			return newTestResultSet(t, []string{"name", "age"}, rows...), nil
+		}
+	}
+	_, err := interceptor(context.Background(), stmt, nil, result(rows...))
+	assert.Nil(t, err)
+	_, err = interceptor(context.Background(), stmt, nil, result(rows[2], rows[1], rows[0]))
+	assert.Nil(t, err)
+	_, err = interceptor(context.Background(), "SHOW HOSTS", nil, result(rows...))
+	assert.Nil(t, err)
+
+	if assert.Len(t, profiles, 2) {
+		assert.Equal(t, 3, profiles[0].Rows)
+		assert.Len(t, profiles[0].Checksums, 1)
+		// the checksums do not depend on the order of the rows
+		assert.Equal(t, profiles[0].Checksums, profiles[1].Checksums)
+		if assert.Len(t, profiles[0].Samples, 2) {
+			// the third row replaced the first sample
+			name, _ := profiles[0].Samples[0].GetValueByColName("name")
+			assert.Equal(t, `"Manu"`, name.String())
+		}
+	}
+
+	_, err = interceptor(context.Background(), stmt, nil, result(rows[0], rows[1]))
+	assert.Nil(t, err)
+	assert.NotEqual(t, profiles[0].Checksums["name"], profiles[2].Checksums["name"])
+}
@@ -25,6 +25,13 @@ type ResultSet struct {
 	columnNames     []string
 	colNameIndexMap map[string]int
 	timezoneInfo    timezoneInfo
+	// measured by the session, see GetLatencyBreakdown
+	roundTrip time.Duration
+	decode    time.Duration
+	// the statements last executed by the session if the statement failed, see WithQueryHistory
+	history []string
+	// the size of the response accounted by the pool, see WithMemorySoftLimit
+	memory *trackedMemory
 }
 
 type Record struct {
@@ -91,6 +98,7 @@ const (
 	ErrorCode_E_BAD_PERMISSION        ErrorCode = ErrorCode(nebula.ErrorCode_E_BAD_PERMISSION)
 	ErrorCode_E_SEMANTIC_ERROR        ErrorCode = ErrorCode(nebula.ErrorCode_E_SEMANTIC_ERROR)
 	ErrorCode_E_PARTIAL_SUCCEEDED     ErrorCode = ErrorCode(nebula.ErrorCode_E_PARTIAL_SUCCEEDED)
+	ErrorCode_E_SPACE_NOT_FOUND       ErrorCode = ErrorCode(nebula.ErrorCode_E_SPACE_NOT_FOUND)
 )
 
 func genResultSet(resp *graph.ExecutionResponse, timezoneInfo timezoneInfo) (*ResultSet, error) {
@@ -387,6 +395,12 @@ func (res ResultSet) IsSucceed() bool {
 	return res.GetErrorCode() == ErrorCode_SUCCEEDED
 }
 
+// QueryHistory returns the redacted statements last executed by the session, from the oldest one
+// to this one, if the statement failed and WithQueryHistory is set in the pool config
+func (res ResultSet) QueryHistory() []string {
+	return res.history
+}
+
 func (res ResultSet) IsPartialSucceed() bool {
 	return res.GetErrorCode() == ErrorCode_E_PARTIAL_SUCCEEDED
 }
@@ -0,0 +1,73 @@
+//go:build go1.23
+// +build go1.23
+
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"iter"
+)
+
+// Rows returns an iterator over the records of the rows of the result set, e.g.
+//
+//	for record, err := range resultSet.Rows() {
+//		if err != nil {
+//			return err
+//		}
+//		...
+//	}
+//
+// The iteration stops after the first error.
+func (res ResultSet) Rows() iter.Seq2[*Record, error] {
+	return func(yield func(*Record, error) bool) {
+		for i := 0; i < res.GetRowSize(); i++ {
+			record, err := res.GetRowValuesByIndex(i)
+			if !yield(record, err) || err != nil {
+				return
+			}
+		}
+	}
+}
+
+// Nodes returns an iterator over the vertices of the column, see Rows
+func (res ResultSet) Nodes(colName string) iter.Seq2[*Node, error] {
+	return columnValues(res, colName, (*ValueWrapper).AsNode)
+}
+
+// Relationships returns an iterator over the edges of the column, see Rows
+func (res ResultSet) Relationships(colName string) iter.Seq2[*Relationship, error] {
+	return columnValues(res, colName, (*ValueWrapper).AsRelationship)
+}
+
+// Paths returns an iterator over the paths of the column, see Rows
+func (res ResultSet) Paths(colName string) iter.Seq2[*PathWrapper, error] {
+	return columnValues(res, colName, (*ValueWrapper).AsPath)
+}
+
+// columnValues returns an iterator over the values of the column converted by as
+func columnValues[T any](res ResultSet, colName string, as func(*ValueWrapper) (T, error)) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		for record, err := range res.Rows() {
+			var value *ValueWrapper
+			if err == nil {
+				value, err = record.GetValueByColName(colName)
+			}
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+			v, err := as(value)
+			if !yield(v, err) || err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,87 @@
+//go:build go1.23
+// +build go1.23
+
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vesoft-inc/nebula-go/v3/nebula"
+	"github.com/vesoft-inc/nebula-go/v3/nebula/graph"
+)
+
+func newIterTestResultSet(t *testing.T) *ResultSet {
+	dataset := &nebula.DataSet{ColumnNames: [][]byte{[]byte("v"), []byte("e")}}
+	for i := 0; i < 3; i++ {
+		dataset.Rows = append(dataset.Rows, &nebula.Row{Values: []*nebula.Value{
+			{VVal: getVertexInt(i, 1, 1)},
+			{EVal: getEdge("Tom", "Bob", 1)},
+		}})
+	}
+	resp := &graph.ExecutionResponse{ErrorCode: nebula.ErrorCode_SUCCEEDED, Data: dataset}
+	resultSet, err := genResultSet(resp, testTimezone)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resultSet
+}
+
+func TestResultSetRows(t *testing.T) {
+	resultSet := newIterTestResultSet(t)
+	var n int
+	for record, err := range resultSet.Rows() {
+		assert.Nil(t, err)
+		_, err = record.GetValueByColName("e")
+		assert.Nil(t, err)
+		n++
+	}
+	assert.Equal(t, 3, n)
+
+	n = 0
+	for range resultSet.Rows() {
+		if n++; n == 2 {
+			break
+		}
+	}
+	assert.Equal(t, 2, n)
+}
+
+func TestResultSetNodesAndRelationships(t *testing.T) {
+	resultSet := newIterTestResultSet(t)
+	var vids []int64
+	for node, err := range resultSet.Nodes("v") {
+		assert.Nil(t, err)
+		vid, _ := node.GetID().AsInt()
+		vids = append(vids, vid)
+	}
+	assert.Equal(t, []int64{0, 1, 2}, vids)
+
+	var n int
+	for rel, err := range resultSet.Relationships("e") {
+		assert.Nil(t, err)
+		src, _ := rel.GetSrcVertexID().AsString()
+		assert.Equal(t, "Tom", src)
+		n++
+	}
+	assert.Equal(t, 3, n)
+
+	var errs int
+	for node, err := range resultSet.Nodes("e") {
+		assert.Nil(t, node)
+		assert.NotNil(t, err)
+		errs++
+	}
+	assert.Equal(t, 1, errs)
+	for _, err := range resultSet.Nodes("missing") {
+		assert.NotNil(t, err)
+	}
+}
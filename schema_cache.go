@@ -0,0 +1,195 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SchemaProp is a property of a tag or an edge type as shown by DESCRIBE TAG and DESCRIBE EDGE
+type SchemaProp struct {
+	Name     string
+	Type     string
+	Nullable bool
+	// True if the property has a default value
+	HasDefault bool
+}
+
+// WithSchemaCache caches the schema introspection of GetVIDType, DescribeTag and DescribeEdge per space
+// for the ttl, so that hot paths like the ogm and the VID formatting do not issue DESCRIBE statements.
+// The cache is shared by the sessions of the pool and is invalidated by InvalidateSchema and ExecuteDDL.
+func WithSchemaCache(ttl time.Duration) PoolConfOption {
+	return func(conf *PoolConfig) {
+		conf.SchemaCacheTTL = ttl
+	}
+}
+
+type schemaCacheKey struct {
+	space string
+	// "vid type", "tag" or "edge"
+	kind string
+	name string
+}
+
+type schemaCacheEntry struct {
+	value   interface{}
+	expires time.Time
+}
+
+// schemaCache is the schema cache of a pool
+type schemaCache struct {
+	mu      sync.Mutex
+	entries map[schemaCacheKey]schemaCacheEntry
+}
+
+// cachedSchema returns the cached value of the key, loading it if it is missing or has expired.
+// Errors are not cached.
+func (pool *ConnectionPool) cachedSchema(key schemaCacheKey, load func() (interface{}, error)) (interface{}, error) {
+	if pool == nil || pool.conf.SchemaCacheTTL <= 0 {
+		return load()
+	}
+	c := &pool.schema
+	now := pool.conf.Clock.Now()
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok && now.Before(entry.expires) {
+		return entry.value, nil
+	}
+	value, err := load()
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	if c.entries == nil {
+		c.entries = make(map[schemaCacheKey]schemaCacheEntry)
+	}
+	c.entries[key] = schemaCacheEntry{value: value, expires: now.Add(pool.conf.SchemaCacheTTL)}
+	c.mu.Unlock()
+	return value, nil
+}
+
+// InvalidateSchema drops the cached schema of the space, or of every space if space is empty
+func (pool *ConnectionPool) InvalidateSchema(space string) {
+	c := &pool.schema
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key := range c.entries {
+		if space == "" || key.space == space {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// WarmSchema loads the vid type and the properties of every tag and edge type of the spaces into the schema cache,
+// so that the first requests after a start do not pay for the introspection
+func (pool *ConnectionPool) WarmSchema(ctx context.Context, spaces ...string) error {
+	return pool.WithSession(ctx, func(session *Session) error {
+		for _, space := range spaces {
+			if _, err := session.GetVIDType(space); err != nil {
+				return err
+			}
+			for _, kind := range []string{"tag", "edge"} {
+				names, err := session.showSchemaNames(ctx, space, kind)
+				if err != nil {
+					return err
+				}
+				for _, name := range names {
+					if _, err := session.describeSchema(ctx, space, kind, name); err != nil {
+						return err
+					}
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// DescribeTag returns the properties of the tag of the space. The session is switched to the space
+// when they are loaded, but not when they are served from the schema cache, see WithSchemaCache,
+// so the space of the session is unspecified after the call.
+func (session *Session) DescribeTag(ctx context.Context, space, tag string) ([]SchemaProp, error) {
+	return session.describeSchema(ctx, space, "tag", tag)
+}
+
+// DescribeEdge returns the properties of the edge type of the space. The session is switched to the space
+// when they are loaded, but not when they are served from the schema cache, see WithSchemaCache,
+// so the space of the session is unspecified after the call.
+func (session *Session) DescribeEdge(ctx context.Context, space, edge string) ([]SchemaProp, error) {
+	return session.describeSchema(ctx, space, "edge", edge)
+}
+
+func (session *Session) describeSchema(ctx context.Context, space, kind, name string) ([]SchemaProp, error) {
+	props, err := session.connPool.cachedSchema(schemaCacheKey{space: space, kind: kind, name: name}, func() (interface{}, error) {
+		return session.loadSchemaProps(ctx, space, kind, name)
+	})
+	if err != nil {
+		return nil, err
+	}
+	// the cached slice is shared
+	return append([]SchemaProp(nil), props.([]SchemaProp)...), nil
+}
+
+func (session *Session) loadSchemaProps(ctx context.Context, space, kind, name string) ([]SchemaProp, error) {
+	resp, err := session.executeAdmin(ctx, fmt.Sprintf("USE %s; DESCRIBE %s %s",
+		QuoteIdentifier(space), strings.ToUpper(kind), QuoteIdentifier(name)),
+		fmt.Sprintf("describe %s %s in space %s", kind, name, space))
+	if err != nil {
+		return nil, err
+	}
+	props := make([]SchemaProp, 0, resp.GetRowSize())
+	for i := 0; i < resp.GetRowSize(); i++ {
+		record, err := resp.GetRowValuesByIndex(i)
+		if err != nil {
+			return nil, err
+		}
+		var prop SchemaProp
+		var null string
+		r := recordReader{record: record}
+		r.string("Field", &prop.Name)
+		r.string("Type", &prop.Type)
+		r.string("Null", &null)
+		def := r.value("Default")
+		if r.err != nil {
+			return nil, fmt.Errorf("failed to describe %s %s: %s", kind, name, r.err.Error())
+		}
+		prop.Nullable = strings.EqualFold(null, "YES")
+		prop.HasDefault = def != nil
+		props = append(props, prop)
+	}
+	return props, nil
+}
+
+// showSchemaNames returns the names of the tags or the edge types of the space
+func (session *Session) showSchemaNames(ctx context.Context, space, kind string) ([]string, error) {
+	resp, err := session.executeAdmin(ctx, fmt.Sprintf("USE %s; SHOW %sS", QuoteIdentifier(space), strings.ToUpper(kind)),
+		fmt.Sprintf("show %ss of space %s", kind, space))
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, resp.GetRowSize())
+	for i := 0; i < resp.GetRowSize(); i++ {
+		record, err := resp.GetRowValuesByIndex(i)
+		if err != nil {
+			return nil, err
+		}
+		var name string
+		r := recordReader{record: record}
+		r.string("Name", &name)
+		if r.err != nil {
+			return nil, fmt.Errorf("failed to show %ss: %s", kind, r.err.Error())
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
@@ -0,0 +1,60 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchemaCache(t *testing.T) {
+	clock := NewManualClock(time.Now())
+	conf := NewPoolConf(WithSchemaCache(time.Minute))
+	conf.Clock = clock
+	pool := &ConnectionPool{conf: conf}
+	loads := 0
+	load := func() (interface{}, error) {
+		loads++
+		return VIDTypeInt64, nil
+	}
+	key := schemaCacheKey{space: "test", kind: "vid type"}
+
+	for i := 0; i < 2; i++ {
+		v, err := pool.cachedSchema(key, load)
+		assert.Nil(t, err)
+		assert.Equal(t, VIDTypeInt64, v)
+	}
+	assert.Equal(t, 1, loads)
+
+	clock.Advance(time.Minute)
+	_, _ = pool.cachedSchema(key, load)
+	assert.Equal(t, 2, loads)
+
+	pool.InvalidateSchema("other")
+	_, _ = pool.cachedSchema(key, load)
+	assert.Equal(t, 2, loads)
+	pool.InvalidateSchema("test")
+	_, _ = pool.cachedSchema(key, load)
+	assert.Equal(t, 3, loads)
+
+	// errors are not cached
+	other := schemaCacheKey{space: "other", kind: "tag", name: "player"}
+	_, err := pool.cachedSchema(other, func() (interface{}, error) { return nil, fmt.Errorf("failed") })
+	assert.NotNil(t, err)
+	_, err = pool.cachedSchema(other, load)
+	assert.Nil(t, err)
+	assert.Equal(t, 4, loads)
+
+	pool.conf.SchemaCacheTTL = 0
+	_, _ = pool.cachedSchema(key, load)
+	assert.Equal(t, 5, loads)
+}
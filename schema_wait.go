@@ -0,0 +1,206 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultSchemaPollInterval is the interval between the checks of WaitForSchema
+const DefaultSchemaPollInterval = 500 * time.Millisecond
+
+// SchemaKind is the kind of a schema object
+type SchemaKind int
+
+const (
+	SchemaTag SchemaKind = iota
+	SchemaEdge
+	SchemaTagIndex
+	SchemaEdgeIndex
+)
+
+func (k SchemaKind) String() string {
+	switch k {
+	case SchemaTag:
+		return "tag"
+	case SchemaEdge:
+		return "edge"
+	case SchemaTagIndex:
+		return "tag index"
+	case SchemaEdgeIndex:
+		return "edge index"
+	default:
+		return "unknown"
+	}
+}
+
+// SchemaWant is a schema object awaited by WaitForSchema
+type SchemaWant struct {
+	Kind SchemaKind
+	Name string
+}
+
+func (w SchemaWant) String() string {
+	return w.Kind.String() + " " + w.Name
+}
+
+// WantTag awaits a tag
+func WantTag(name string) SchemaWant {
+	return SchemaWant{Kind: SchemaTag, Name: name}
+}
+
+// WantEdge awaits an edge type
+func WantEdge(name string) SchemaWant {
+	return SchemaWant{Kind: SchemaEdge, Name: name}
+}
+
+// WantTagIndex awaits a tag index
+func WantTagIndex(name string) SchemaWant {
+	return SchemaWant{Kind: SchemaTagIndex, Name: name}
+}
+
+// WantEdgeIndex awaits an edge index
+func WantEdgeIndex(name string) SchemaWant {
+	return SchemaWant{Kind: SchemaEdgeIndex, Name: name}
+}
+
+// WaitForSchema waits until the space and the wanted schema objects can be used by the graph service
+// the session is connected to, polling every DefaultSchemaPollInterval until the context is done.
+// Schema changes are propagated to the graph services with the heartbeats of the meta service,
+// so a tag can be listed by SHOW TAGS while inserting into it still fails.
+// Tags and edge types are checked with a FETCH which goes through the schema cache of the graph service,
+// indexes are checked with SHOW TAG INDEXES and SHOW EDGE INDEXES. The session is switched to the space.
+func (session *Session) WaitForSchema(ctx context.Context, space string, wants ...SchemaWant) error {
+	if err := session.WaitForSpace(ctx, space, DefaultSchemaPollInterval); err != nil {
+		return err
+	}
+	vidType, err := session.GetVIDType(space)
+	if err != nil {
+		return err
+	}
+	vid := `""`
+	if vidType == VIDTypeInt64 {
+		vid = "0"
+	}
+
+	pending := wants
+	for {
+		var missing []SchemaWant
+		var indexes map[SchemaKind][]string
+		for _, want := range pending {
+			var visible bool
+			switch want.Kind {
+			case SchemaTag, SchemaEdge:
+				visible, err = session.schemaVisible(ctx, space, want, vid)
+			case SchemaTagIndex, SchemaEdgeIndex:
+				if indexes == nil {
+					indexes = make(map[SchemaKind][]string)
+				}
+				if _, ok := indexes[want.Kind]; !ok {
+					if indexes[want.Kind], err = session.showIndexes(ctx, space, want.Kind); err != nil {
+						return err
+					}
+				}
+				visible = containsString(indexes[want.Kind], want.Name)
+			default:
+				return fmt.Errorf("failed to wait for schema: unknown kind %d", want.Kind)
+			}
+			if err != nil {
+				return err
+			}
+			if !visible {
+				missing = append(missing, want)
+			}
+		}
+		if len(missing) == 0 {
+			return nil
+		}
+		pending = missing
+
+		timer := session.connPool.conf.Clock.NewTimer(DefaultSchemaPollInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("failed to wait for schema of space %s: %s, missing %s",
+				space, ctx.Err().Error(), formatWants(missing))
+		case <-timer.C():
+		}
+	}
+}
+
+// schemaVisible returns true if the tag or the edge type of the space can be used by the graph service
+func (session *Session) schemaVisible(ctx context.Context, space string, want SchemaWant, vid string) (bool, error) {
+	stmt := fmt.Sprintf("FETCH PROP ON %s %s YIELD vertex AS v", QuoteIdentifier(want.Name), vid)
+	if want.Kind == SchemaEdge {
+		stmt = fmt.Sprintf("FETCH PROP ON %s %s -> %s YIELD edge AS e", QuoteIdentifier(want.Name), vid, vid)
+	}
+	resp, err := session.ExecuteIn(ctx, space, stmt, nil)
+	if err != nil {
+		return false, err
+	}
+	if resp.IsSucceed() {
+		return true, nil
+	}
+	if isSchemaNotFound(resp.GetErrorMsg()) {
+		return false, nil
+	}
+	return false, fmt.Errorf("failed to check %s: %s", want, resp.GetErrorMsg())
+}
+
+func isSchemaNotFound(msg string) bool {
+	msg = strings.ToLower(msg)
+	return strings.Contains(msg, "notfound") || strings.Contains(msg, "not found") || strings.Contains(msg, "not exist")
+}
+
+// showIndexes returns the names of the tag or edge indexes of the space
+func (session *Session) showIndexes(ctx context.Context, space string, kind SchemaKind) ([]string, error) {
+	stmt := "SHOW TAG INDEXES"
+	if kind == SchemaEdgeIndex {
+		stmt = "SHOW EDGE INDEXES"
+	}
+	resp, err := session.executeAdmin(ctx, "USE "+QuoteIdentifier(space)+"; "+stmt, strings.ToLower(stmt))
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, resp.GetRowSize())
+	for i := 0; i < resp.GetRowSize(); i++ {
+		record, err := resp.GetRowValuesByIndex(i)
+		if err != nil {
+			return nil, err
+		}
+		var name string
+		r := recordReader{record: record}
+		r.string("Index Name", &name)
+		if r.err != nil {
+			return nil, fmt.Errorf("failed to %s: %s", strings.ToLower(stmt), r.err.Error())
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func formatWants(wants []SchemaWant) string {
+	names := make([]string, len(wants))
+	for i, w := range wants {
+		names[i] = w.String()
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,24 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchemaWants(t *testing.T) {
+	assert.Equal(t, "tag player, edge follow, tag index player_name, edge index follow_degree",
+		formatWants([]SchemaWant{WantTag("player"), WantEdge("follow"), WantTagIndex("player_name"), WantEdgeIndex("follow_degree")}))
+
+	assert.True(t, isSchemaNotFound("TagNotFound: Tag `player' not found"))
+	assert.True(t, isSchemaNotFound("EdgeNotFound: `follow'"))
+	assert.False(t, isSchemaNotFound("SyntaxError: syntax error near `PROP'"))
+}
@@ -0,0 +1,217 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SecretProvider fetches a secret, such as a password or PEM encoded key material. Secret managers like Vault,
+// AWS Secrets Manager or GCP Secret Manager are plugged in by implementing it with their clients, e.g.
+//
+//	nebula.SecretProviderFunc(func(ctx context.Context) ([]byte, error) {
+//		out, err := client.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{SecretId: aws.String("nebula")})
+//		if err != nil {
+//			return nil, err
+//		}
+//		return []byte(aws.ToString(out.SecretString)), nil
+//	})
+type SecretProvider interface {
+	GetSecret(ctx context.Context) ([]byte, error)
+}
+
+// SecretProviderFunc is a func implementing SecretProvider
+type SecretProviderFunc func(ctx context.Context) ([]byte, error)
+
+// GetSecret calls f
+func (f SecretProviderFunc) GetSecret(ctx context.Context) ([]byte, error) {
+	return f(ctx)
+}
+
+// EnvSecret returns a provider of the secret held by an environment variable
+func EnvSecret(name string) SecretProvider {
+	return SecretProviderFunc(func(ctx context.Context) ([]byte, error) {
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			return nil, fmt.Errorf("failed to get secret: environment variable %s is not set", name)
+		}
+		return []byte(value), nil
+	})
+}
+
+// FileSecret returns a provider of the secret held by a file, such as a mounted Kubernetes secret.
+// The file is read again on every call, so that rotated secrets are picked up.
+func FileSecret(path string) SecretProvider {
+	return SecretProviderFunc(func(ctx context.Context) ([]byte, error) {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get secret: %s", err.Error())
+		}
+		return data, nil
+	})
+}
+
+// CachedSecret caches the secret of a provider for a TTL. The secret is fetched again on the first call
+// after it has expired, and the expired secret is returned if the provider fails meanwhile,
+// so that a short outage of the secret manager does not prevent new sessions.
+type CachedSecret struct {
+	provider SecretProvider
+	ttl      time.Duration
+	// nil until the cached secret of an option is bound to the clock of its pool, see withClock
+	clock Clock
+
+	mu        sync.Mutex
+	secret    []byte
+	fetchedAt time.Time
+	fetched   bool
+}
+
+// NewCachedSecret returns a provider caching the secret of the provider for the ttl
+func NewCachedSecret(provider SecretProvider, ttl time.Duration) *CachedSecret {
+	return newCachedSecret(provider, ttl, realClock{})
+}
+
+func newCachedSecret(provider SecretProvider, ttl time.Duration, clock Clock) *CachedSecret {
+	return &CachedSecret{provider: provider, ttl: ttl, clock: clock}
+}
+
+// GetSecret returns the cached secret, fetching it if it has expired
+func (c *CachedSecret) GetSecret(ctx context.Context) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	clock := c.clock
+	if clock == nil {
+		clock = realClock{}
+	}
+	now := clock.Now()
+	if c.fetched && now.Sub(c.fetchedAt) < c.ttl {
+		return c.secret, nil
+	}
+	secret, err := c.provider.GetSecret(ctx)
+	if err != nil {
+		if c.fetched {
+			return c.secret, nil
+		}
+		return nil, err
+	}
+	c.secret, c.fetchedAt, c.fetched = secret, now, true
+	return secret, nil
+}
+
+// Invalidate drops the cached secret, e.g. after an authentication failure following a rotation
+func (c *CachedSecret) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.secret, c.fetched = nil, false
+}
+
+// WithPasswordProvider fetches the password of the sessions acquired without explicit credentials
+// from the provider, caching it for the ttl. It replaces the Password of the pool config.
+func WithPasswordProvider(provider SecretProvider, ttl time.Duration) PoolConfOption {
+	return func(conf *PoolConfig) {
+		conf.PasswordProvider = newCachedSecret(provider, ttl, nil)
+	}
+}
+
+// WithUsernameProvider fetches the username of the sessions acquired without explicit credentials
+// from the provider, caching it for the ttl. It replaces the Username of the pool config.
+func WithUsernameProvider(provider SecretProvider, ttl time.Duration) PoolConfOption {
+	return func(conf *PoolConfig) {
+		conf.UsernameProvider = newCachedSecret(provider, ttl, nil)
+	}
+}
+
+// withClock returns the provider of an option bound to the clock of the pool, the other providers are unchanged
+func withClock(provider SecretProvider, clock Clock) SecretProvider {
+	if c, ok := provider.(*CachedSecret); ok && c.clock == nil {
+		return newCachedSecret(c.provider, c.ttl, clock)
+	}
+	return provider
+}
+
+// invalidateCredentials drops the cached username and password of the pool,
+// it returns false if none is cached, so that fetching them again can not change them
+func (pool *ConnectionPool) invalidateCredentials() bool {
+	invalidated := false
+	for _, provider := range []SecretProvider{pool.conf.UsernameProvider, pool.conf.PasswordProvider} {
+		if c, ok := provider.(*CachedSecret); ok && c.ttl > 0 {
+			c.Invalidate()
+			invalidated = true
+		}
+	}
+	return invalidated
+}
+
+// password returns the password of the sessions acquired without explicit credentials
+func (pool *ConnectionPool) password(ctx context.Context) (string, error) {
+	return credential(ctx, "password", pool.conf.Password, pool.conf.PasswordProvider)
+}
+
+// username returns the username of the sessions acquired without explicit credentials
+func (pool *ConnectionPool) username(ctx context.Context) (string, error) {
+	return credential(ctx, "username", pool.conf.Username, pool.conf.UsernameProvider)
+}
+
+// credential returns the secret of the provider, or value if there is no provider
+func credential(ctx context.Context, name, value string, provider SecretProvider) (string, error) {
+	if provider == nil {
+		return value, nil
+	}
+	secret, err := provider.GetSecret(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get %s: %s", name, err.Error())
+	}
+	// files and secret managers often hold a trailing newline
+	return strings.TrimRight(string(secret), "\r\n"), nil
+}
+
+// hasUsername returns true if the pool config holds the credentials of the sessions acquired without explicit ones
+func (conf *PoolConfig) hasUsername() bool {
+	return conf.Username != "" || conf.UsernameProvider != nil
+}
+
+// ClientCertificateFromSecrets returns a func for tls.Config.GetClientCertificate loading the client certificate
+// and its private key from the PEM encoded secrets of the providers, caching them for the ttl.
+// The certificate can thus be rotated without restarting the pool.
+func ClientCertificateFromSecrets(cert, key SecretProvider, ttl time.Duration) func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	certs, keys := NewCachedSecret(cert, ttl), NewCachedSecret(key, ttl)
+	load := func() (*tls.Certificate, error) {
+		ctx := context.Background()
+		certPEM, err := certs.GetSecret(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get client certificate: %s", err.Error())
+		}
+		keyPEM, err := keys.GetSecret(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get client key: %s", err.Error())
+		}
+		pair, err := tls.X509KeyPair(certPEM, keyPEM)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %s", err.Error())
+		}
+		return &pair, nil
+	}
+	return func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+		pair, err := load()
+		if err != nil {
+			// the certificate and the key may have been cached on both sides of a rotation
+			certs.Invalidate()
+			keys.Invalidate()
+			pair, err = load()
+		}
+		return pair, err
+	}
+}
@@ -0,0 +1,206 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/facebook/fbthrift/thrift/lib/go/thrift"
+	"github.com/stretchr/testify/assert"
+	"github.com/vesoft-inc/nebula-go/v3/nebula"
+	"github.com/vesoft-inc/nebula-go/v3/nebula/graph"
+)
+
+func TestCachedSecret(t *testing.T) {
+	ctx := context.Background()
+	clock := NewManualClock(time.Now())
+	fetches := 0
+	var fail bool
+	provider := SecretProviderFunc(func(ctx context.Context) ([]byte, error) {
+		if fail {
+			return nil, fmt.Errorf("secret manager unavailable")
+		}
+		fetches++
+		return []byte(fmt.Sprintf("secret%d\n", fetches)), nil
+	})
+	c := newCachedSecret(provider, time.Minute, clock)
+	for i := 0; i < 2; i++ {
+		secret, err := c.GetSecret(ctx)
+		assert.Nil(t, err)
+		assert.Equal(t, "secret1\n", string(secret))
+	}
+
+	clock.Advance(time.Minute)
+	secret, _ := c.GetSecret(ctx)
+	assert.Equal(t, "secret2\n", string(secret))
+
+	// the expired secret is kept while the provider fails
+	fail = true
+	clock.Advance(time.Minute)
+	secret, err := c.GetSecret(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, "secret2\n", string(secret))
+	c.Invalidate()
+	_, err = c.GetSecret(ctx)
+	assert.NotNil(t, err)
+
+	fail = false
+	pool := &ConnectionPool{conf: NewPoolConf(WithPasswordProvider(provider, time.Minute))}
+	password, err := pool.password(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, "secret3", password)
+	assert.NotNil(t, NewPoolConf(WithCredentials("root", "nebula"), WithPasswordProvider(provider, time.Minute)).Validate())
+}
+
+func TestCachedSecretPoolClock(t *testing.T) {
+	ctx := context.Background()
+	clock := NewManualClock(time.Now())
+	fetches := 0
+	provider := SecretProviderFunc(func(ctx context.Context) ([]byte, error) {
+		fetches++
+		return []byte(fmt.Sprintf("secret%d", fetches)), nil
+	})
+	// the clock of the pool applies even if it is set after the provider
+	conf := NewPoolConf(WithPasswordProvider(provider, time.Minute), WithClock(clock))
+	conf.validateConf(DefaultLogger{})
+	pool := &ConnectionPool{conf: conf}
+	password, _ := pool.password(ctx)
+	assert.Equal(t, "secret1", password)
+	clock.Advance(30 * time.Second)
+	password, _ = pool.password(ctx)
+	assert.Equal(t, "secret1", password)
+	clock.Advance(30 * time.Second)
+	password, _ = pool.password(ctx)
+	assert.Equal(t, "secret2", password)
+}
+
+func TestSecretProviders(t *testing.T) {
+	ctx := context.Background()
+	os.Setenv("NEBULA_TEST_SECRET", "s3cret")
+	defer os.Unsetenv("NEBULA_TEST_SECRET")
+	secret, err := EnvSecret("NEBULA_TEST_SECRET").GetSecret(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, "s3cret", string(secret))
+	_, err = EnvSecret("NEBULA_TEST_MISSING").GetSecret(ctx)
+	assert.NotNil(t, err)
+
+	f, err := ioutil.TempFile("", "secret")
+	assert.Nil(t, err)
+	defer os.Remove(f.Name())
+	f.WriteString("from file")
+	f.Close()
+	secret, err = FileSecret(f.Name()).GetSecret(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, "from file", string(secret))
+	_, err = FileSecret(f.Name() + ".missing").GetSecret(ctx)
+	assert.NotNil(t, err)
+
+	getCert := ClientCertificateFromSecrets(EnvSecret("NEBULA_TEST_SECRET"), EnvSecret("NEBULA_TEST_SECRET"), time.Minute)
+	_, err = getCert(nil)
+	assert.NotNil(t, err)
+}
+
+func TestCredentialFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "secrets")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	userFile, passwordFile := dir+"/user", dir+"/password"
+	assert.Nil(t, ioutil.WriteFile(userFile, []byte("root\n"), 0600))
+	assert.Nil(t, ioutil.WriteFile(passwordFile, []byte("nebula\n"), 0600))
+
+	dsn, err := NewConnStringBuilder().
+		Hosts(HostAddress{Host: "127.0.0.1", Port: 9669}).
+		Credentials("ignored", "ignored").
+		UsernameFile(userFile).
+		PasswordFile(passwordFile).
+		LazyInit().
+		Build()
+	assert.Nil(t, err)
+	cfg, err := ParseConnectionString(dsn)
+	assert.Nil(t, err)
+	pool, err := cfg.NewPool(DefaultLogger{})
+	assert.Nil(t, err)
+	defer pool.Close()
+
+	ctx := context.Background()
+	username, err := pool.username(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, "root", username)
+	password, err := pool.password(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, "nebula", password)
+
+	// the rotated password is read by the next acquire
+	assert.Nil(t, ioutil.WriteFile(passwordFile, []byte("rotated\n"), 0600))
+	password, err = pool.password(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, "rotated", password)
+
+	// the last username is kept while the file is being replaced
+	assert.Nil(t, os.Remove(userFile))
+	username, err = pool.username(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, "root", username)
+}
+
+// newAuthTestConn returns a connection answering the ping of the pool, then the authentication with the code
+func newAuthTestConn(t *testing.T, code nebula.ErrorCode) *connection {
+	buf := thrift.NewMemoryBuffer()
+	proto := thrift.NewBinaryProtocolFactoryDefault().GetProtocol(buf)
+	replies := []struct {
+		method string
+		result thrift.WritableStruct
+	}{
+		{"executeWithParameter", &graph.GraphServiceExecuteWithParameterResult{Success: graph.NewExecutionResponse()}},
+		{"authenticate", &graph.GraphServiceAuthenticateResult{Success: &graph.AuthResponse{ErrorCode: code, ErrorMsg: []byte("Invalid password")}}},
+	}
+	for i, reply := range replies {
+		assert.Nil(t, proto.WriteMessageBegin(reply.method, thrift.REPLY, int32(i+1)))
+		assert.Nil(t, reply.result.Write(proto))
+		assert.Nil(t, proto.WriteMessageEnd())
+	}
+	conn := newConnectionWithClock(HostAddress{Host: "127.0.0.1", Port: 9669}, realClock{})
+	conn.graph = graph.NewGraphServiceClientFactory(buf, thrift.NewBinaryProtocolFactoryDefault())
+	return conn
+}
+
+func TestAcquireInvalidatesCachedCredentials(t *testing.T) {
+	fetches := 0
+	provider := SecretProviderFunc(func(ctx context.Context) ([]byte, error) {
+		fetches++
+		return []byte(fmt.Sprintf("secret%d", fetches)), nil
+	})
+	conf := NewPoolConf(WithCredentials("root", ""), WithPasswordProvider(provider, time.Hour))
+	conf.ServerVersion = "3.0.0"
+	conf.validateConf(DefaultLogger{})
+	pool := &ConnectionPool{conf: conf, log: DefaultLogger{}}
+	pool.idleConnectionQueue.PushBack(newAuthTestConn(t, nebula.ErrorCode_E_BAD_USERNAME_PASSWORD))
+	pool.idleConnectionQueue.PushBack(newAuthTestConn(t, nebula.ErrorCode_SUCCEEDED))
+
+	// the rotated password is fetched again after the cached one is rejected
+	session, err := pool.Acquire(context.Background())
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, "secret2", session.password)
+	assert.Equal(t, 2, fetches)
+
+	// the authentication is retried only once
+	pool.idleConnectionQueue.Init()
+	pool.idleConnectionQueue.PushBack(newAuthTestConn(t, nebula.ErrorCode_E_BAD_USERNAME_PASSWORD))
+	pool.idleConnectionQueue.PushBack(newAuthTestConn(t, nebula.ErrorCode_E_BAD_USERNAME_PASSWORD))
+	_, err = pool.Acquire(context.Background())
+	assert.EqualError(t, err, "fail to authenticate, error: Invalid password")
+	assert.Equal(t, 3, fetches)
+}
@@ -0,0 +1,124 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// The graph service flags limiting the number of sessions and connections of a client
+const (
+	maxSessionsPerIPPerUserFlag = "max_sessions_per_ip_per_user"
+	maxAllowedConnectionsFlag   = "max_allowed_connections"
+)
+
+// ServerLimits are the limits of the graph service which apply to a pool
+type ServerLimits struct {
+	// The max number of sessions of a user from an IP, 0 if unknown
+	MaxSessionsPerIPPerUser int64
+	// The max number of connections of the graph service, 0 if unknown
+	MaxAllowedConnections int64
+}
+
+// MaxPoolSize returns the lowest known limit, or 0 if no limit is known
+func (l ServerLimits) MaxPoolSize() int {
+	max := int64(0)
+	for _, limit := range []int64{l.MaxSessionsPerIPPerUser, l.MaxAllowedConnections} {
+		if limit > 0 && (max == 0 || limit < max) {
+			max = limit
+		}
+	}
+	return int(max)
+}
+
+// WithAdaptivePoolSize caps the max size of the pool to the limits of the graph service
+func WithAdaptivePoolSize() PoolConfOption {
+	return func(conf *PoolConfig) {
+		conf.AdaptivePoolSize = true
+	}
+}
+
+// ServerLimits returns the limits of the graph service from SHOW CONFIGS GRAPH,
+// using a session acquired with the credentials of the config of the pool.
+// Flags which are not registered in the meta service are reported as unknown.
+func (pool *ConnectionPool) ServerLimits(ctx context.Context) (ServerLimits, error) {
+	session, err := pool.Acquire(ctx)
+	if err != nil {
+		return ServerLimits{}, err
+	}
+	defer session.Release()
+	configs, err := session.ShowConfigs(ctx, ConfigGraph)
+	if err != nil {
+		return ServerLimits{}, err
+	}
+	return serverLimits(configs), nil
+}
+
+func serverLimits(configs []ServiceConfig) ServerLimits {
+	var limits ServerLimits
+	for _, c := range configs {
+		if c.Value == nil || !c.Value.IsInt() {
+			continue
+		}
+		v, _ := c.Value.AsInt()
+		switch c.Name {
+		case maxSessionsPerIPPerUserFlag:
+			limits.MaxSessionsPerIPPerUser = v
+		case maxAllowedConnectionsFlag:
+			limits.MaxAllowedConnections = v
+		}
+	}
+	return limits
+}
+
+// adaptPoolSize caps the max and min sizes of the pool to the limits of the graph service.
+// It must be called before the pool is used.
+func (pool *ConnectionPool) adaptPoolSize(ctx context.Context) {
+	if !pool.conf.hasUsername() {
+		pool.log.Warn("The pool size can not be adapted to the limits of the graph service: no credentials in the pool config")
+		return
+	}
+	limits, err := pool.ServerLimits(ctx)
+	if err != nil {
+		pool.log.Warn(fmt.Sprintf("The pool size can not be adapted to the limits of the graph service: %s", err.Error()))
+		return
+	}
+	max := limits.MaxPoolSize()
+	if max == 0 || pool.conf.MaxConnPoolSize <= max {
+		return
+	}
+	pool.log.Warn(fmt.Sprintf("MaxConnPoolSize %d exceeds the limits of the graph service, %d has been applied",
+		pool.conf.MaxConnPoolSize, max))
+	pool.capPoolSize(max)
+}
+
+// capPoolSize lowers the max and min sizes of the pool to max and closes the idle connections above it
+func (pool *ConnectionPool) capPoolSize(max int) {
+	pool.rwLock.Lock()
+	defer pool.rwLock.Unlock()
+	pool.conf.MaxConnPoolSize = max
+	if pool.conf.MinConnPoolSize > max {
+		pool.conf.MinConnPoolSize = max
+	}
+	// the connections opened for the min size of the config may exceed the new max size
+	for pool.idleConnectionQueue.Len() > 0 &&
+		pool.idleConnectionQueue.Len()+pool.activeConnectionQueue.Len() > max {
+		pool.idleConnectionQueue.Remove(pool.idleConnectionQueue.Front()).(*connection).close()
+	}
+}
+
+// adaptPoolSizeTimeout returns the deadline of the limits lookup, the socket timeout or 10 seconds if there is none
+func adaptPoolSizeTimeout(timeout time.Duration) time.Duration {
+	if timeout > 0 {
+		return timeout
+	}
+	return 10 * time.Second
+}
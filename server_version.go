@@ -0,0 +1,186 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ServerVersion is the version of a graph service
+type ServerVersion struct {
+	Major int
+	Minor int
+	Patch int
+	// The version as reported by the graph service, empty if the version is unknown
+	Raw string
+}
+
+// ParseServerVersion parses a version such as "3.1.0", "v3.1.0" or "3.2.0-nightly"
+func ParseServerVersion(s string) (ServerVersion, error) {
+	raw := strings.TrimSpace(s)
+	v := strings.TrimPrefix(strings.TrimPrefix(raw, "v"), "V")
+	if i := strings.IndexAny(v, "-+ "); i >= 0 {
+		v = v[:i]
+	}
+	parts := strings.Split(v, ".")
+	if len(parts) == 0 || len(parts) > 3 {
+		return ServerVersion{}, fmt.Errorf("invalid server version: %s", s)
+	}
+	var nums [3]int
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return ServerVersion{}, fmt.Errorf("invalid server version: %s", s)
+		}
+		nums[i] = n
+	}
+	return ServerVersion{Major: nums[0], Minor: nums[1], Patch: nums[2], Raw: raw}, nil
+}
+
+// IsKnown returns true if the version has been negotiated or configured
+func (v ServerVersion) IsKnown() bool {
+	return v.Raw != ""
+}
+
+// AtLeast returns true if the version is greater than or equal to major.minor.patch
+func (v ServerVersion) AtLeast(major, minor, patch int) bool {
+	if v.Major != major {
+		return v.Major > major
+	}
+	if v.Minor != minor {
+		return v.Minor > minor
+	}
+	return v.Patch >= patch
+}
+
+func (v ServerVersion) String() string {
+	if !v.IsKnown() {
+		return "unknown"
+	}
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// Capabilities are the optional features supported by a graph service
+type Capabilities struct {
+	// Statements can be executed with parameters
+	ParameterizedQueries bool
+	// Results can be returned as json
+	JsonResults bool
+}
+
+// Capabilities returns the optional features supported by the version.
+// All features are assumed to be supported if the version is unknown.
+func (v ServerVersion) Capabilities() Capabilities {
+	if !v.IsKnown() {
+		return Capabilities{ParameterizedQueries: true, JsonResults: true}
+	}
+	return Capabilities{
+		ParameterizedQueries: v.AtLeast(2, 6, 0),
+		JsonResults:          v.AtLeast(2, 5, 0),
+	}
+}
+
+// ServerVersion returns the version of the graph service the session is connected to.
+// The version is unknown unless the NegotiateServerVersion or ServerVersion option of the pool is set.
+func (session *Session) ServerVersion() ServerVersion {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	if session.connection == nil {
+		return ServerVersion{}
+	}
+	return session.connection.serverVersion
+}
+
+// Capabilities returns the optional features supported by the graph service the session is connected to
+func (session *Session) Capabilities() Capabilities {
+	return session.ServerVersion().Capabilities()
+}
+
+// negotiateServerVersion sets the server version of the connection of the session,
+// either from the pool config or from SHOW HOSTS GRAPH.
+func (session *Session) negotiateServerVersion(conf PoolConfig) error {
+	if session.connection.serverVersion.IsKnown() {
+		return nil
+	}
+	if conf.ServerVersion != "" {
+		version, err := ParseServerVersion(conf.ServerVersion)
+		if err != nil {
+			return err
+		}
+		session.connection.serverVersion = version
+		return nil
+	}
+	if !conf.NegotiateServerVersion {
+		return nil
+	}
+
+	resp, err := session.Execute("SHOW HOSTS GRAPH")
+	if err != nil {
+		return err
+	}
+	if !resp.IsSucceed() {
+		return fmt.Errorf("failed to show graph hosts: %s", resp.GetErrorMsg())
+	}
+	var lowest ServerVersion
+	for i := 0; i < resp.GetRowSize(); i++ {
+		record, err := resp.GetRowValuesByIndex(i)
+		if err != nil {
+			return err
+		}
+		version, err := recordServerVersion(record)
+		if err != nil {
+			// versions such as nightly builds can not be compared
+			continue
+		}
+		if recordIsHost(record, session.connection.severAddress) {
+			session.connection.serverVersion = version
+			return nil
+		}
+		if !lowest.IsKnown() || !version.AtLeast(lowest.Major, lowest.Minor, lowest.Patch) {
+			lowest = version
+		}
+	}
+	// The connected host was not found, the features are gated on the lowest version of the cluster
+	session.connection.serverVersion = lowest
+	return nil
+}
+
+func recordServerVersion(record *Record) (ServerVersion, error) {
+	val, err := record.GetValueByColName("Version")
+	if err != nil {
+		return ServerVersion{}, err
+	}
+	s, err := val.AsString()
+	if err != nil {
+		return ServerVersion{}, err
+	}
+	return ParseServerVersion(s)
+}
+
+func recordIsHost(record *Record, address HostAddress) bool {
+	hostVal, err := record.GetValueByColName("Host")
+	if err != nil {
+		return false
+	}
+	portVal, err := record.GetValueByColName("Port")
+	if err != nil {
+		return false
+	}
+	host, err := hostVal.AsString()
+	if err != nil {
+		return false
+	}
+	port, err := portVal.AsInt()
+	if err != nil {
+		return false
+	}
+	return host == address.Host && int(port) == address.Port
+}
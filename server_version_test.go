@@ -0,0 +1,44 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseServerVersion(t *testing.T) {
+	v, err := ParseServerVersion("v3.1.2")
+	assert.Nil(t, err)
+	assert.Equal(t, ServerVersion{3, 1, 2, "v3.1.2"}, v)
+	assert.Equal(t, "3.1.2", v.String())
+
+	v, err = ParseServerVersion("3.2-nightly")
+	assert.Nil(t, err)
+	assert.Equal(t, 3, v.Major)
+	assert.Equal(t, 2, v.Minor)
+
+	_, err = ParseServerVersion("nightly")
+	assert.NotNil(t, err)
+	assert.Equal(t, "unknown", ServerVersion{}.String())
+}
+
+func TestServerVersionCapabilities(t *testing.T) {
+	v, _ := ParseServerVersion("3.1.0")
+	assert.True(t, v.AtLeast(3, 0, 0))
+	assert.True(t, v.AtLeast(3, 1, 0))
+	assert.False(t, v.AtLeast(3, 1, 1))
+	assert.Equal(t, Capabilities{true, true}, v.Capabilities())
+
+	v, _ = ParseServerVersion("2.5.1")
+	assert.Equal(t, Capabilities{ParameterizedQueries: false, JsonResults: true}, v.Capabilities())
+
+	assert.Equal(t, Capabilities{true, true}, ServerVersion{}.Capabilities())
+}
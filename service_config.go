@@ -0,0 +1,125 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// ConfigComponent is a service of the cluster whose configs can be shown and updated
+type ConfigComponent string
+
+const (
+	ConfigGraph   ConfigComponent = "GRAPH"
+	ConfigMeta    ConfigComponent = "META"
+	ConfigStorage ConfigComponent = "STORAGE"
+	// ConfigAll shows the configs of every service
+	ConfigAll ConfigComponent = ""
+)
+
+// ServiceConfig is a config of a service, as shown by SHOW CONFIGS
+type ServiceConfig struct {
+	Component ConfigComponent
+	Name      string
+	Type      string
+	// MUTABLE configs can be updated at runtime
+	Mode  string
+	Value *ValueWrapper
+}
+
+// WithConfigUpdateAllowlist restricts the configs which can be changed with Session.UpdateConfig,
+// no config can be changed if no name is given
+func WithConfigUpdateAllowlist(names ...string) PoolConfOption {
+	return func(conf *PoolConfig) {
+		conf.ConfigUpdateAllowlist = append(append([]string{}, conf.ConfigUpdateAllowlist...), names...)
+	}
+}
+
+// ShowConfigs returns the configs of the component
+func (session *Session) ShowConfigs(ctx context.Context, component ConfigComponent) ([]ServiceConfig, error) {
+	stmt := strings.TrimSpace("SHOW CONFIGS " + string(component))
+	resp, err := session.executeAdmin(ctx, stmt, "show configs")
+	if err != nil {
+		return nil, err
+	}
+	return parseServiceConfigs(resp)
+}
+
+func parseServiceConfigs(resp *ResultSet) ([]ServiceConfig, error) {
+	configs := make([]ServiceConfig, 0, resp.GetRowSize())
+	for i := 0; i < resp.GetRowSize(); i++ {
+		record, err := resp.GetRowValuesByIndex(i)
+		if err != nil {
+			return nil, err
+		}
+		var c ServiceConfig
+		var component string
+		r := recordReader{record: record}
+		r.string("module", &component)
+		r.string("name", &c.Name)
+		r.string("type", &c.Type)
+		r.string("mode", &c.Mode)
+		if r.err == nil {
+			c.Value, r.err = record.GetValueByColName("value")
+		}
+		if r.err != nil {
+			return nil, fmt.Errorf("failed to show configs: %s", r.err.Error())
+		}
+		c.Component = ConfigComponent(strings.ToUpper(component))
+		configs = append(configs, c)
+	}
+	return configs, nil
+}
+
+// UpdateConfig changes a mutable config of the component at runtime.
+// The value can be a boolean, an integer, a float or a string.
+// The config must be in the ConfigUpdateAllowlist of the pool, if any.
+func (session *Session) UpdateConfig(ctx context.Context, component ConfigComponent, name string, value interface{}) error {
+	if !configAllowed(session.connPool.conf.ConfigUpdateAllowlist, name) {
+		return fmt.Errorf("failed to update config %s: not in the allowlist of the pool", name)
+	}
+	stmt, err := updateConfigStatement(component, name, value)
+	if err != nil {
+		return err
+	}
+	_, err = session.executeAdmin(ctx, stmt, "update config "+name)
+	return err
+}
+
+func updateConfigStatement(component ConfigComponent, name string, value interface{}) (string, error) {
+	if component == ConfigAll {
+		return "", fmt.Errorf("failed to update config %s: the component is required", name)
+	}
+	if name == "" || strings.TrimFunc(name, isConfigNameRune) != "" {
+		return "", fmt.Errorf("failed to update config %q: invalid name", name)
+	}
+	lit, err := FormatValue(value)
+	if err != nil {
+		return "", fmt.Errorf("failed to update config %s: %s", name, err.Error())
+	}
+	return fmt.Sprintf("UPDATE CONFIGS %s:%s = %s", strings.ToLower(string(component)), name, lit), nil
+}
+
+func configAllowed(allowlist []string, name string) bool {
+	if allowlist == nil {
+		return true
+	}
+	for _, allowed := range allowlist {
+		if allowed == name {
+			return true
+		}
+	}
+	return false
+}
+
+func isConfigNameRune(r rune) bool {
+	return r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9'
+}
@@ -9,7 +9,9 @@
 package nebula_go
 
 import (
+	"context"
 	"fmt"
+	"strings"
 	"sync"
 
 	"github.com/facebook/fbthrift/thrift/lib/go/thrift"
@@ -28,7 +30,15 @@ type Session struct {
 	connPool   *ConnectionPool
 	log        Logger
 	mu         sync.Mutex
+	charset    string
+	// the space of the session, as reported by the last statement
+	space string
 	timezoneInfo
+	// the credentials of the session, to authenticate it again, see WithSessionRepin
+	username, password string
+	repinning          int32
+	// the statements last executed, see WithQueryHistory
+	history queryHistory
 }
 
 func (session *Session) reconnectWithExecuteErr(err error) error {
@@ -49,25 +59,94 @@ func (session *Session) reconnectWithExecuteErr(err error) error {
 }
 
 func (session *Session) executeWithReconnect(f func() (interface{}, error)) (interface{}, error) {
-	resp, err := f()
+	resp, err := session.safeExecute(f)
 	if err == nil {
 		return resp, nil
 	}
+	if _, ok := err.(*PanicError); ok {
+		return nil, err
+	}
 	if err2 := session.reconnectWithExecuteErr(err); err2 != nil {
 		return nil, err2
 	}
 	// Execute with the new connetion
-	return f()
+	return session.safeExecute(f)
 
 }
 
 // ExecuteWithParameter returns the result of the given query as a ResultSet
-func (session *Session) ExecuteWithParameter(stmt string, params map[string]interface{}) (*ResultSet, error) {
+func (session *Session) ExecuteWithParameter(stmt string, params map[string]interface{}, opts ...ExecOption) (*ResultSet, error) {
+	return session.ExecuteWithContext(context.Background(), stmt, params, opts...)
+}
+
+// ExecuteWithContext returns the result of the given query as a ResultSet.
+// The statement goes through the interceptors of the pool. It returns once the context is done
+// even if the query is still running, in which case the session can only be used again after the query has finished.
+// The deadline of the context is not pushed down to the graph service: neither the graph protocol nor nGQL
+// provide a per query timeout, the server side execution time is bounded by the flags of the graph service.
+// If KillOnCancel is set in the pool config, the query is killed when the context is done.
+// The options tune this execution only, e.g. WithExecTimeout or WithExecRetries.
+func (session *Session) ExecuteWithContext(ctx context.Context, stmt string, params map[string]interface{},
+	opts ...ExecOption) (*ResultSet, error) {
+	o := newExecOptions(opts)
+	ctx, cancel := o.withMeta(session.connPool.withTenant(ctx))
+	defer cancel()
+	invoker := chainInterceptors(session.connPool.conf.Interceptors,
+		func(ctx context.Context, stmt string, params map[string]interface{}) (*ResultSet, error) {
+			if err := session.checkExplicitSpace(ctx, stmt); err != nil {
+				return nil, err
+			}
+			// the histograms are labelled by the statement of the caller, not by the corrected one
+			query, begin, tenant := stmt, session.connPool.conf.Clock.Now(), TenantFrom(ctx)
+			stmt = session.correctSpace(ctx, stmt)
+			started := ctx.Err() == nil
+			run := func() (interface{}, error) {
+				return runWithContext(ctx, func() (interface{}, error) {
+					return session.executeWithParameter(tenant, stmt, params)
+				})
+			}
+			resp, err := run()
+			if session.shouldRepin(ctx, resp, err) {
+				resp, err = session.repinAndReplay(ctx, query, resp, err, run)
+			}
+			if max := session.connPool.conf.QueryHistory; max > 0 {
+				session.history.record(max, query)
+			}
+			if max := session.connPool.conf.LatencyHistograms; max > 0 && err == nil {
+				session.connPool.latencyHistograms.record(max, tenant, query, session.connPool.conf.Clock.Now().Sub(begin))
+			}
+			if ctx.Err() == nil {
+				session.connPool.acquireStats.recordOutcome(session.connPool.Clock().Now(), err != nil)
+			}
+			if err != nil {
+				if started && ctx.Err() != nil {
+					session.killOnCancel(tenant)
+				}
+				return nil, err
+			}
+			return resp.(*ResultSet), nil
+		})
+	return session.withHistory(o.retry(ctx, session.connPool.conf.Clock, session.connPool.randIntn, func() (*ResultSet, error) {
+		// a retry may run on another connection after a reconnect
+		if o.host != nil {
+			if err := session.checkHost(*o.host); err != nil {
+				return nil, err
+			}
+		}
+		return invoker(ctx, stmt, params)
+	}))
+}
+
+func (session *Session) executeWithParameter(tenant, stmt string, params map[string]interface{}) (*ResultSet, error) {
 	session.mu.Lock()
 	defer session.mu.Unlock()
 	if session.connection == nil {
 		return nil, fmt.Errorf("failed to execute: Session has been released")
 	}
+	if len(params) > 0 && !session.connection.serverVersion.Capabilities().ParameterizedQueries {
+		return nil, fmt.Errorf("failed to execute: parameterized queries are not supported by the graph service %s",
+			session.connection.serverVersion)
+	}
 	paramsMap := make(map[string]*nebula.Value)
 	for k, v := range params {
 		nv, er := value2Nvalue(v)
@@ -76,29 +155,61 @@ func (session *Session) ExecuteWithParameter(stmt string, params map[string]inte
 		}
 		paramsMap[k] = nv
 	}
+	query := stmt
+	stmt = session.connPool.stmtPrefix + tenantComment(tenant) + stmt
+	memory := &session.connPool.memory
+	if err := memory.admit(session.connPool.conf.MemorySoftLimit); err != nil {
+		return nil, err
+	}
 	execFunc := func() (interface{}, error) {
+		var before int64
+		if session.connection.counter != nil {
+			before = session.connection.counter.readBytes()
+		}
+		clock := session.connPool.conf.Clock
+		sent := clock.Now()
 		resp, err := session.connection.executeWithParameter(session.sessionID, stmt, paramsMap)
 		if err != nil {
 			return nil, err
 		}
+		received := clock.Now()
+		var size int64
+		if session.connection.counter != nil {
+			size = session.connection.counter.readBytes() - before
+		}
+		memory.startDecode(size)
 		resSet, err := genResultSet(resp, session.timezoneInfo)
+		memory.endDecode(size)
 		if err != nil {
 			return nil, err
 		}
+		resSet.roundTrip, resSet.decode = received.Sub(sent), clock.Now().Sub(received)
+		memory.track(resSet, size)
+		if resSet.IsSucceed() {
+			session.space = resSet.GetSpaceName()
+		}
 		return resSet, nil
 	}
 
 	resp, err := session.executeWithReconnect(execFunc)
+	var host HostAddress
+	if session.connection != nil {
+		host = session.connection.severAddress
+	}
 	if err != nil {
+		session.connPool.recordError(host, tenant, query, nil)
 		return nil, err
 	}
+	if resSet := resp.(*ResultSet); !resSet.IsSucceed() {
+		session.connPool.recordError(host, tenant, query, resSet)
+	}
 	return resp.(*ResultSet), err
 
 }
 
 // Execute returns the result of the given query as a ResultSet
-func (session *Session) Execute(stmt string) (*ResultSet, error) {
-	return session.ExecuteWithParameter(stmt, map[string]interface{}{})
+func (session *Session) Execute(stmt string, opts ...ExecOption) (*ResultSet, error) {
+	return session.ExecuteWithParameter(stmt, map[string]interface{}{}, opts...)
 }
 
 // ExecuteJson returns the result of the given query as a json string
@@ -222,11 +333,22 @@ func (session *Session) ExecuteJson(stmt string) ([]byte, error) {
 //     ]
 // }
 func (session *Session) ExecuteJsonWithParameter(stmt string, params map[string]interface{}) ([]byte, error) {
+	return session.executeJsonWithParameter("", stmt, params)
+}
+
+func (session *Session) executeJsonWithParameter(tenant, stmt string, params map[string]interface{}) ([]byte, error) {
 	session.mu.Lock()
 	defer session.mu.Unlock()
 	if session.connection == nil {
 		return nil, fmt.Errorf("failed to execute: Session has been released")
 	}
+	if !session.connection.serverVersion.Capabilities().JsonResults {
+		return nil, fmt.Errorf("failed to execute: json results are not supported by the graph service %s",
+			session.connection.serverVersion)
+	}
+	if err := session.connPool.memory.admit(session.connPool.conf.MemorySoftLimit); err != nil {
+		return nil, err
+	}
 
 	paramsMap := make(map[string]*nebula.Value)
 	for k, v := range params {
@@ -236,6 +358,7 @@ func (session *Session) ExecuteJsonWithParameter(stmt string, params map[string]
 		}
 		paramsMap[k] = nv
 	}
+	stmt = session.connPool.stmtPrefix + tenantComment(tenant) + stmt
 	execFunc := func() (interface{}, error) {
 		resp, err := session.connection.ExecuteJsonWithParameter(session.sessionID, stmt, paramsMap)
 		if err != nil {
@@ -256,6 +379,11 @@ func (session *Session) reConnect() error {
 		err = fmt.Errorf(err.Error())
 		return err
 	}
+	// the new connection counts in the workload partition of the old one
+	session.connPool.rwLock.Lock()
+	session.connPool.assignWorkloadLocked(newconnection, session.connection.workload)
+	session.connPool.bindSessionLocked(newconnection, session.sessionID)
+	session.connPool.rwLock.Unlock()
 
 	// Release connection to pool
 	session.connPool.release(session.connection)
@@ -280,14 +408,113 @@ func (session *Session) Release() {
 		session.log.Warn(fmt.Sprintf("Sign out failed, %s", err.Error()))
 	}
 	// Release connection to pool
+	host := session.connection.severAddress
 	session.connPool.release(session.connection)
 	session.connection = nil
+	session.connPool.sessionEvent(SessionEvent{Kind: SessionReleased, SessionID: session.sessionID, Host: host})
 }
 
 func (session *Session) GetSessionID() int64 {
 	return session.sessionID
 }
 
+// GetTimezoneName returns the name of the timezone used to format time and datetime values
+func (session *Session) GetTimezoneName() string {
+	return string(session.timezoneInfo.name)
+}
+
+// GetTimezoneOffset returns the offset in seconds of the timezone used to format time and datetime values
+func (session *Session) GetTimezoneOffset() int32 {
+	return session.timezoneInfo.offset
+}
+
+// GetCharset returns the charset negotiated when the session was acquired.
+// It returns an empty string if no charset was configured in the pool.
+func (session *Session) GetCharset() string {
+	return session.charset
+}
+
+// onAcquire applies the session settings of the pool config to a newly acquired session
+func (session *Session) onAcquire(conf PoolConfig) error {
+	if err := session.negotiateServerVersion(conf); err != nil {
+		return err
+	}
+	if conf.TimeZone != "" {
+		location, err := loadTimeZone(conf.TimeZone)
+		if err != nil {
+			return err
+		}
+		_, offset := conf.Clock.Now().In(location).Zone()
+		session.timezoneInfo = timezoneInfo{int32(offset), []byte(location.String())}
+	}
+	if conf.Charset != "" {
+		if err := session.negotiateCharset(conf.Charset); err != nil {
+			return err
+		}
+	}
+	if err := session.runOnConnectStmts(conf.OnConnectStmts); err != nil {
+		return err
+	}
+	if conf.Space != "" && !conf.ExplicitSpace {
+		resp, err := session.Execute("USE " + QuoteIdentifier(conf.Space))
+		if err != nil {
+			return err
+		}
+		if !resp.IsSucceed() {
+			return fmt.Errorf("failed to use space %s: %s", conf.Space, resp.GetErrorMsg())
+		}
+	}
+	return nil
+}
+
+// runOnConnectStmts executes the on-connect statements if the connection has not executed them yet.
+// They are executed again by the next session if one of them fails.
+func (session *Session) runOnConnectStmts(stmts []string) error {
+	if session.connection.onConnectDone {
+		return nil
+	}
+	for _, stmt := range stmts {
+		resp, err := session.Execute(stmt)
+		if err != nil {
+			return err
+		}
+		if !resp.IsSucceed() {
+			return fmt.Errorf("failed to execute on-connect statement %s: %s", stmt, resp.GetErrorMsg())
+		}
+	}
+	session.connection.onConnectDone = true
+	return nil
+}
+
+// negotiateCharset checks that the given charset is supported by the graph service
+func (session *Session) negotiateCharset(charset string) error {
+	resp, err := session.Execute("SHOW CHARSET")
+	if err != nil {
+		return err
+	}
+	if !resp.IsSucceed() {
+		return fmt.Errorf("failed to show charset: %s", resp.GetErrorMsg())
+	}
+	charsets, err := resp.GetValuesByColName("Charset")
+	if err != nil {
+		return err
+	}
+	var supported []string
+	for _, val := range charsets {
+		name, err := val.AsString()
+		if err != nil {
+			return err
+		}
+		if strings.EqualFold(name, charset) {
+			session.charset = name
+			return nil
+		}
+		supported = append(supported, name)
+	}
+	return fmt.Errorf("charset %s is not supported by the graph service, supported charsets: %s",
+		charset, strings.Join(supported, ", "))
+}
+
 func IsError(resp *graph.ExecutionResponse) bool {
 	return resp.GetErrorCode() != nebula.ErrorCode_SUCCEEDED
 }
@@ -374,6 +601,11 @@ func value2Nvalue(any interface{}) (value *nebula.Value, err error) {
 		value.SetTVal(&v)
 	} else if v, ok := any.(nebula.Geography); ok {
 		value.SetGgVal(&v)
+	} else if encoded, ok, er := encodeValue(any); ok {
+		if er != nil {
+			return nil, er
+		}
+		value, err = value2Nvalue(encoded)
 	} else {
 		// unsupport other Value type, use this function carefully
 		err = fmt.Errorf("Only support convert boolean/float/int/string/map/list to nebula.Value but %T", any)
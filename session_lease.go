@@ -0,0 +1,230 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// the statement keeping a leased session alive
+const leasePingStmt = "YIELD 1"
+
+// leasedSession is the part of a Session used by a SessionLease
+type leasedSession interface {
+	ExecuteWithContext(ctx context.Context, stmt string, params map[string]interface{}, opts ...ExecOption) (*ResultSet, error)
+	Release()
+}
+
+// SessionLease is a session kept alive by the driver, see LeaseSession
+type SessionLease struct {
+	acquire func(ctx context.Context) (leasedSession, error)
+	clock   Clock
+	ttl     time.Duration
+	log     Logger
+
+	mu       sync.Mutex
+	session  leasedSession
+	space    string
+	lastUsed time.Time
+	renewals int
+	released bool
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+// LeaseSession acquires a session with the credentials of the pool config and keeps it alive
+// until the lease is released or the context is done, e.g. for an interactive console.
+// ttl is the idle timeout of the sessions of the graph service, session_idle_timeout_secs:
+// the session is pinged once it has been idle for half of ttl, and it is renewed with a new session
+// switched to the same space when the graph service has expired it anyway.
+func (pool *ConnectionPool) LeaseSession(ctx context.Context, ttl time.Duration) (*SessionLease, error) {
+	return newSessionLease(ctx, ttl, pool.conf.Clock, pool.log, func(ctx context.Context) (leasedSession, error) {
+		return pool.Acquire(ctx)
+	})
+}
+
+func newSessionLease(ctx context.Context, ttl time.Duration, clock Clock, log Logger,
+	acquire func(ctx context.Context) (leasedSession, error)) (*SessionLease, error) {
+	if ttl <= 0 {
+		return nil, fmt.Errorf("failed to lease session: invalid ttl %s", ttl)
+	}
+	session, err := acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lease session: %s", err.Error())
+	}
+	l := &SessionLease{
+		acquire:  acquire,
+		clock:    clock,
+		ttl:      ttl,
+		log:      log,
+		session:  session,
+		lastUsed: clock.Now(),
+		done:     make(chan struct{}),
+	}
+	// the timer is created before the goroutine, so that it follows the clock from the acquire
+	timer := clock.NewTimer(ttl / 2)
+	l.wg.Add(1)
+	go l.keepAlive(ctx, timer)
+	return l, nil
+}
+
+// ExecuteWithContext executes the statement with the leased session.
+// If the graph service has expired the session, it is renewed and the statement executed again.
+func (l *SessionLease) ExecuteWithContext(ctx context.Context, stmt string, params map[string]interface{},
+	opts ...ExecOption) (*ResultSet, error) {
+	session, err := l.current()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := session.ExecuteWithContext(ctx, stmt, params, opts...)
+	if err == nil && isSessionExpired(resp) {
+		if session, err = l.renew(ctx, session); err != nil {
+			return nil, err
+		}
+		resp, err = session.ExecuteWithContext(ctx, stmt, params, opts...)
+	}
+	l.used(resp)
+	return resp, err
+}
+
+// Execute executes the statement with the leased session, see ExecuteWithContext
+func (l *SessionLease) Execute(stmt string, opts ...ExecOption) (*ResultSet, error) {
+	return l.ExecuteWithContext(context.Background(), stmt, nil, opts...)
+}
+
+// Renewals returns the number of times the session was renewed after being expired by the graph service
+func (l *SessionLease) Renewals() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.renewals
+}
+
+// Release stops keeping the session alive and releases it
+func (l *SessionLease) Release() {
+	l.mu.Lock()
+	if l.released {
+		l.mu.Unlock()
+		return
+	}
+	l.released = true
+	close(l.done)
+	l.mu.Unlock()
+	l.wg.Wait()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.session != nil {
+		l.session.Release()
+		l.session = nil
+	}
+}
+
+func (l *SessionLease) current() (leasedSession, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.released {
+		return nil, fmt.Errorf("failed to execute: the session lease has been released")
+	}
+	return l.session, nil
+}
+
+// used records the use of the session and the space it was switched to
+func (l *SessionLease) used(resp *ResultSet) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.lastUsed = l.clock.Now()
+	if resp != nil && resp.IsSucceed() && resp.GetSpaceName() != "" {
+		l.space = resp.GetSpaceName()
+	}
+}
+
+// renew replaces the expired session by a new session switched to the space of the expired one
+func (l *SessionLease) renew(ctx context.Context, expired leasedSession) (leasedSession, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.released {
+		return nil, fmt.Errorf("failed to renew session: the session lease has been released")
+	}
+	if l.session != expired {
+		// renewed concurrently
+		return l.session, nil
+	}
+	session, err := l.acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to renew session: %s", err.Error())
+	}
+	if l.space != "" {
+		resp, err := session.ExecuteWithContext(ctx, "USE "+QuoteIdentifier(l.space), nil)
+		if err == nil && !resp.IsSucceed() {
+			err = fmt.Errorf("error code %d: %s", resp.GetErrorCode(), resp.GetErrorMsg())
+		}
+		if err != nil {
+			session.Release()
+			return nil, fmt.Errorf("failed to renew session: failed to use space %s: %s", l.space, err.Error())
+		}
+	}
+	expired.Release()
+	l.session = session
+	l.renewals++
+	return session, nil
+}
+
+// keepAlive pings the session when it has been idle for half of the ttl, until the lease is released
+// or the context is done
+func (l *SessionLease) keepAlive(ctx context.Context, timer Timer) {
+	defer l.wg.Done()
+	defer timer.Stop()
+	interval := l.ttl / 2
+	for {
+		select {
+		case <-l.done:
+			return
+		case <-ctx.Done():
+			go l.Release()
+			return
+		case <-timer.C():
+		}
+
+		l.mu.Lock()
+		session, idle := l.session, l.clock.Now().Sub(l.lastUsed)
+		l.mu.Unlock()
+		next := interval - idle
+		if idle >= interval {
+			if err := l.ping(ctx, session); err != nil {
+				l.log.Warn(fmt.Sprintf("Failed to keep the leased session alive: %s", err.Error()))
+			}
+			next = interval
+		}
+		timer.Reset(next)
+	}
+}
+
+func (l *SessionLease) ping(ctx context.Context, session leasedSession) error {
+	ctx, cancel := context.WithTimeout(ctx, l.ttl/2)
+	defer cancel()
+	resp, err := session.ExecuteWithContext(ctx, leasePingStmt, nil)
+	if err != nil {
+		return err
+	}
+	if isSessionExpired(resp) {
+		_, err = l.renew(ctx, session)
+		return err
+	}
+	l.used(nil)
+	return nil
+}
+
+// isSessionExpired returns true if the graph service no longer knows the session
+func isSessionExpired(resp *ResultSet) bool {
+	return !resp.IsSucceed() && ClassifyErrorCode(resp.GetErrorCode()) == ErrorClassSession
+}
@@ -0,0 +1,133 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vesoft-inc/nebula-go/v3/nebula"
+)
+
+type fakeLeasedSession struct {
+	t        *testing.T
+	mu       sync.Mutex
+	stmts    []string
+	executed chan string
+	expired  bool
+	released bool
+	// the error message of the USE statements, which fail if it is set
+	useError string
+}
+
+func (s *fakeLeasedSession) ExecuteWithContext(ctx context.Context, stmt string, params map[string]interface{},
+	opts ...ExecOption) (*ResultSet, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stmts = append(s.stmts, stmt)
+	resp := newTestResultSet(s.t, nil)
+	if s.expired {
+		resp.resp.ErrorCode = nebula.ErrorCode_E_SESSION_INVALID
+	} else if s.useError != "" && strings.HasPrefix(stmt, "USE ") {
+		resp.resp.ErrorCode = nebula.ErrorCode_E_SEMANTIC_ERROR
+		resp.resp.ErrorMsg = []byte(s.useError)
+	} else {
+		resp.resp.SpaceName = []byte("test")
+	}
+	select {
+	case s.executed <- stmt:
+	default:
+	}
+	return resp, nil
+}
+
+func (s *fakeLeasedSession) Release() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.released = true
+}
+
+func TestSessionLease(t *testing.T) {
+	clock := NewManualClock(time.Now())
+	var sessions []*fakeLeasedSession
+	acquire := func(ctx context.Context) (leasedSession, error) {
+		s := &fakeLeasedSession{t: t, executed: make(chan string, 1)}
+		sessions = append(sessions, s)
+		return s, nil
+	}
+	_, err := newSessionLease(context.Background(), 0, clock, DefaultLogger{}, acquire)
+	assert.NotNil(t, err)
+
+	lease, err := newSessionLease(context.Background(), 10*time.Second, clock, DefaultLogger{}, acquire)
+	assert.Nil(t, err)
+	_, err = lease.Execute("USE test")
+	assert.Nil(t, err)
+	<-sessions[0].executed
+
+	// pinged once idle for half of the ttl
+	clock.Advance(5 * time.Second)
+	assert.Equal(t, leasePingStmt, <-sessions[0].executed)
+
+	sessions[0].mu.Lock()
+	sessions[0].expired = true
+	sessions[0].mu.Unlock()
+	resp, err := lease.Execute("MATCH (v) RETURN v LIMIT 1")
+	assert.Nil(t, err)
+	assert.True(t, resp.IsSucceed())
+	assert.Equal(t, 1, lease.Renewals())
+	assert.Len(t, sessions, 2)
+	assert.True(t, sessions[0].released)
+	assert.Equal(t, []string{"USE " + QuoteIdentifier("test"), "MATCH (v) RETURN v LIMIT 1"}, sessions[1].stmts)
+
+	lease.Release()
+	assert.True(t, sessions[1].released)
+	_, err = lease.Execute("YIELD 1")
+	assert.NotNil(t, err)
+}
+
+func TestSessionLeaseContextDone(t *testing.T) {
+	session := &fakeLeasedSession{t: t, executed: make(chan string, 1)}
+	ctx, cancel := context.WithCancel(context.Background())
+	lease, err := newSessionLease(ctx, time.Minute, NewManualClock(time.Now()), DefaultLogger{},
+		func(ctx context.Context) (leasedSession, error) { return session, nil })
+	assert.Nil(t, err)
+	cancel()
+	lease.wg.Wait()
+	assert.Eventually(t, func() bool {
+		session.mu.Lock()
+		defer session.mu.Unlock()
+		return session.released
+	}, time.Second, time.Millisecond)
+}
+
+func TestSessionLeaseRenewUseError(t *testing.T) {
+	var sessions []*fakeLeasedSession
+	lease, err := newSessionLease(context.Background(), time.Minute, NewManualClock(time.Now()), DefaultLogger{},
+		func(ctx context.Context) (leasedSession, error) {
+			s := &fakeLeasedSession{t: t, executed: make(chan string, 1), useError: "SpaceNotFound: 100%"}
+			sessions = append(sessions, s)
+			return s, nil
+		})
+	assert.Nil(t, err)
+	defer lease.Release()
+	lease.mu.Lock()
+	lease.space = "test"
+	expired := lease.session
+	lease.mu.Unlock()
+
+	_, err = lease.renew(context.Background(), expired)
+	assert.EqualError(t, err, fmt.Sprintf("failed to renew session: failed to use space test: error code %d: SpaceNotFound: 100%%",
+		nebula.ErrorCode_E_SEMANTIC_ERROR))
+	assert.True(t, sessions[1].released)
+}
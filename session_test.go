@@ -9,6 +9,8 @@
 package nebula_go
 
 import (
+	"context"
+	"fmt"
 	"testing"
 	"time"
 )
@@ -48,3 +50,219 @@ func TestSession_Execute(t *testing.T) {
 	}()
 	time.Sleep(300 * time.Millisecond)
 }
+
+func TestSession_TimezoneAndCharset(t *testing.T) {
+	config := GetDefaultConf()
+	config.TimeZone = "Asia/Shanghai"
+	config.Charset = "utf8"
+	host := HostAddress{address, port}
+	pool, err := NewConnectionPool([]HostAddress{host}, config, DefaultLogger{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+
+	sess, err := pool.GetSession("root", "nebula")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sess.Release()
+	if sess.GetTimezoneName() != "Asia/Shanghai" || sess.GetTimezoneOffset() != 8*3600 {
+		t.Fatalf("unexpected timezone: %s, %d", sess.GetTimezoneName(), sess.GetTimezoneOffset())
+	}
+	if sess.GetCharset() != "utf8" {
+		t.Fatalf("unexpected charset: %s", sess.GetCharset())
+	}
+
+	config.Charset = "latin1"
+	pool2, err := NewConnectionPool([]HostAddress{host}, config, DefaultLogger{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool2.Close()
+	if _, err = pool2.GetSession("root", "nebula"); err == nil {
+		t.Fatal("expected unsupported charset to fail the session")
+	}
+}
+
+func TestSession_WaitForSchema(t *testing.T) {
+	config := GetDefaultConf()
+	config.Username, config.Password = "root", "nebula"
+	host := HostAddress{address, port}
+	pool, err := NewConnectionPool([]HostAddress{host}, config, DefaultLogger{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+	sess, err := pool.Acquire(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sess.Release()
+
+	if _, err = sess.ExecuteDDL(ctx, "CREATE SPACE IF NOT EXISTS test_wait_schema(vid_type = FIXED_STRING(30))"); err != nil {
+		t.Fatal(err)
+	}
+	defer dropSpace(t, sess, "test_wait_schema")
+	if err = sess.WaitForSpace(ctx, "test_wait_schema", DefaultSchemaPollInterval); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = sess.ExecuteDDL(ctx, "CREATE TAG IF NOT EXISTS person(name string); "+
+		"CREATE EDGE IF NOT EXISTS like(likeness double); CREATE TAG INDEX IF NOT EXISTS person_name ON person(name(10))"); err != nil {
+		t.Fatal(err)
+	}
+	err = sess.WaitForSchema(ctx, "test_wait_schema", WantTag("person"), WantEdge("like"), WantTagIndex("person_name"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := sess.Execute(`INSERT VERTEX person(name) VALUES "Bob":("Bob")`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkResultSet(t, "insert after WaitForSchema", resp)
+}
+
+func TestSession_OnConnectStmts(t *testing.T) {
+	var executed int
+	config := NewPoolConf(
+		WithOnConnectStmt("SHOW SPACES"),
+		WithInterceptors(func(ctx context.Context, stmt string, params map[string]interface{}, invoker Invoker) (*ResultSet, error) {
+			if stmt == "SHOW SPACES" {
+				executed++
+			}
+			return invoker(ctx, stmt, params)
+		}),
+	)
+	config.MaxConnPoolSize = 1
+	host := HostAddress{address, port}
+	pool, err := NewConnectionPool([]HostAddress{host}, config, DefaultLogger{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+
+	for i := 0; i < 3; i++ {
+		sess, err := pool.GetSession("root", "nebula")
+		if err != nil {
+			t.Fatal(err)
+		}
+		sess.Release()
+	}
+	if executed != 1 {
+		t.Fatalf("expected the on-connect statement to be executed once, executed %d times", executed)
+	}
+}
+
+func TestSession_KillOnCancel(t *testing.T) {
+	config := NewPoolConf(WithCredentials("root", "nebula"), WithKillOnCancel())
+	host := HostAddress{address, port}
+	pool, err := NewConnectionPool([]HostAddress{host}, config, DefaultLogger{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+
+	sess, err := pool.Acquire(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sess.Release()
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	if _, err = sess.ExecuteWithContext(ctx, "UNWIND range(1, 1000000000) AS x RETURN count(x)", nil); err == nil {
+		t.Fatal("expected the execution to be canceled")
+	}
+
+	admin, err := pool.Acquire(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer admin.Release()
+	for i := 0; ; i++ {
+		queries, err := admin.ShowQueries(context.Background(), false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if findSessionQuery(queries, sess.GetSessionID()) == nil {
+			break
+		}
+		if i == 50 {
+			t.Fatal("expected the canceled query to be killed")
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+func TestSession_WithSession(t *testing.T) {
+	config := NewPoolConf(WithCredentials("root", "nebula"))
+	host := HostAddress{address, port}
+	pool, err := NewConnectionPool([]HostAddress{host}, config, DefaultLogger{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+
+	attempts := 0
+	err = pool.WithSession(context.Background(), func(s *Session) error {
+		attempts++
+		if _, err := s.Execute("YIELD 1"); err != nil {
+			return err
+		}
+		if attempts < 2 {
+			return fmt.Errorf("transient")
+		}
+		return nil
+	}, WithRetries(3, 10*time.Millisecond))
+	if err != nil || attempts != 2 {
+		t.Fatalf("expected 2 attempts, got %d: %v", attempts, err)
+	}
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("expected the panic to be propagated")
+			}
+		}()
+		pool.WithSession(context.Background(), func(s *Session) error {
+			panic("boom")
+		})
+	}()
+	if n := pool.getActiveConnCount(); n != 0 {
+		t.Fatalf("expected the sessions to be released, %d connections are active", n)
+	}
+}
+
+func TestSession_ExecuteIn(t *testing.T) {
+	config := NewPoolConf(WithCredentials("root", "nebula"), WithExplicitSpace())
+	host := HostAddress{address, port}
+	pool, err := NewConnectionPool([]HostAddress{host}, config, DefaultLogger{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+
+	sess, err := pool.Acquire(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sess.Release()
+	if _, err := sess.ExecuteDDL(context.Background(), "CREATE SPACE IF NOT EXISTS test_explicit_space(vid_type = FIXED_STRING(8))"); err != nil {
+		t.Fatal(err)
+	}
+	if err := sess.WaitForSpace(context.Background(), "test_explicit_space", 100*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	resp, err := sess.ExecuteIn(context.Background(), "test_explicit_space", "SHOW TAGS", nil)
+	if err != nil || !resp.IsSucceed() {
+		t.Fatalf("failed to show tags: %v", err)
+	}
+	if sess.GetSpaceName() != "test_explicit_space" {
+		t.Fatalf("expected the session to use test_explicit_space, got %s", sess.GetSpaceName())
+	}
+	if _, err := sess.Execute("SHOW TAGS"); err == nil {
+		t.Fatal("expected the statement without explicit space to be rejected")
+	}
+}
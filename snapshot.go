@@ -0,0 +1,144 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Snapshot is a snapshot of the cluster, as shown by SHOW SNAPSHOTS
+type Snapshot struct {
+	Name string
+	// VALID or INVALID
+	Status string
+	// The storage hosts holding the snapshot
+	Hosts []string
+}
+
+// CreateSnapshot creates a snapshot of the cluster and returns it
+func (session *Session) CreateSnapshot(ctx context.Context) (Snapshot, error) {
+	before, err := session.ShowSnapshots(ctx)
+	if err != nil {
+		return Snapshot{}, err
+	}
+	if _, err := session.executeAdmin(ctx, "CREATE SNAPSHOT", "create snapshot"); err != nil {
+		return Snapshot{}, err
+	}
+	after, err := session.ShowSnapshots(ctx)
+	if err != nil {
+		return Snapshot{}, err
+	}
+	// the statement does not return the name of the snapshot
+	existing := make(map[string]bool, len(before))
+	for _, s := range before {
+		existing[s.Name] = true
+	}
+	for _, s := range after {
+		if !existing[s.Name] {
+			return s, nil
+		}
+	}
+	return Snapshot{}, fmt.Errorf("failed to create snapshot: the new snapshot is not listed")
+}
+
+// DropSnapshot drops the snapshot with the given name
+func (session *Session) DropSnapshot(ctx context.Context, name string) error {
+	_, err := session.executeAdmin(ctx, "DROP SNAPSHOT "+QuoteIdentifier(name), "drop snapshot "+name)
+	return err
+}
+
+// ShowSnapshots returns the snapshots of the cluster
+func (session *Session) ShowSnapshots(ctx context.Context) ([]Snapshot, error) {
+	resp, err := session.executeAdmin(ctx, "SHOW SNAPSHOTS", "show snapshots")
+	if err != nil {
+		return nil, err
+	}
+	return parseSnapshots(resp)
+}
+
+func parseSnapshots(resp *ResultSet) ([]Snapshot, error) {
+	snapshots := make([]Snapshot, 0, resp.GetRowSize())
+	for i := 0; i < resp.GetRowSize(); i++ {
+		record, err := resp.GetRowValuesByIndex(i)
+		if err != nil {
+			return nil, err
+		}
+		var s Snapshot
+		var hosts string
+		r := recordReader{record: record}
+		r.string("Name", &s.Name)
+		r.string("Status", &s.Status)
+		r.string("Hosts", &hosts)
+		if r.err != nil {
+			return nil, fmt.Errorf("failed to show snapshots: %s", r.err.Error())
+		}
+		for _, host := range strings.Split(hosts, ",") {
+			if host = strings.TrimSpace(host); host != "" {
+				s.Hosts = append(s.Hosts, host)
+			}
+		}
+		snapshots = append(snapshots, s)
+	}
+	return snapshots, nil
+}
+
+// CloneSpace creates the space target with the schema of the space source, without its data,
+// and waits until the target space can be used. It requires a graph service 3.1 or later.
+func (session *Session) CloneSpace(ctx context.Context, source, target string, pollInterval time.Duration) error {
+	if version := session.ServerVersion(); version.IsKnown() && !version.AtLeast(3, 1, 0) {
+		return fmt.Errorf("failed to clone space: not supported by the graph service %s", version)
+	}
+	// executed once, unlike ExecuteDDL, as a retry of a clone which was applied would fail on the existing target
+	stmt := fmt.Sprintf("CREATE SPACE %s AS %s", QuoteIdentifier(target), QuoteIdentifier(source))
+	if _, err := session.executeAdmin(ctx, stmt, fmt.Sprintf("clone space %s to %s", source, target)); err != nil {
+		return err
+	}
+	session.connPool.InvalidateSchema("")
+	return session.WaitForSpace(ctx, target, pollInterval)
+}
+
+// WaitForSpace waits until the space can be used, which happens after the graph service
+// has received the new schema from the meta service, polling every pollInterval.
+// The session is switched to the space once it is ready.
+func (session *Session) WaitForSpace(ctx context.Context, space string, pollInterval time.Duration) error {
+	stmt := "USE " + QuoteIdentifier(space)
+	for {
+		resp, err := session.ExecuteWithContext(ctx, stmt, nil)
+		if err != nil {
+			return err
+		}
+		if resp.IsSucceed() {
+			return nil
+		}
+		if !isSpaceNotFound(resp) {
+			return fmt.Errorf("failed to wait for space %s: %s", space, resp.GetErrorMsg())
+		}
+		timer := session.connPool.conf.Clock.NewTimer(pollInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C():
+		}
+	}
+}
+
+// isSpaceNotFound returns true if the statement failed because the graph service does not know the space yet
+func isSpaceNotFound(resp *ResultSet) bool {
+	switch resp.GetErrorCode() {
+	case ErrorCode_E_SPACE_NOT_FOUND:
+		return true
+	case ErrorCode_E_SEMANTIC_ERROR, ErrorCode_E_EXECUTION_ERROR:
+		return strings.Contains(resp.GetErrorMsg(), "SpaceNotFound")
+	}
+	return false
+}
@@ -0,0 +1,46 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+)
+
+// WithSpaceValidation pins the sessions to the space of the pool config: before every statement the space
+// reported by the previous one is checked and, if the session has moved to another space, e.g. with a USE
+// issued by a previous user of the session, the statement is prefixed with a USE of the space of the pool config
+// in the same request. Corrections are logged and counted in PoolStats.SpaceCorrections.
+func WithSpaceValidation() PoolConfOption {
+	return func(conf *PoolConfig) {
+		conf.ValidateSpace = true
+	}
+}
+
+// correctSpace returns the statement prefixed with a USE of the space of the pool config
+// if ValidateSpace is set and the session uses another space.
+// Statements starting with USE and statements whose space is explicit are returned as is.
+func (session *Session) correctSpace(ctx context.Context, stmt string) string {
+	pool := session.connPool
+	if pool == nil || !pool.conf.ValidateSpace || pool.conf.Space == "" || pool.conf.ExplicitSpace ||
+		ctx.Value(explicitSpaceKey{}) != nil || startsWithUse(stmt) {
+		return stmt
+	}
+	space := session.GetSpaceName()
+	if space == "" || space == pool.conf.Space {
+		return stmt
+	}
+	atomic.AddInt64(&pool.spaceCorrections, 1)
+	if session.log != nil {
+		loggerFor(session.log, TenantFrom(ctx)).Warn(fmt.Sprintf("The session uses the space %s instead of the space %s of the pool config, "+
+			"the space has been switched back", space, pool.conf.Space))
+	}
+	return "USE " + QuoteIdentifier(pool.conf.Space) + "; " + stmt
+}
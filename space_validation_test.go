@@ -0,0 +1,38 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCorrectSpace(t *testing.T) {
+	ctx := context.Background()
+	conf := NewPoolConf(WithSpaceValidation())
+	conf.Space = "tenant1"
+	pool := &ConnectionPool{conf: conf}
+	session := &Session{connPool: pool, log: DefaultLogger{}}
+	assert.Equal(t, "YIELD 1", session.correctSpace(ctx, "YIELD 1"))
+
+	session.space = "tenant1"
+	assert.Equal(t, "YIELD 1", session.correctSpace(ctx, "YIELD 1"))
+
+	session.space = "tenant2"
+	assert.Equal(t, "USE `tenant1`; YIELD 1", session.correctSpace(ctx, "YIELD 1"))
+	assert.Equal(t, "USE tenant3; YIELD 1", session.correctSpace(ctx, "USE tenant3; YIELD 1"))
+	assert.Equal(t, "SHOW SPACES", session.correctSpace(withExplicitSpace(ctx), "SHOW SPACES"))
+	assert.Equal(t, int64(1), pool.spaceCorrections)
+
+	pool.conf.ValidateSpace = false
+	assert.Equal(t, "YIELD 1", session.correctSpace(ctx, "YIELD 1"))
+	assert.NotNil(t, NewPoolConf(WithSpaceValidation()).Validate())
+}
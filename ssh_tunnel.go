@@ -0,0 +1,191 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+// DefaultSSHKeepAlive is the interval of the keepalive requests of NewSSHTunnel
+const DefaultSSHKeepAlive = 30 * time.Second
+
+// SSHClient is a client connected to an SSH server, it is implemented by *ssh.Client of golang.org/x/crypto/ssh
+type SSHClient interface {
+	Dial(network, address string) (net.Conn, error)
+	SendRequest(name string, wantReply bool, payload []byte) (bool, []byte, error)
+	Close() error
+}
+
+// SSHDialFunc connects to the SSH server of a bastion host, e.g.
+//
+//	func() (nebula.SSHClient, error) {
+//		return ssh.Dial("tcp", "bastion:22", sshConfig)
+//	}
+type SSHDialFunc func() (SSHClient, error)
+
+// SSHTunnel opens the connections of a pool through a bastion host, see WithSSHTunnel.
+// The SSH connection is opened by the first dial, and it is opened again by the next dial after it broke,
+// a dial failing on a broken SSH connection being retried once on a new one.
+type SSHTunnel struct {
+	dialSSH   SSHDialFunc
+	keepAlive time.Duration
+	clock     Clock
+
+	mu     sync.Mutex
+	client SSHClient
+	closed bool
+	done   chan struct{}
+}
+
+// NewSSHTunnel returns a tunnel sending a keepalive request every keepAlive on its SSH connection,
+// which is closed if the request fails. 0 means DefaultSSHKeepAlive and a negative value no keepalive.
+// The tunnel must be closed once the pools using it are closed.
+func NewSSHTunnel(dial SSHDialFunc, keepAlive time.Duration) *SSHTunnel {
+	return NewSSHTunnelWithClock(dial, keepAlive, realClock{})
+}
+
+// NewSSHTunnelWithClock returns a tunnel like NewSSHTunnel whose keepalive requests are timed by the clock,
+// e.g. the clock of the pool config
+func NewSSHTunnelWithClock(dial SSHDialFunc, keepAlive time.Duration, clock Clock) *SSHTunnel {
+	if keepAlive == 0 {
+		keepAlive = DefaultSSHKeepAlive
+	}
+	t := &SSHTunnel{dialSSH: dial, keepAlive: keepAlive, clock: clock, done: make(chan struct{})}
+	if keepAlive > 0 {
+		timer := clock.NewTimer(keepAlive)
+		go t.keepAliveLoop(timer)
+	}
+	return t
+}
+
+// WithSSHTunnel opens the connections of the pool through the tunnel
+func WithSSHTunnel(tunnel *SSHTunnel) PoolConfOption {
+	return WithDialer(tunnel.Dial)
+}
+
+// Dial opens a connection to the address from the bastion host, it is a DialFunc
+func (t *SSHTunnel) Dial(ctx context.Context, network, address string) (net.Conn, error) {
+	var err error
+	for attempt := 0; attempt < 2; attempt++ {
+		var client SSHClient
+		if client, err = t.sshClient(); err != nil {
+			return nil, err
+		}
+		var conn net.Conn
+		if conn, err = dialSSH(ctx, client, network, address); err == nil {
+			return conn, nil
+		}
+		// a channel rejected by the bastion host, e.g. when it can not reach the address,
+		// leaves the SSH connection usable by the other dials
+		if ctx.Err() != nil || !isSSHBroken(client) {
+			break
+		}
+		// the SSH connection is broken, e.g. after the bastion host restarted
+		t.reset(client)
+	}
+	return nil, fmt.Errorf("failed to dial %s through the SSH tunnel: %s", address, err.Error())
+}
+
+// dialSSH dials with the client until the context is done, SSHClient.Dial having no context
+func dialSSH(ctx context.Context, client SSHClient, network, address string) (net.Conn, error) {
+	type dialed struct {
+		conn net.Conn
+		err  error
+	}
+	ch := make(chan dialed, 1)
+	go func() {
+		conn, err := client.Dial(network, address)
+		ch <- dialed{conn, err}
+	}()
+	select {
+	case d := <-ch:
+		return d.conn, d.err
+	case <-ctx.Done():
+		go func() {
+			if d := <-ch; d.conn != nil {
+				d.conn.Close()
+			}
+		}()
+		return nil, ctx.Err()
+	}
+}
+
+// isSSHBroken returns true if the SSH connection does not answer a keepalive request
+func isSSHBroken(client SSHClient) bool {
+	_, _, err := client.SendRequest("keepalive@openssh.com", true, nil)
+	return err != nil
+}
+
+// sshClient returns the SSH connection of the tunnel, opening it if needed
+func (t *SSHTunnel) sshClient() (SSHClient, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closed {
+		return nil, fmt.Errorf("failed to dial through the SSH tunnel: the tunnel has been closed")
+	}
+	if t.client == nil {
+		client, err := t.dialSSH()
+		if err != nil {
+			return nil, fmt.Errorf("failed to open the SSH tunnel: %s", err.Error())
+		}
+		t.client = client
+	}
+	return t.client, nil
+}
+
+// reset closes the SSH connection if it is still the one of the tunnel, so that the next dial opens a new one
+func (t *SSHTunnel) reset(client SSHClient) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.client == client {
+		t.client = nil
+		client.Close()
+	}
+}
+
+func (t *SSHTunnel) keepAliveLoop(timer Timer) {
+	for {
+		select {
+		case <-t.done:
+			timer.Stop()
+			return
+		case <-timer.C():
+		}
+		timer.Reset(t.keepAlive)
+		t.mu.Lock()
+		client := t.client
+		t.mu.Unlock()
+		if client != nil {
+			if _, _, err := client.SendRequest("keepalive@openssh.com", true, nil); err != nil {
+				t.reset(client)
+			}
+		}
+	}
+}
+
+// Close closes the SSH connection and stops the keepalive, the connections opened through it are closed
+func (t *SSHTunnel) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closed {
+		return nil
+	}
+	t.closed = true
+	close(t.done)
+	if t.client != nil {
+		err := t.client.Close()
+		t.client = nil
+		return err
+	}
+	return nil
+}
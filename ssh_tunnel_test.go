@@ -0,0 +1,149 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeSSHClient struct {
+	broken    bool
+	rejected  bool
+	closed    bool
+	dialed    []string
+	keepAlive chan error
+}
+
+func (c *fakeSSHClient) Dial(network, address string) (net.Conn, error) {
+	if c.broken {
+		return nil, fmt.Errorf("ssh: unexpected packet")
+	}
+	if c.rejected {
+		return nil, fmt.Errorf("ssh: rejected: connect failed (No route to host)")
+	}
+	c.dialed = append(c.dialed, address)
+	conn, _ := net.Pipe()
+	return conn, nil
+}
+
+func (c *fakeSSHClient) SendRequest(name string, wantReply bool, payload []byte) (bool, []byte, error) {
+	if c.broken {
+		return false, nil, fmt.Errorf("EOF")
+	}
+	if c.keepAlive == nil {
+		return true, nil, nil
+	}
+	err := <-c.keepAlive
+	return err == nil, nil, err
+}
+
+func (c *fakeSSHClient) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestSSHTunnelDial(t *testing.T) {
+	var clients []*fakeSSHClient
+	tunnel := NewSSHTunnel(func() (SSHClient, error) {
+		client := &fakeSSHClient{broken: len(clients) == 0}
+		clients = append(clients, client)
+		return client, nil
+	}, -1)
+	defer tunnel.Close()
+
+	// the first SSH connection is broken, the dial is retried on a new one
+	conn, err := tunnel.Dial(context.Background(), "tcp", "graphd:9669")
+	assert.Nil(t, err)
+	conn.Close()
+	if assert.Len(t, clients, 2) {
+		assert.True(t, clients[0].closed)
+		assert.Equal(t, []string{"graphd:9669"}, clients[1].dialed)
+	}
+
+	conn, err = tunnel.Dial(context.Background(), "tcp", "graphd:9669")
+	assert.Nil(t, err)
+	conn.Close()
+	assert.Len(t, clients, 2)
+
+	assert.Nil(t, tunnel.Close())
+	assert.True(t, clients[1].closed)
+	_, err = tunnel.Dial(context.Background(), "tcp", "graphd:9669")
+	assert.EqualError(t, err, "failed to dial through the SSH tunnel: the tunnel has been closed")
+}
+
+func TestSSHTunnelDialRejected(t *testing.T) {
+	var clients []*fakeSSHClient
+	tunnel := NewSSHTunnel(func() (SSHClient, error) {
+		client := &fakeSSHClient{rejected: true}
+		clients = append(clients, client)
+		return client, nil
+	}, -1)
+	defer tunnel.Close()
+
+	// the SSH connection answers the keepalive, it is kept for the other dials
+	_, err := tunnel.Dial(context.Background(), "tcp", "unreachable:9669")
+	assert.EqualError(t, err, "failed to dial unreachable:9669 through the SSH tunnel: "+
+		"ssh: rejected: connect failed (No route to host)")
+	if assert.Len(t, clients, 1) {
+		assert.False(t, clients[0].closed)
+	}
+
+	clients[0].rejected = false
+	conn, err := tunnel.Dial(context.Background(), "tcp", "graphd:9669")
+	assert.Nil(t, err)
+	conn.Close()
+	assert.Len(t, clients, 1)
+}
+
+func TestSSHTunnelDialFailure(t *testing.T) {
+	tunnel := NewSSHTunnel(func() (SSHClient, error) {
+		return nil, fmt.Errorf("connection refused")
+	}, -1)
+	defer tunnel.Close()
+	_, err := tunnel.Dial(context.Background(), "tcp", "graphd:9669")
+	assert.EqualError(t, err, "failed to open the SSH tunnel: connection refused")
+}
+
+func TestSSHTunnelKeepAlive(t *testing.T) {
+	clock := NewManualClock(time.Now())
+	var clients []*fakeSSHClient
+	tunnel := NewSSHTunnelWithClock(func() (SSHClient, error) {
+		client := &fakeSSHClient{keepAlive: make(chan error)}
+		clients = append(clients, client)
+		return client, nil
+	}, time.Second, clock)
+	defer tunnel.Close()
+
+	conn, err := tunnel.Dial(context.Background(), "tcp", "graphd:9669")
+	assert.Nil(t, err)
+	conn.Close()
+
+	clock.Advance(time.Second)
+	clients[0].keepAlive <- nil
+	clock.Advance(time.Second)
+	clients[0].keepAlive <- fmt.Errorf("EOF")
+
+	// the failed keepalive closes the SSH connection, the next dial opens a new one
+	assert.Eventually(t, func() bool {
+		tunnel.mu.Lock()
+		defer tunnel.mu.Unlock()
+		return tunnel.client == nil
+	}, time.Second, time.Millisecond)
+	assert.True(t, clients[0].closed)
+	conn, err = tunnel.Dial(context.Background(), "tcp", "graphd:9669")
+	assert.Nil(t, err)
+	conn.Close()
+	assert.Len(t, clients, 2)
+}
@@ -0,0 +1,212 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// the kinds of the tokens of a statement
+const (
+	tokenSpace  = iota // whitespace and comments
+	tokenWord          // keywords and identifiers
+	tokenIdent         // identifiers quoted with backquotes
+	tokenString        // string literals
+	tokenNumber        // numeric literals
+	tokenParam         // parameters and variables, e.g. $p
+	tokenPunct         // operators and punctuation
+)
+
+type token struct {
+	kind int
+	text string
+}
+
+// the keywords of nGQL, which are case insensitive unlike the identifiers
+var ngqlKeywords = map[string]bool{}
+
+func init() {
+	for _, kw := range strings.Fields(`
+		add alter all and as asc ascending balance bidirect both by case charset collate collation comment
+		configs contains create data delete desc descending describe detach distinct download drop edge edges
+		else end ends exists explain false fetch find format from get go grant graph group host hosts if in
+		index indexes ingest insert intersect into is job jobs kill limit listener lookup match meta minus
+		no noloop not null offset on optional or order out over part parts path profile prop queries query
+		rebuild return reversely revoke role roles sample session sessions set shortest show skip snapshot
+		snapshots space spaces starts stats status step steps storage subgraph submit tag tags then to true
+		ttl_col ttl_duration union unwind update upsert upto use user users values vertex vertices when where with
+		xor yield`) {
+		ngqlKeywords[kw] = true
+	}
+}
+
+// lexStatement splits a statement into tokens. Comments start with #, //, -- followed by a space,
+// or are enclosed in /* */. Strings are enclosed in double or single quotes and escaped with backslashes.
+// Unterminated strings, identifiers and comments extend to the end of the statement.
+func lexStatement(stmt string) []token {
+	var tokens []token
+	for i := 0; i < len(stmt); {
+		start := i
+		kind := tokenPunct
+		c := stmt[i]
+		switch {
+		case isSpace(c):
+			kind = tokenSpace
+			for i < len(stmt) && isSpace(stmt[i]) {
+				i++
+			}
+		case c == '#' || strings.HasPrefix(stmt[i:], "//") ||
+			(strings.HasPrefix(stmt[i:], "--") && (i+2 == len(stmt) || isSpace(stmt[i+2]))):
+			kind = tokenSpace
+			if j := strings.IndexByte(stmt[i:], '\n'); j >= 0 {
+				i += j
+			} else {
+				i = len(stmt)
+			}
+		case strings.HasPrefix(stmt[i:], "/*"):
+			kind = tokenSpace
+			if j := strings.Index(stmt[i+2:], "*/"); j >= 0 {
+				i += j + 4
+			} else {
+				i = len(stmt)
+			}
+		case c == '"' || c == '\'' || c == '`':
+			kind = tokenString
+			if c == '`' {
+				kind = tokenIdent
+			}
+			for i++; i < len(stmt) && stmt[i] != c; i++ {
+				if stmt[i] == '\\' {
+					i++
+				}
+			}
+			if i++; i > len(stmt) {
+				i = len(stmt)
+			}
+		case isDigit(c) || (c == '.' && i+1 < len(stmt) && isDigit(stmt[i+1])):
+			kind = tokenNumber
+			for i++; i < len(stmt) && (isWordChar(stmt[i]) || stmt[i] == '.' ||
+				((stmt[i] == '+' || stmt[i] == '-') && (stmt[i-1] == 'e' || stmt[i-1] == 'E'))); i++ {
+			}
+		case isWordStart(c):
+			kind = tokenWord
+			for i++; i < len(stmt) && isWordChar(stmt[i]); i++ {
+			}
+		case c == '$' && i+1 < len(stmt) && isWordStart(stmt[i+1]):
+			kind = tokenParam
+			for i += 2; i < len(stmt) && isWordChar(stmt[i]); i++ {
+			}
+		default:
+			i++
+		}
+		tokens = append(tokens, token{kind: kind, text: stmt[start:i]})
+	}
+	return tokens
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f' || c == '\v'
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isWordStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c >= 0x80
+}
+
+func isWordChar(c byte) bool {
+	return isWordStart(c) || isDigit(c)
+}
+
+// NormalizeStatement strips the comments of the statement, collapses its whitespace into single spaces
+// and lowercases its keywords, so that statements differing only by their formatting are equal,
+// e.g. to deduplicate or log them. Strings and identifiers are left as is, as identifiers are case sensitive.
+// It is the basis of the templates of FingerprintStatement, which key the metrics and the caches of the driver.
+func NormalizeStatement(stmt string) string {
+	return normalizeTokens(lexStatement(stmt))
+}
+
+// normalizeTokens joins the tokens as NormalizeStatement does
+func normalizeTokens(tokens []token) string {
+	return joinTokens(tokens, func(t token) string {
+		if t.kind == tokenWord && ngqlKeywords[strings.ToLower(t.text)] {
+			return strings.ToLower(t.text)
+		}
+		return t.text
+	})
+}
+
+// joinTokens joins the mapped tokens, replacing whitespace and comments with single spaces
+func joinTokens(tokens []token, mapToken func(token) string) string {
+	var sb strings.Builder
+	space := false
+	for _, t := range tokens {
+		if t.kind == tokenSpace {
+			space = true
+			continue
+		}
+		if space && sb.Len() > 0 {
+			sb.WriteByte(' ')
+		}
+		space = false
+		sb.WriteString(mapToken(t))
+	}
+	return sb.String()
+}
+
+// StatementFingerprint is the template of a statement and its digest
+type StatementFingerprint struct {
+	// The normalized statement, see NormalizeStatement, with its literals replaced by ?,
+	// a list of literals is replaced by a single ?
+	Template string
+	// The hex encoded hash of the template
+	Digest string
+}
+
+// FingerprintStatement returns the fingerprint of the statement, so that statements only differing
+// by their literals or formatting share a template, e.g. to label the latency metrics
+// and the slow query logs without an unbounded number of labels.
+func FingerprintStatement(stmt string) StatementFingerprint {
+	var tokens []token
+	for _, t := range lexStatement(stmt) {
+		if isLiteral(t) {
+			t = token{kind: tokenPunct, text: "?"}
+			// a list of literals, e.g. the VIDs of a FETCH, is replaced by a single ?
+			if n := len(tokens); n >= 2 && tokens[n-1].text == "," && tokens[n-2].text == "?" {
+				tokens = tokens[:n-1]
+				continue
+			}
+			if n := len(tokens); n >= 3 && tokens[n-1].kind == tokenSpace && tokens[n-2].text == "," && tokens[n-3].text == "?" {
+				tokens = tokens[:n-2]
+				continue
+			}
+		}
+		tokens = append(tokens, t)
+	}
+	template := normalizeTokens(tokens)
+	sum := sha256.Sum256([]byte(template))
+	return StatementFingerprint{Template: template, Digest: hex.EncodeToString(sum[:8])}
+}
+
+func isLiteral(t token) bool {
+	switch t.kind {
+	case tokenString, tokenNumber:
+		return true
+	case tokenWord:
+		switch strings.ToLower(t.text) {
+		case "true", "false", "null":
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,175 @@
+//go:build go1.16
+// +build go1.16
+
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+)
+
+// The directives of the .ngql files loaded by LoadStatements
+const (
+	nameDirective    = "-- name:"
+	paramsDirective  = "-- params:"
+	includeDirective = "-- include:"
+)
+
+// LoadStatements registers the statements of the .ngql files of fsys matching the patterns,
+// e.g. the files embedded with go:embed, so that nGQL can be reviewed outside of go strings:
+//
+//	//go:embed queries/*.ngql
+//	var queries embed.FS
+//
+//	func init() {
+//		nebula.MustLoadStatements(queries, "queries/*.ngql")
+//	}
+//
+// Each statement of a file starts with a "-- name: <name>" line, and may declare the parameters it requires
+// with a "-- params: <name>, <name>" line. A "-- include: <name>" line is replaced by the statement
+// of that name, which can be defined in any of the files, e.g. a fragment shared by several statements.
+// The trailing semicolons are removed. Nothing is registered if a file is invalid.
+func LoadStatements(fsys fs.FS, patterns ...string) error {
+	stmts, err := loadStatements(fsys, patterns...)
+	if err != nil {
+		return err
+	}
+	return RegisterStatement(stmts...)
+}
+
+// MustLoadStatements is LoadStatements panicking on error, for the initialization of packages
+func MustLoadStatements(fsys fs.FS, patterns ...string) {
+	if err := LoadStatements(fsys, patterns...); err != nil {
+		panic(err)
+	}
+}
+
+func loadStatements(fsys fs.FS, patterns ...string) ([]NamedStatement, error) {
+	var files []string
+	for _, pattern := range patterns {
+		matches, err := fs.Glob(fsys, pattern)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load statements: %s", err.Error())
+		}
+		files = append(files, matches...)
+	}
+	sort.Strings(files)
+	var stmts []NamedStatement
+	for _, file := range files {
+		content, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load statements: %s", err.Error())
+		}
+		parsed, err := parseStatements(file, string(content))
+		if err != nil {
+			return nil, err
+		}
+		stmts = append(stmts, parsed...)
+	}
+	return resolveIncludes(stmts)
+}
+
+// parseStatements parses the statements of a .ngql file
+func parseStatements(source, content string) ([]NamedStatement, error) {
+	var stmts []NamedStatement
+	var body []string
+	flush := func() {
+		if len(stmts) > 0 {
+			stmt := strings.TrimSpace(strings.Join(body, "\n"))
+			stmts[len(stmts)-1].Stmt = strings.TrimSpace(strings.TrimRight(stmt, ";"))
+		}
+		body = nil
+	}
+	for i, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, nameDirective):
+			flush()
+			name := strings.TrimSpace(strings.TrimPrefix(trimmed, nameDirective))
+			if name == "" {
+				return nil, fmt.Errorf("failed to load statements: %s:%d: empty name", source, i+1)
+			}
+			stmts = append(stmts, NamedStatement{Name: name, Source: source})
+		case len(stmts) == 0:
+			if trimmed != "" && !strings.HasPrefix(trimmed, "--") {
+				return nil, fmt.Errorf("failed to load statements: %s:%d: statement without %s line", source, i+1, nameDirective)
+			}
+		case strings.HasPrefix(trimmed, paramsDirective):
+			for _, p := range strings.Split(strings.TrimPrefix(trimmed, paramsDirective), ",") {
+				if p = strings.TrimPrefix(strings.TrimSpace(p), "$"); p != "" {
+					stmts[len(stmts)-1].Params = append(stmts[len(stmts)-1].Params, p)
+				}
+			}
+		default:
+			body = append(body, strings.TrimRight(line, " \t\r"))
+		}
+	}
+	flush()
+	return stmts, nil
+}
+
+// resolveIncludes replaces the include lines of the statements by the statements they name
+func resolveIncludes(stmts []NamedStatement) ([]NamedStatement, error) {
+	byName := make(map[string]*NamedStatement, len(stmts))
+	for i := range stmts {
+		byName[stmts[i].Name] = &stmts[i]
+	}
+	resolved := make(map[string]bool, len(stmts))
+	var resolve func(s *NamedStatement, stack []string) error
+	resolve = func(s *NamedStatement, stack []string) error {
+		if resolved[s.Name] {
+			return nil
+		}
+		for _, name := range stack {
+			if name == s.Name {
+				return fmt.Errorf("failed to load statements: include cycle %s", strings.Join(append(stack, s.Name), " -> "))
+			}
+		}
+		lines := strings.Split(s.Stmt, "\n")
+		for i, line := range lines {
+			trimmed := strings.TrimSpace(line)
+			if !strings.HasPrefix(trimmed, includeDirective) {
+				continue
+			}
+			name := strings.TrimSpace(strings.TrimPrefix(trimmed, includeDirective))
+			included, ok := byName[name]
+			if !ok {
+				return fmt.Errorf("failed to load statements: %s includes unknown statement %s", s.Name, name)
+			}
+			if err := resolve(included, append(stack, s.Name)); err != nil {
+				return err
+			}
+			lines[i] = included.Stmt
+			s.Params = appendMissing(s.Params, included.Params)
+		}
+		s.Stmt = strings.Join(lines, "\n")
+		resolved[s.Name] = true
+		return nil
+	}
+	for i := range stmts {
+		if err := resolve(&stmts[i], nil); err != nil {
+			return nil, err
+		}
+	}
+	return stmts, nil
+}
+
+// appendMissing appends the items of add which are not in list
+func appendMissing(list, add []string) []string {
+	for _, item := range add {
+		if !containsString(list, item) {
+			list = append(list, item)
+		}
+	}
+	return list
+}
@@ -0,0 +1,69 @@
+//go:build go1.16
+// +build go1.16
+
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadStatements(t *testing.T) {
+	fsys := fstest.MapFS{
+		"queries/players.ngql": {Data: []byte(`-- Queries of the players
+-- name: player_by_id
+-- params: $id
+FETCH PROP ON player $id
+-- include: player_fields
+;
+
+-- name: players_of_team
+-- params: team, limit
+MATCH (v:player)-[:serve]->(t:team) WHERE id(t) == $team
+RETURN v LIMIT $limit;
+`)},
+		"queries/fragments.ngql": {Data: []byte(`-- name: player_fields
+-- params: unused
+YIELD properties(vertex).name AS name, properties(vertex).age AS age
+`)},
+		"other.txt": {Data: []byte("not loaded")},
+	}
+	stmts, err := loadStatements(fsys, "queries/*.ngql")
+	assert.Nil(t, err)
+	assert.Len(t, stmts, 3)
+	assert.Equal(t, NamedStatement{
+		Name:   "player_by_id",
+		Stmt:   "FETCH PROP ON player $id\nYIELD properties(vertex).name AS name, properties(vertex).age AS age",
+		Params: []string{"id", "unused"},
+		Source: "queries/players.ngql",
+	}, stmts[1])
+	assert.Equal(t, "MATCH (v:player)-[:serve]->(t:team) WHERE id(t) == $team\nRETURN v LIMIT $limit", stmts[2].Stmt)
+
+	registry := &statementRegistry{statements: make(map[string]NamedStatement)}
+	assert.Nil(t, registry.register(stmts...))
+	s, ok := registry.lookup("players_of_team")
+	assert.True(t, ok)
+	assert.NotNil(t, s.checkParams(map[string]interface{}{"team": "t1"}))
+	assert.Nil(t, s.checkParams(map[string]interface{}{"team": "t1", "limit": 10}))
+	assert.NotNil(t, registry.register(stmts[0]), "the names must be unique")
+
+	for _, content := range []string{
+		"MATCH (v) RETURN v",
+		"-- name:\nYIELD 1",
+		"-- name: a\n-- include: b\n-- name: b\n-- include: a",
+		"-- name: a\n-- include: missing",
+	} {
+		_, err := loadStatements(fstest.MapFS{"bad.ngql": {Data: []byte(content)}}, "*.ngql")
+		assert.NotNil(t, err, content)
+	}
+}
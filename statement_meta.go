@@ -0,0 +1,92 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"context"
+	"time"
+)
+
+// StatementMeta is the metadata of a statement. It is carried by the context of the execution,
+// so that the interceptors, e.g. routing or retry policies, can act on it without parsing the statement.
+type StatementMeta struct {
+	// The timeout of the statement, applied to the context of the execution
+	Timeout time.Duration
+	// Free-form labels, e.g. the name of the feature issuing the statement
+	Labels map[string]string
+	// True if the statement does not write, so that it can be retried or routed to any cluster
+	ReadOnly bool
+}
+
+type statementMetaKey struct{}
+
+// WithStatementMeta returns a context carrying the metadata of a statement
+func WithStatementMeta(ctx context.Context, meta StatementMeta) context.Context {
+	return context.WithValue(ctx, statementMetaKey{}, meta)
+}
+
+// StatementMetaFrom returns the metadata of the statement executed with the context, false if there is none
+func StatementMetaFrom(ctx context.Context) (StatementMeta, bool) {
+	meta, ok := ctx.Value(statementMetaKey{}).(StatementMeta)
+	return meta, ok
+}
+
+// Query is a statement with its parameters and metadata, built with NewQuery and executed with ExecuteQuery
+type Query struct {
+	Stmt   string
+	Params map[string]interface{}
+	Meta   StatementMeta
+}
+
+// NewQuery returns a query of the statement
+func NewQuery(stmt string) *Query {
+	return &Query{Stmt: stmt}
+}
+
+// Param sets a parameter of the query
+func (q *Query) Param(name string, value interface{}) *Query {
+	if q.Params == nil {
+		q.Params = make(map[string]interface{})
+	}
+	q.Params[name] = value
+	return q
+}
+
+// Timeout sets the timeout of the query
+func (q *Query) Timeout(timeout time.Duration) *Query {
+	q.Meta.Timeout = timeout
+	return q
+}
+
+// Label sets a label of the query
+func (q *Query) Label(key, value string) *Query {
+	if q.Meta.Labels == nil {
+		q.Meta.Labels = make(map[string]string)
+	}
+	q.Meta.Labels[key] = value
+	return q
+}
+
+// ReadOnly marks the query as read-only
+func (q *Query) ReadOnly() *Query {
+	q.Meta.ReadOnly = true
+	return q
+}
+
+// ExecuteQuery executes the query with its metadata in the context, bounded by its timeout if any.
+// Like for ExecuteWithContext, the timeout is not pushed down to the graph service.
+func (session *Session) ExecuteQuery(ctx context.Context, q *Query) (*ResultSet, error) {
+	ctx = WithStatementMeta(ctx, q.Meta)
+	if q.Meta.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, q.Meta.Timeout)
+		defer cancel()
+	}
+	return session.ExecuteWithContext(ctx, q.Stmt, q.Params)
+}
@@ -0,0 +1,49 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExecuteQuery(t *testing.T) {
+	var meta StatementMeta
+	var deadline bool
+	var params map[string]interface{}
+	conf := NewPoolConf(WithInterceptors(func(ctx context.Context, stmt string, p map[string]interface{}, invoker Invoker) (*ResultSet, error) {
+		meta, _ = StatementMetaFrom(ctx)
+		_, deadline = ctx.Deadline()
+		params = p
+		return &ResultSet{}, nil
+	}))
+	session := &Session{connPool: &ConnectionPool{conf: conf}}
+
+	q := NewQuery("FETCH PROP ON player $id YIELD vertex AS v").
+		Param("id", "Tim").
+		Timeout(time.Second).
+		Label("feature", "profile").
+		ReadOnly()
+	_, err := session.ExecuteQuery(context.Background(), q)
+	assert.Nil(t, err)
+	assert.Equal(t, StatementMeta{Timeout: time.Second, Labels: map[string]string{"feature": "profile"}, ReadOnly: true}, meta)
+	assert.True(t, deadline)
+	assert.Equal(t, map[string]interface{}{"id": "Tim"}, params)
+
+	_, err = session.ExecuteQuery(context.Background(), NewQuery("YIELD 1"))
+	assert.Nil(t, err)
+	assert.False(t, deadline)
+	assert.False(t, meta.ReadOnly)
+
+	_, ok := StatementMetaFrom(context.Background())
+	assert.False(t, ok)
+}
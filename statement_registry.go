@@ -0,0 +1,108 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// NamedStatement is a statement registered under a name, e.g. loaded from a .ngql file by LoadStatements
+type NamedStatement struct {
+	Name string
+	Stmt string
+	// The parameters the statement requires
+	Params []string
+	// Where the statement comes from, e.g. the file it was loaded from
+	Source string
+}
+
+type statementRegistry struct {
+	sync.RWMutex
+	statements map[string]NamedStatement
+}
+
+// the process-wide registry of RegisterStatement
+var statements = &statementRegistry{statements: make(map[string]NamedStatement)}
+
+func (r *statementRegistry) register(stmts ...NamedStatement) error {
+	r.Lock()
+	defer r.Unlock()
+	for i, s := range stmts {
+		if s.Name == "" {
+			return fmt.Errorf("failed to register statement from %s: empty name", s.Source)
+		}
+		if prev, ok := r.statements[s.Name]; ok {
+			return fmt.Errorf("failed to register statement %s from %s: already registered from %s", s.Name, s.Source, prev.Source)
+		}
+		for _, other := range stmts[:i] {
+			if other.Name == s.Name {
+				return fmt.Errorf("failed to register statement %s from %s: already defined in %s", s.Name, s.Source, other.Source)
+			}
+		}
+	}
+	for _, s := range stmts {
+		r.statements[s.Name] = s
+	}
+	return nil
+}
+
+func (r *statementRegistry) lookup(name string) (NamedStatement, bool) {
+	r.RLock()
+	defer r.RUnlock()
+	s, ok := r.statements[name]
+	return s, ok
+}
+
+// RegisterStatement registers statements under their names, e.g. at init.
+// It fails without registering any statement if a name is already registered.
+func RegisterStatement(stmts ...NamedStatement) error {
+	return statements.register(stmts...)
+}
+
+// LookupStatement returns the statement registered under the name
+func LookupStatement(name string) (NamedStatement, bool) {
+	return statements.lookup(name)
+}
+
+// RegisteredStatements returns the names of the registered statements in order
+func RegisteredStatements() []string {
+	statements.RLock()
+	defer statements.RUnlock()
+	names := make([]string, 0, len(statements.statements))
+	for name := range statements.statements {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ExecuteNamed executes the statement registered under the name with the parameters,
+// which must include every parameter the statement requires
+func (session *Session) ExecuteNamed(ctx context.Context, name string, params map[string]interface{}) (*ResultSet, error) {
+	s, ok := LookupStatement(name)
+	if !ok {
+		return nil, fmt.Errorf("failed to execute statement %s: not registered", name)
+	}
+	if err := s.checkParams(params); err != nil {
+		return nil, err
+	}
+	return session.ExecuteWithContext(ctx, s.Stmt, params)
+}
+
+func (s NamedStatement) checkParams(params map[string]interface{}) error {
+	for _, p := range s.Params {
+		if _, ok := params[p]; !ok {
+			return fmt.Errorf("failed to execute statement %s: missing parameter %s", s.Name, p)
+		}
+	}
+	return nil
+}
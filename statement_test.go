@@ -0,0 +1,51 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeStatement(t *testing.T) {
+	for stmt, expected := range map[string]string{
+		"  GO FROM \"player100\"\n\tOVER Follow  YIELD dst(edge) ":         `go from "player100" over Follow yield dst(edge)`,
+		"/* client_name=app */ MATCH (v)--(v2) RETURN v // trailing":       "match (v)--(v2) return v",
+		"FETCH PROP ON player 'a  #b' # comment\nYIELD properties(vertex)": "fetch prop on player 'a  #b' yield properties(vertex)",
+		"USE `My Space`; SHOW TAGS -- comment":                             "use `My Space`; show tags",
+		"LOOKUP ON player WHERE player.age > $Age/*x*/LIMIT 10":            "lookup on player where player.age > $Age limit 10",
+		`RETURN "unterminated \" string`:                                   `return "unterminated \" string`,
+		"":                                                                 "",
+	} {
+		assert.Equal(t, expected, NormalizeStatement(stmt), stmt)
+	}
+
+	// the templates keying the metrics and the caches are the normalized statements without their literals
+	stmt := "/* client_name=app */ MATCH (v:Player)\n\tRETURN v LIMIT $n"
+	assert.Equal(t, NormalizeStatement(stmt), FingerprintStatement(stmt).Template)
+}
+
+func TestFingerprintStatement(t *testing.T) {
+	for stmt, expected := range map[string]string{
+		`FETCH PROP ON player "player100" YIELD properties(vertex)`:                  "fetch prop on player ? yield properties(vertex)",
+		`FETCH PROP ON player "player100", "player101",  'player102' YIELD vertex`:   "fetch prop on player ? yield vertex",
+		"LOOKUP ON player WHERE player.age > 30 AND player.name == \"Tim\" LIMIT 10": "lookup on player where player.age > ? and player.name == ? limit ?",
+		"RETURN [1, 2.5, true, NULL] AS l, $p":                                       "return [?] as l, $p",
+		"INSERT VERTEX player(name, age) VALUES \"a\":(\"A\", 1), \"b\":(\"B\", 2)":  "insert vertex player(name, age) values ?:(?), ?:(?)",
+	} {
+		assert.Equal(t, expected, FingerprintStatement(stmt).Template, stmt)
+	}
+
+	a := FingerprintStatement("GO FROM 'a' OVER follow")
+	b := FingerprintStatement("/* c */ go  from \"b\", \"c\" over follow")
+	assert.Equal(t, a, b)
+	assert.Len(t, a.Digest, 16)
+	assert.NotEqual(t, a.Digest, FingerprintStatement("GO FROM 'a' OVER serve").Digest)
+}
@@ -0,0 +1,99 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// StatementFailure is a registered statement rejected by the graph service
+type StatementFailure struct {
+	Name   string
+	Source string
+	Msg    string
+}
+
+// VerifyError lists the registered statements rejected by VerifyStatements
+type VerifyError struct {
+	Failures []StatementFailure
+}
+
+func (e *VerifyError) Error() string {
+	msgs := make([]string, len(e.Failures))
+	for i, f := range e.Failures {
+		msgs[i] = fmt.Sprintf("%s (%s): %s", f.Name, f.Source, f.Msg)
+	}
+	return fmt.Sprintf("%d invalid statement(s): %s", len(e.Failures), strings.Join(msgs, "; "))
+}
+
+// VerifyStatements EXPLAINs every registered statement with a session acquired from the pool
+// with the credentials of its config, so that a CI job can check the statements against the live schema
+// without executing them. It returns a *VerifyError listing every statement rejected by the graph service.
+// The parameters are passed to every statement, e.g. {"id": "player100", "limit": 10}, the parameters
+// a statement requires and which are missing are passed as null, which some statements reject:
+// give them values of the expected types.
+func VerifyStatements(ctx context.Context, pool *ConnectionPool, params map[string]interface{}) error {
+	session, err := pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to verify statements: %s", err.Error())
+	}
+	defer session.Release()
+	return verifyStatements(ctx, registeredStatements(), params,
+		func(ctx context.Context, stmt string, params map[string]interface{}) (*ResultSet, error) {
+			return session.ExecuteWithContext(ctx, stmt, params)
+		})
+}
+
+// registeredStatements returns the registered statements ordered by name
+func registeredStatements() []NamedStatement {
+	statements.RLock()
+	defer statements.RUnlock()
+	stmts := make([]NamedStatement, 0, len(statements.statements))
+	for _, s := range statements.statements {
+		stmts = append(stmts, s)
+	}
+	sort.Slice(stmts, func(i, j int) bool { return stmts[i].Name < stmts[j].Name })
+	return stmts
+}
+
+func verifyStatements(ctx context.Context, stmts []NamedStatement, params map[string]interface{},
+	execute func(ctx context.Context, stmt string, params map[string]interface{}) (*ResultSet, error)) error {
+	var failures []StatementFailure
+	for _, s := range stmts {
+		dummies := make(map[string]interface{}, len(params)+len(s.Params))
+		for _, p := range s.Params {
+			dummies[p] = nil
+		}
+		for k, v := range params {
+			dummies[k] = v
+		}
+		resp, err := execute(ctx, explainStmt(s.Stmt), dummies)
+		if err != nil {
+			return fmt.Errorf("failed to verify statement %s: %s", s.Name, err.Error())
+		}
+		if !resp.IsSucceed() {
+			failures = append(failures, StatementFailure{Name: s.Name, Source: s.Source, Msg: resp.GetErrorMsg()})
+		}
+	}
+	if len(failures) > 0 {
+		return &VerifyError{Failures: failures}
+	}
+	return nil
+}
+
+// explainStmt returns the EXPLAIN of the statement, the sentences of a sequence are explained together
+func explainStmt(stmt string) string {
+	if strings.Contains(stmt, ";") {
+		return "EXPLAIN {" + stmt + "}"
+	}
+	return "EXPLAIN " + stmt
+}
@@ -0,0 +1,53 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vesoft-inc/nebula-go/v3/nebula"
+)
+
+func TestVerifyStatements(t *testing.T) {
+	stmts := []NamedStatement{
+		{Name: "good", Stmt: "MATCH (v:player) RETURN v LIMIT $limit", Params: []string{"limit"}, Source: "a.ngql"},
+		{Name: "bad", Stmt: "MATCH (v:playr) RETURN v", Source: "b.ngql"},
+		{Name: "sequence", Stmt: "USE nba; MATCH (v) RETURN v", Source: "b.ngql"},
+	}
+	var executed []string
+	var passed []map[string]interface{}
+	execute := func(ctx context.Context, stmt string, params map[string]interface{}) (*ResultSet, error) {
+		executed = append(executed, stmt)
+		passed = append(passed, params)
+		resp := newTestResultSet(t, nil)
+		if strings.Contains(stmt, "playr") {
+			resp.resp.ErrorCode = nebula.ErrorCode_E_SEMANTIC_ERROR
+			resp.resp.ErrorMsg = []byte("No schema found for `playr'")
+		}
+		return resp, nil
+	}
+	err := verifyStatements(context.Background(), stmts, map[string]interface{}{"limit": 1}, execute)
+	if assert.IsType(t, &VerifyError{}, err) {
+		assert.Equal(t, []StatementFailure{{Name: "bad", Source: "b.ngql", Msg: "No schema found for `playr'"}},
+			err.(*VerifyError).Failures)
+	}
+	assert.Equal(t, []string{
+		"EXPLAIN MATCH (v:player) RETURN v LIMIT $limit",
+		"EXPLAIN MATCH (v:playr) RETURN v",
+		"EXPLAIN {USE nba; MATCH (v) RETURN v}",
+	}, executed)
+	assert.Equal(t, map[string]interface{}{"limit": 1}, passed[0])
+
+	err = verifyStatements(context.Background(), stmts[:1], nil, execute)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{"limit": nil}, passed[3])
+}
@@ -0,0 +1,72 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"context"
+)
+
+// TenantExtractor returns the tenant of the caller from the context of an execution, empty if there is none
+type TenantExtractor func(ctx context.Context) string
+
+type tenantKey struct{}
+
+// WithTenantFromContext labels the executions of the sessions of the pool with the tenant returned by extract:
+// the error counts, the latency histograms and the warnings logged during the execution carry the tenant,
+// the statements are sent with a "/* tenant=... */" comment visible in SHOW QUERIES and in the slow query logs
+// of the graph service, and the interceptors, e.g. tracing or auditing ones, get it from TenantFrom.
+func WithTenantFromContext(extract TenantExtractor) PoolConfOption {
+	return func(conf *PoolConfig) {
+		conf.TenantFromContext = extract
+	}
+}
+
+// TenantFrom returns the tenant of the execution the context is passed to, see WithTenantFromContext
+func TenantFrom(ctx context.Context) string {
+	tenant, _ := ctx.Value(tenantKey{}).(string)
+	return tenant
+}
+
+// withTenant returns the context carrying the tenant extracted by the pool, if any
+func (pool *ConnectionPool) withTenant(ctx context.Context) context.Context {
+	if pool == nil || pool.conf.TenantFromContext == nil || ctx.Value(tenantKey{}) != nil {
+		return ctx
+	}
+	if tenant := pool.conf.TenantFromContext(ctx); tenant != "" {
+		return context.WithValue(ctx, tenantKey{}, tenant)
+	}
+	return ctx
+}
+
+// tenantComment returns the comment labelling a statement with the tenant, empty if there is none
+func tenantComment(tenant string) string {
+	if tenant = sanitizeComment(tenant); tenant == "" {
+		return ""
+	}
+	return "/* tenant=" + tenant + " */ "
+}
+
+// tenantLogger prefixes the messages with the tenant
+type tenantLogger struct {
+	Logger
+	tenant string
+}
+
+// loggerFor returns the logger labelling the messages with the tenant, log itself if there is none
+func loggerFor(log Logger, tenant string) Logger {
+	if tenant == "" || log == nil {
+		return log
+	}
+	return tenantLogger{Logger: log, tenant: tenant}
+}
+
+func (l tenantLogger) Info(msg string)  { l.Logger.Info("[tenant " + l.tenant + "] " + msg) }
+func (l tenantLogger) Warn(msg string)  { l.Logger.Warn("[tenant " + l.tenant + "] " + msg) }
+func (l tenantLogger) Error(msg string) { l.Logger.Error("[tenant " + l.tenant + "] " + msg) }
+func (l tenantLogger) Fatal(msg string) { l.Logger.Fatal("[tenant " + l.tenant + "] " + msg) }
@@ -0,0 +1,70 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type tenantCtxKey struct{}
+
+type recordingLogger struct {
+	DefaultLogger
+	warnings []string
+}
+
+func (l *recordingLogger) Warn(msg string) {
+	l.warnings = append(l.warnings, msg)
+}
+
+func TestWithTenant(t *testing.T) {
+	pool := &ConnectionPool{conf: NewPoolConf(WithTenantFromContext(func(ctx context.Context) string {
+		tenant, _ := ctx.Value(tenantCtxKey{}).(string)
+		return tenant
+	}))}
+	ctx := context.WithValue(context.Background(), tenantCtxKey{}, "acme")
+	assert.Equal(t, "acme", TenantFrom(pool.withTenant(ctx)))
+	assert.Equal(t, "", TenantFrom(pool.withTenant(context.Background())))
+	assert.Equal(t, "", TenantFrom((&ConnectionPool{}).withTenant(ctx)))
+
+	assert.Equal(t, "/* tenant=acme */ ", tenantComment("acme"))
+	assert.Equal(t, "/* tenant=acme/ */ ", tenantComment("ac me*/;"))
+	assert.Equal(t, "", tenantComment(""))
+}
+
+func TestTenantLabels(t *testing.T) {
+	log := &recordingLogger{}
+	loggerFor(log, "acme").Warn("slow")
+	loggerFor(log, "").Warn("slow")
+	assert.Equal(t, []string{"[tenant acme] slow", "slow"}, log.warnings)
+
+	pool := &ConnectionPool{conf: NewPoolConf()}
+	graphd := HostAddress{Host: "graphd", Port: DefaultPort}
+	pool.recordError(graphd, "acme", "SHOW HOSTS", nil)
+	pool.recordError(graphd, "globex", "SHOW HOSTS", nil)
+	pool.recordError(graphd, "acme", "SHOW HOSTS", nil)
+	counts := pool.ErrorCounts()
+	if assert.Len(t, counts, 2) {
+		assert.Equal(t, "acme", counts[0].Tenant)
+		assert.Equal(t, int64(2), counts[0].Count)
+		assert.Equal(t, "globex", counts[1].Tenant)
+	}
+
+	pool.latencyHistograms.record(10, "globex", "SHOW HOSTS", time.Millisecond)
+	pool.latencyHistograms.record(10, "acme", "SHOW HOSTS", time.Millisecond)
+	histograms := pool.LatencyHistograms()
+	if assert.Len(t, histograms, 2) {
+		assert.Equal(t, "acme", histograms[0].Tenant)
+		assert.Equal(t, "globex", histograms[1].Tenant)
+	}
+}
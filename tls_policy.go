@@ -0,0 +1,119 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"crypto/tls"
+	"fmt"
+	"strings"
+)
+
+// the TLS versions of the tls_min_version parameter
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// the cipher suites of the tls_ciphers parameter, by their standard name.
+// The suites of TLS 1.3 are not configurable and the insecure suites are not supported.
+var tlsCipherSuites = map[string]uint16{
+	"TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256":       tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+	"TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384":       tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+	"TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256": tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305,
+	"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256":         tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+	"TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384":         tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+	"TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256":   tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305,
+	"TLS_ECDHE_ECDSA_WITH_AES_128_CBC_SHA":          tls.TLS_ECDHE_ECDSA_WITH_AES_128_CBC_SHA,
+	"TLS_ECDHE_ECDSA_WITH_AES_256_CBC_SHA":          tls.TLS_ECDHE_ECDSA_WITH_AES_256_CBC_SHA,
+	"TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA":            tls.TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA,
+	"TLS_ECDHE_RSA_WITH_AES_256_CBC_SHA":            tls.TLS_ECDHE_RSA_WITH_AES_256_CBC_SHA,
+	"TLS_RSA_WITH_AES_128_GCM_SHA256":               tls.TLS_RSA_WITH_AES_128_GCM_SHA256,
+	"TLS_RSA_WITH_AES_256_GCM_SHA384":               tls.TLS_RSA_WITH_AES_256_GCM_SHA384,
+}
+
+// WithTLSMinVersion enforces a minimum TLS version, e.g. tls.VersionTLS12, on the TLS configs of the pool
+func WithTLSMinVersion(version uint16) PoolConfOption {
+	return func(conf *PoolConfig) {
+		conf.TLSMinVersion = version
+	}
+}
+
+// WithTLSCipherSuites restricts the cipher suites of TLS 1.2 and lower, e.g. to the suites approved by FIPS 140-2.
+// The cipher suites of TLS 1.3 are not configurable.
+func WithTLSCipherSuites(suites ...uint16) PoolConfOption {
+	return func(conf *PoolConfig) {
+		conf.TLSCipherSuites = append([]uint16(nil), suites...)
+	}
+}
+
+// parseTLSVersion parses the value of the tls_min_version parameter, e.g. "1.2"
+func parseTLSVersion(value string) (uint16, error) {
+	version, ok := tlsVersions[value]
+	if !ok {
+		return 0, fmt.Errorf("unknown TLS version %s, it must be 1.0, 1.1, 1.2 or 1.3", value)
+	}
+	return version, nil
+}
+
+// formatTLSVersion formats a TLS version for the tls_min_version parameter
+func formatTLSVersion(version uint16) (string, bool) {
+	for name, v := range tlsVersions {
+		if v == version {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// parseTLSCipherSuites parses the comma separated names of the tls_ciphers parameter
+func parseTLSCipherSuites(value string) ([]uint16, error) {
+	var suites []uint16
+	for _, name := range strings.Split(value, ",") {
+		suite, ok := tlsCipherSuites[strings.TrimSpace(name)]
+		if !ok {
+			return nil, fmt.Errorf("unknown or insecure cipher suite %s", name)
+		}
+		suites = append(suites, suite)
+	}
+	return suites, nil
+}
+
+// formatTLSCipherSuite returns the name of a cipher suite for the tls_ciphers parameter
+func formatTLSCipherSuite(suite uint16) (string, bool) {
+	for name, s := range tlsCipherSuites {
+		if s == suite {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// withTLSPolicy returns a copy of the config enforcing the minimum version and the cipher suites,
+// or the config if there is nothing to enforce. It fails if there is a policy and TLS is disabled, i.e. the config is nil.
+func withTLSPolicy(config *tls.Config, minVersion uint16, suites []uint16) (*tls.Config, error) {
+	if config == nil {
+		if minVersion != 0 || len(suites) > 0 {
+			return nil, fmt.Errorf("TLSMinVersion and TLSCipherSuites require TLS")
+		}
+		return nil, nil
+	}
+	if config.MinVersion >= minVersion && len(suites) == 0 {
+		return config, nil
+	}
+	config = config.Clone()
+	if config.MinVersion < minVersion {
+		config.MinVersion = minVersion
+	}
+	if len(suites) > 0 {
+		config.CipherSuites = append([]uint16(nil), suites...)
+	}
+	return config, nil
+}
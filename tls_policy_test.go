@@ -0,0 +1,76 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"crypto/tls"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithTLSPolicy(t *testing.T) {
+	config := &tls.Config{}
+	same, err := withTLSPolicy(config, 0, nil)
+	assert.Nil(t, err)
+	assert.True(t, config == same)
+	plain, err := withTLSPolicy(nil, 0, nil)
+	assert.Nil(t, err)
+	assert.Nil(t, plain)
+	_, err = withTLSPolicy(nil, tls.VersionTLS12, nil)
+	assert.EqualError(t, err, "TLSMinVersion and TLSCipherSuites require TLS")
+
+	enforced, err := withTLSPolicy(config, tls.VersionTLS12, []uint16{tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256})
+	assert.Nil(t, err)
+	assert.Equal(t, uint16(tls.VersionTLS12), enforced.MinVersion)
+	assert.Equal(t, []uint16{tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256}, enforced.CipherSuites)
+	assert.Equal(t, uint16(0), config.MinVersion, "the config of the caller must not be changed")
+
+	// a higher minimum of the config is kept
+	config = &tls.Config{MinVersion: tls.VersionTLS13}
+	kept, err := withTLSPolicy(config, tls.VersionTLS12, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, uint16(tls.VersionTLS13), kept.MinVersion)
+
+	pool := &ConnectionPool{conf: NewPoolConf(WithTLSMinVersion(tls.VersionTLS12))}
+	assert.EqualError(t, pool.prepareTLS(),
+		"failed to prepare the TLS config of the pool: TLSMinVersion and TLSCipherSuites require TLS")
+	conf := NewPoolConf(WithTLSMinVersion(tls.VersionTLS12), WithHostTLS(HostAddress{Host: "graphd", Port: 9669}, nil))
+	assert.EqualError(t, conf.Validate(), "invalid config, 1 problem(s): "+
+		"TLSMinVersion and TLSCipherSuites require TLS, which HostTLS disables for host graphd:9669")
+}
+
+func TestTLSPolicyParams(t *testing.T) {
+	version, err := parseTLSVersion("1.3")
+	assert.Nil(t, err)
+	assert.Equal(t, uint16(tls.VersionTLS13), version)
+	_, err = parseTLSVersion("TLS1.3")
+	assert.NotNil(t, err)
+
+	suites, err := parseTLSCipherSuites("TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384, TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384")
+	assert.Nil(t, err)
+	assert.Equal(t, []uint16{tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384, tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384}, suites)
+	_, err = parseTLSCipherSuites("TLS_RSA_WITH_3DES_EDE_CBC_SHA")
+	assert.NotNil(t, err)
+
+	dsn, err := NewConnStringBuilder().Hosts(HostAddress{Host: "graphd", Port: DefaultPort}).TLS(TLSEnabled).
+		TLSMinVersion(tls.VersionTLS12).TLSCipherSuites(tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256).Build()
+	assert.Nil(t, err)
+	cfg, err := ParseConnectionString(dsn)
+	assert.Nil(t, err)
+	assert.Nil(t, cfg.Validate())
+	assert.Equal(t, uint16(tls.VersionTLS12), cfg.PoolConfig.TLSMinVersion)
+	assert.Equal(t, []uint16{tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256}, cfg.PoolConfig.TLSCipherSuites)
+
+	_, err = NewConnStringBuilder().Hosts(HostAddress{Host: "graphd", Port: DefaultPort}).TLSCipherSuites(tls.TLS_RSA_WITH_RC4_128_SHA).Build()
+	assert.NotNil(t, err)
+
+	conf := NewPoolConf(WithTLSMinVersion(tls.VersionTLS13), WithTLSCipherSuites(tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256))
+	assert.NotNil(t, conf.Validate())
+}
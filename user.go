@@ -0,0 +1,119 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Role is a built-in role of the graph service
+type Role string
+
+const (
+	RoleGod   Role = "GOD"
+	RoleAdmin Role = "ADMIN"
+	RoleDBA   Role = "DBA"
+	RoleUser  Role = "USER"
+	RoleGuest Role = "GUEST"
+)
+
+// RoleAssignment is a role granted to a user on a space
+type RoleAssignment struct {
+	Role  Role
+	Space string
+}
+
+// CreateUser creates a user with the given password, nothing is done if the user exists and ifNotExists is true
+func (session *Session) CreateUser(ctx context.Context, user, password string, ifNotExists bool) error {
+	stmt := "CREATE USER "
+	if ifNotExists {
+		stmt += "IF NOT EXISTS "
+	}
+	stmt += fmt.Sprintf("%s WITH PASSWORD %s", QuoteIdentifier(user), quoteString(password))
+	_, err := session.executeAdmin(ctx, stmt, "create user "+user)
+	return err
+}
+
+// DropUser drops a user, nothing is done if the user does not exist and ifExists is true
+func (session *Session) DropUser(ctx context.Context, user string, ifExists bool) error {
+	stmt := "DROP USER "
+	if ifExists {
+		stmt += "IF EXISTS "
+	}
+	_, err := session.executeAdmin(ctx, stmt+QuoteIdentifier(user), "drop user "+user)
+	return err
+}
+
+// ChangePassword changes the password of a user, the current password is required
+func (session *Session) ChangePassword(ctx context.Context, user, oldPassword, newPassword string) error {
+	_, err := session.executeAdmin(ctx, fmt.Sprintf("CHANGE PASSWORD %s FROM %s TO %s",
+		QuoteIdentifier(user), quoteString(oldPassword), quoteString(newPassword)), "change password of "+user)
+	return err
+}
+
+// validRole returns an error unless the role is one of the built-in roles
+func validRole(role Role) error {
+	switch role {
+	case RoleGod, RoleAdmin, RoleDBA, RoleUser, RoleGuest:
+		return nil
+	}
+	return fmt.Errorf("unknown role %q", string(role))
+}
+
+// GrantRole grants a role on a space to a user
+func (session *Session) GrantRole(ctx context.Context, role Role, space, user string) error {
+	if err := validRole(role); err != nil {
+		return fmt.Errorf("failed to grant role: %s", err.Error())
+	}
+	_, err := session.executeAdmin(ctx, fmt.Sprintf("GRANT ROLE %s ON %s TO %s",
+		role, QuoteIdentifier(space), QuoteIdentifier(user)), fmt.Sprintf("grant role %s to %s", role, user))
+	return err
+}
+
+// RevokeRole revokes a role on a space from a user
+func (session *Session) RevokeRole(ctx context.Context, role Role, space, user string) error {
+	if err := validRole(role); err != nil {
+		return fmt.Errorf("failed to revoke role: %s", err.Error())
+	}
+	_, err := session.executeAdmin(ctx, fmt.Sprintf("REVOKE ROLE %s ON %s FROM %s",
+		role, QuoteIdentifier(space), QuoteIdentifier(user)), fmt.Sprintf("revoke role %s from %s", role, user))
+	return err
+}
+
+// DescribeUser returns the roles granted to a user
+func (session *Session) DescribeUser(ctx context.Context, user string) ([]RoleAssignment, error) {
+	resp, err := session.executeAdmin(ctx, "DESCRIBE USER "+QuoteIdentifier(user), "describe user "+user)
+	if err != nil {
+		return nil, err
+	}
+	return parseRoleAssignments(resp)
+}
+
+func parseRoleAssignments(resp *ResultSet) ([]RoleAssignment, error) {
+	roles := make([]RoleAssignment, 0, resp.GetRowSize())
+	for i := 0; i < resp.GetRowSize(); i++ {
+		record, err := resp.GetRowValuesByIndex(i)
+		if err != nil {
+			return nil, err
+		}
+		var role string
+		var ra RoleAssignment
+		r := recordReader{record: record}
+		r.string("role", &role)
+		r.string("space", &ra.Space)
+		if r.err != nil {
+			return nil, fmt.Errorf("failed to describe user: %s", r.err.Error())
+		}
+		ra.Role = Role(strings.ToUpper(role))
+		roles = append(roles, ra)
+	}
+	return roles, nil
+}
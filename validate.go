@@ -0,0 +1,225 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ConfigError lists every problem found by Validate
+type ConfigError struct {
+	Problems []string
+}
+
+func (e *ConfigError) Error() string {
+	return fmt.Sprintf("invalid config, %d problem(s): %s", len(e.Problems), strings.Join(e.Problems, "; "))
+}
+
+// Validate checks the hosts, the TLS mode and the pool config, and returns a *ConfigError
+// listing every problem found, or nil if there is none
+func (cfg *ConnectionConfig) Validate() error {
+	var problems []string
+	if len(cfg.Hosts) == 0 {
+		problems = append(problems, "no host")
+	}
+	for _, host := range cfg.Hosts {
+		switch {
+		case host.IsUnixSocket():
+		case host.Host == "":
+			problems = append(problems, fmt.Sprintf("empty host name with port %d", host.Port))
+		case host.Port <= 0 || host.Port > 65535:
+			problems = append(problems, fmt.Sprintf("port %d of host %s is out of range, it must be between 1 and 65535",
+				host.Port, host.Host))
+		}
+	}
+	switch cfg.TLS {
+	case TLSDisabled, TLSSkipVerify, "":
+	case TLSEnabled:
+		if _, err := x509.SystemCertPool(); err != nil {
+			problems = append(problems, fmt.Sprintf("TLS is enabled but the system certificates can not be loaded: %s, "+
+				"use tls=skip-verify or install the CA certificates", err.Error()))
+		}
+	default:
+		problems = append(problems, fmt.Sprintf("unknown TLS mode %q, it must be %s, %s or %s",
+			cfg.TLS, TLSDisabled, TLSEnabled, TLSSkipVerify))
+	}
+	if len(cfg.PoolConfig.PinnedCertificates) > 0 && (cfg.TLS == TLSDisabled || cfg.TLS == "") {
+		problems = append(problems, "pinned certificates require TLS")
+	}
+	if (cfg.PoolConfig.TLSMinVersion != 0 || len(cfg.PoolConfig.TLSCipherSuites) > 0) && (cfg.TLS == TLSDisabled || cfg.TLS == "") {
+		problems = append(problems, "tls_min_version and tls_ciphers require TLS")
+	}
+	problems = append(problems, cfg.PoolConfig.problems()...)
+	if len(problems) > 0 {
+		return &ConfigError{Problems: problems}
+	}
+	return nil
+}
+
+// Validate checks the pool config and returns a *ConfigError listing every problem found, or nil if there is none.
+// Unlike the pool constructors, which replace the invalid values by their defaults, it reports them.
+func (conf PoolConfig) Validate() error {
+	if problems := conf.problems(); len(problems) > 0 {
+		return &ConfigError{Problems: problems}
+	}
+	return nil
+}
+
+func (conf PoolConfig) problems() []string {
+	var problems []string
+	add := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+	for _, d := range []struct {
+		name  string
+		value time.Duration
+	}{{"TimeOut", conf.TimeOut}, {"IdleTime", conf.IdleTime}, {"StarvationThreshold", conf.StarvationThreshold}} {
+		if d.value < 0 {
+			add("%s %s is negative", d.name, d.value)
+		} else if d.value > 0 && d.value < time.Millisecond {
+			add("%s %s is shorter than a millisecond, use a time.Duration such as %d * time.Second",
+				d.name, d.value, int64(d.value))
+		}
+	}
+	if conf.MaxConnPoolSize < 1 {
+		add("MaxConnPoolSize %d must be at least 1", conf.MaxConnPoolSize)
+	}
+	if conf.MinConnPoolSize < 0 {
+		add("MinConnPoolSize %d is negative", conf.MinConnPoolSize)
+	}
+	if conf.MaxConnPoolSize >= 1 && conf.MinConnPoolSize > conf.MaxConnPoolSize {
+		add("MinConnPoolSize %d is greater than MaxConnPoolSize %d", conf.MinConnPoolSize, conf.MaxConnPoolSize)
+	}
+	for workload, size := range conf.WorkloadPartitions {
+		if size <= 0 || size > conf.MaxConnPoolSize {
+			add("the partition of workload %s is %d, it must be between 1 and MaxConnPoolSize %d",
+				workload, size, conf.MaxConnPoolSize)
+		}
+	}
+	if conf.LatencyHistograms < 0 {
+		add("LatencyHistograms %d is negative", conf.LatencyHistograms)
+	}
+	if conf.BatchParallelism < 0 {
+		add("BatchParallelism %d is negative", conf.BatchParallelism)
+	}
+	if conf.QueryHistory < 0 {
+		add("QueryHistory %d is negative", conf.QueryHistory)
+	}
+	if conf.MemorySoftLimit < 0 {
+		add("MemorySoftLimit %d is negative", conf.MemorySoftLimit)
+	}
+	if _, ok := formatTLSVersion(conf.TLSMinVersion); !ok && conf.TLSMinVersion != 0 {
+		add("unknown TLSMinVersion %#x", conf.TLSMinVersion)
+	}
+	if conf.TLSMinVersion == tls.VersionTLS13 && len(conf.TLSCipherSuites) > 0 {
+		add("TLSCipherSuites do not apply to TLS 1.3, which is the TLSMinVersion")
+	}
+	for _, pin := range conf.PinnedCertificates {
+		if _, err := parsePin(pin); err != nil {
+			add("%s", err.Error())
+		}
+	}
+	for host, config := range conf.HostTLS {
+		if config != nil {
+			continue
+		}
+		if len(conf.PinnedCertificates) > 0 {
+			add("pinned certificates require TLS, which HostTLS disables for host %s:%d", host.Host, host.Port)
+		}
+		if conf.TLSMinVersion != 0 || len(conf.TLSCipherSuites) > 0 {
+			add("TLSMinVersion and TLSCipherSuites require TLS, which HostTLS disables for host %s:%d", host.Host, host.Port)
+		}
+	}
+	if conf.ValidateSpace && (conf.Space == "" || conf.ExplicitSpace) {
+		add("ValidateSpace requires a Space and can not be combined with ExplicitSpace")
+	}
+	if a := conf.Autoscale; a != nil {
+		if a.ScaleUpUtilization > 1 {
+			add("Autoscale.ScaleUpUtilization %g is greater than 1", a.ScaleUpUtilization)
+		}
+		if d := a.withDefaults(); d.ScaleDownUtilization >= d.ScaleUpUtilization {
+			add("Autoscale.ScaleDownUtilization %g must be lower than Autoscale.ScaleUpUtilization %g",
+				d.ScaleDownUtilization, d.ScaleUpUtilization)
+		}
+	}
+	if conf.WireDumpMaxBytes < 0 {
+		add("WireDumpMaxBytes %d is negative", conf.WireDumpMaxBytes)
+	}
+	if conf.ServerVersion != "" {
+		if _, err := ParseServerVersion(conf.ServerVersion); err != nil {
+			add("ServerVersion %q is invalid: %s", conf.ServerVersion, err.Error())
+		}
+	}
+	if conf.TimeZone != "" {
+		if _, err := loadTimeZone(conf.TimeZone); err != nil {
+			add("TimeZone %q is unknown: %s", conf.TimeZone, err.Error())
+		}
+	}
+	if strings.ContainsAny(conf.Space, "`\n\r\x00") {
+		add("Space %q contains a backquote or a control character", conf.Space)
+	}
+	if conf.Space != "" && conf.ExplicitSpace {
+		add("Space %q is ignored when ExplicitSpace is set", conf.Space)
+	}
+	if conf.PasswordProvider != nil && conf.Password != "" {
+		add("Password and PasswordProvider are both set")
+	}
+	if conf.UsernameProvider != nil && conf.Username != "" {
+		add("Username and UsernameProvider are both set")
+	}
+	if conf.Password != "" && !conf.hasUsername() {
+		add("Password is set without Username")
+	}
+	if (conf.AdaptivePoolSize || conf.KillOnCancel) && !conf.hasUsername() {
+		add("AdaptivePoolSize and KillOnCancel require the Username and Password of the pool config")
+	}
+	for i, stmt := range conf.OnConnectStmts {
+		if problem := statementProblem(stmt); problem != "" {
+			add("OnConnectStmts[%d] %s", i, problem)
+		}
+	}
+	return problems
+}
+
+// statementProblem returns why a statement can not be executed, or an empty string
+func statementProblem(stmt string) string {
+	tokens := lexStatement(stmt)
+	significant := false
+	for _, tok := range tokens {
+		if tok.kind == tokenSpace {
+			continue
+		}
+		significant = true
+		if (tok.kind == tokenString || tok.kind == tokenIdent) && !terminated(tok.text) {
+			return fmt.Sprintf("%q has an unterminated string or identifier", stmt)
+		}
+	}
+	if !significant {
+		return "is empty"
+	}
+	return ""
+}
+
+// terminated returns true if the quoted token ends with its closing quote
+func terminated(text string) bool {
+	quote := text[0]
+	for i := 1; i < len(text); i++ {
+		switch text[i] {
+		case '\\':
+			i++
+		case quote:
+			return i == len(text)-1
+		}
+	}
+	return false
+}
@@ -0,0 +1,141 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// ValueDecoder decodes a value of a result set into a value of a user type, e.g. a UUID from a fixed string
+// or an enum from an int. It is the counterpart of ValueEncoder.
+type ValueDecoder func(val *ValueWrapper) (interface{}, error)
+
+// the process-wide registry of the decoders of RegisterValueDecoder
+var valueDecoders = struct {
+	sync.RWMutex
+	decoders map[reflect.Type]ValueDecoder
+}{decoders: make(map[reflect.Type]ValueDecoder)}
+
+// RegisterValueDecoder registers the decoder of the type of sample, so that the values of a result set
+// can be scanned into the type with ResultDecoder.Scan, DecodeValue or the ogm package.
+// The decoder must return a value of the type. A nil decoder removes the registration.
+func RegisterValueDecoder(sample interface{}, dec ValueDecoder) {
+	t := reflect.TypeOf(sample)
+	valueDecoders.Lock()
+	defer valueDecoders.Unlock()
+	if dec == nil {
+		delete(valueDecoders.decoders, t)
+		return
+	}
+	valueDecoders.decoders[t] = dec
+}
+
+// HasValueDecoder returns true if a decoder is registered for the type
+func HasValueDecoder(t reflect.Type) bool {
+	valueDecoders.RLock()
+	defer valueDecoders.RUnlock()
+	_, ok := valueDecoders.decoders[t]
+	return ok
+}
+
+// DecodeValue decodes the value into dst, a pointer to a type registered with RegisterValueDecoder
+func DecodeValue(val *ValueWrapper, dst interface{}) error {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("failed to decode value: %T is not a non-nil pointer", dst)
+	}
+	valueDecoders.RLock()
+	dec, ok := valueDecoders.decoders[rv.Elem().Type()]
+	valueDecoders.RUnlock()
+	if !ok {
+		return fmt.Errorf("failed to decode value: no decoder registered for %s", rv.Elem().Type())
+	}
+	decoded, err := dec(val)
+	if err != nil {
+		return fmt.Errorf("failed to decode value into %s: %s", rv.Elem().Type(), err.Error())
+	}
+	dv := reflect.ValueOf(decoded)
+	if !dv.IsValid() || dv.Type() != rv.Elem().Type() {
+		return fmt.Errorf("failed to decode value into %s: the decoder returned %T", rv.Elem().Type(), decoded)
+	}
+	rv.Elem().Set(dv)
+	return nil
+}
+
+// Scan decodes the value of the column in the row into dst, a pointer to a type registered with
+// RegisterValueDecoder, a bool, a string, an integer, a float, or a pointer to one of them which is set to nil
+// if the value is null. Basic types are converted like Int, Float, String and Bool, according to the mode.
+func (d *ResultDecoder) Scan(row int, col string, dst interface{}) error {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("failed to scan column %s of row %d: %T is not a non-nil pointer", col, row, dst)
+	}
+	return d.scan(row, col, rv.Elem())
+}
+
+func (d *ResultDecoder) scan(row int, col string, f reflect.Value) error {
+	if HasValueDecoder(f.Type()) {
+		v, err := d.value(row, col)
+		if err != nil {
+			return err
+		}
+		if err = DecodeValue(v, f.Addr().Interface()); err != nil {
+			return d.conversionError(f.Type().String(), row, col, v)
+		}
+		return nil
+	}
+	switch f.Kind() {
+	case reflect.Ptr:
+		v, err := d.value(row, col)
+		if err != nil {
+			return err
+		}
+		if v.IsNull() || v.IsEmpty() {
+			f.Set(reflect.Zero(f.Type()))
+			return nil
+		}
+		ptr := reflect.New(f.Type().Elem())
+		if err = d.scan(row, col, ptr.Elem()); err != nil {
+			return err
+		}
+		f.Set(ptr)
+	case reflect.Bool:
+		b, err := d.Bool(row, col)
+		if err != nil {
+			return err
+		}
+		f.SetBool(b)
+	case reflect.String:
+		s, err := d.String(row, col)
+		if err != nil {
+			return err
+		}
+		f.SetString(s)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := d.Int(row, col)
+		if err != nil {
+			return err
+		}
+		if f.OverflowInt(i) {
+			return fmt.Errorf("failed to scan column %s of row %d: value %d overflows %s", col, row, i, f.Type())
+		}
+		f.SetInt(i)
+	case reflect.Float32, reflect.Float64:
+		fl, err := d.Float(row, col)
+		if err != nil {
+			return err
+		}
+		f.SetFloat(fl)
+	default:
+		return fmt.Errorf("failed to scan column %s of row %d: unsupported type %s", col, row, f.Type())
+	}
+	return nil
+}
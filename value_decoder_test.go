@@ -0,0 +1,77 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"encoding/hex"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValueDecoder(t *testing.T) {
+	RegisterValueDecoder(testUUID{}, func(val *ValueWrapper) (interface{}, error) {
+		s, err := val.AsString()
+		if err != nil {
+			return nil, err
+		}
+		var id testUUID
+		b, err := hex.DecodeString(s)
+		if err != nil || len(b) != len(id) {
+			return nil, fmt.Errorf("invalid uuid %s", s)
+		}
+		copy(id[:], b)
+		return id, nil
+	})
+	RegisterValueDecoder(testEnum(0), func(val *ValueWrapper) (interface{}, error) {
+		i, err := val.AsInt()
+		return testEnum(i), err
+	})
+	defer RegisterValueDecoder(testUUID{}, nil)
+	defer RegisterValueDecoder(testEnum(0), nil)
+
+	rs := newTestResultSet(t, []string{"id", "kind", "name", "score"},
+		[]interface{}{"01020304", 2, "Tim", 1},
+		[]interface{}{"xyz", nil, nil, 300},
+	)
+	d := rs.Decoder(DecodeLenient)
+	var id testUUID
+	var kind *testEnum
+	var name *string
+	var score float32
+	assert.Nil(t, d.Scan(0, "id", &id))
+	assert.Equal(t, testUUID{1, 2, 3, 4}, id)
+	assert.Nil(t, d.Scan(0, "kind", &kind))
+	assert.Equal(t, testEnum(2), *kind)
+	assert.Nil(t, d.Scan(0, "name", &name))
+	assert.Equal(t, "Tim", *name)
+	assert.Nil(t, d.Scan(0, "score", &score))
+	assert.Equal(t, float32(1), score)
+
+	assert.IsType(t, &ConversionError{}, d.Scan(1, "id", &id))
+	assert.Nil(t, d.Scan(1, "kind", &kind))
+	assert.Nil(t, kind)
+	assert.Nil(t, d.Scan(1, "name", &name))
+	assert.Nil(t, name)
+	var small int8
+	assert.NotNil(t, d.Scan(1, "score", &small))
+	assert.NotNil(t, d.Scan(0, "id", id))
+	assert.NotNil(t, d.Scan(0, "id", &[]int{}))
+
+	record, err := rs.GetRowValuesByIndex(0)
+	assert.Nil(t, err)
+	val, err := record.GetValueByColName("kind")
+	assert.Nil(t, err)
+	var e testEnum
+	assert.Nil(t, DecodeValue(val, &e))
+	assert.Equal(t, testEnum(2), e)
+	var other int
+	assert.NotNil(t, DecodeValue(val, &other))
+}
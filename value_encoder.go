@@ -0,0 +1,67 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// ValueEncoder encodes a value of a user type, e.g. a UUID or a decimal, into a value supported
+// by the parameters and the literals: nil, a boolean, an integer, a float, a string, a VID,
+// a []interface{}, a map[string]interface{} or a value of the nebula package.
+type ValueEncoder func(v interface{}) (interface{}, error)
+
+// the process-wide registry of the encoders of RegisterValueEncoder
+var valueEncoders = struct {
+	sync.RWMutex
+	encoders map[reflect.Type]ValueEncoder
+}{encoders: make(map[reflect.Type]ValueEncoder)}
+
+// RegisterValueEncoder registers the encoder of the type of sample, so that the values of the type are encoded
+// when they are passed as parameters, formatted with FormatValue or mapped by the ogm package.
+// A nil encoder removes the registration. Encoders should be registered before the pools are used.
+func RegisterValueEncoder(sample interface{}, enc ValueEncoder) {
+	t := reflect.TypeOf(sample)
+	valueEncoders.Lock()
+	defer valueEncoders.Unlock()
+	if enc == nil {
+		delete(valueEncoders.encoders, t)
+		return
+	}
+	valueEncoders.encoders[t] = enc
+}
+
+// HasValueEncoder returns true if an encoder is registered for the type
+func HasValueEncoder(t reflect.Type) bool {
+	valueEncoders.RLock()
+	defer valueEncoders.RUnlock()
+	_, ok := valueEncoders.encoders[t]
+	return ok
+}
+
+// encodeValue encodes v with the encoder registered for its type, it returns false if there is none
+func encodeValue(v interface{}) (interface{}, bool, error) {
+	t := reflect.TypeOf(v)
+	valueEncoders.RLock()
+	enc, ok := valueEncoders.encoders[t]
+	valueEncoders.RUnlock()
+	if !ok {
+		return nil, false, nil
+	}
+	encoded, err := enc(v)
+	if err != nil {
+		return nil, true, fmt.Errorf("failed to encode value of type %T: %s", v, err.Error())
+	}
+	if encoded != nil && reflect.TypeOf(encoded) == t {
+		return nil, true, fmt.Errorf("failed to encode value of type %T: the encoder returned the same type", v)
+	}
+	return encoded, true, nil
+}
@@ -0,0 +1,60 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testUUID [4]byte
+
+type testEnum int
+
+func TestValueEncoder(t *testing.T) {
+	_, err := FormatValue(testUUID{1, 2, 3, 4})
+	assert.NotNil(t, err)
+
+	RegisterValueEncoder(testUUID{}, func(v interface{}) (interface{}, error) {
+		id := v.(testUUID)
+		return hex.EncodeToString(id[:]), nil
+	})
+	RegisterValueEncoder(testEnum(0), func(v interface{}) (interface{}, error) {
+		if v.(testEnum) < 0 {
+			return nil, fmt.Errorf("invalid enum %d", v)
+		}
+		return []interface{}{"enum", int(v.(testEnum))}, nil
+	})
+	defer RegisterValueEncoder(testUUID{}, nil)
+	defer RegisterValueEncoder(testEnum(0), nil)
+	assert.True(t, HasValueEncoder(reflect.TypeOf(testUUID{})))
+
+	lit, err := FormatValue(testUUID{1, 2, 3, 4})
+	assert.Nil(t, err)
+	assert.Equal(t, `"01020304"`, lit)
+
+	val, err := value2Nvalue(map[string]interface{}{"id": testUUID{0xff}, "kind": testEnum(2)})
+	assert.Nil(t, err)
+	assert.Equal(t, "ff000000", string(val.MVal.Kvs["id"].SVal))
+	assert.Equal(t, int64(2), *val.MVal.Kvs["kind"].LVal.Values[1].IVal)
+
+	_, err = value2Nvalue(testEnum(-1))
+	assert.NotNil(t, err)
+
+	RegisterValueEncoder(testUUID{}, func(v interface{}) (interface{}, error) { return v, nil })
+	_, err = FormatValue(testUUID{})
+	assert.NotNil(t, err)
+
+	RegisterValueEncoder(testUUID{}, nil)
+	assert.False(t, HasValueEncoder(reflect.TypeOf(testUUID{})))
+}
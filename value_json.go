@@ -0,0 +1,253 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+)
+
+// The range of the integers which can be represented exactly by the numbers of JavaScript
+const (
+	MaxSafeInteger = 1<<53 - 1
+	MinSafeInteger = -MaxSafeInteger
+)
+
+// JSONOptions are the options of the JSON encoding of values and records
+type JSONOptions struct {
+	// Encode the integers beyond MinSafeInteger and MaxSafeInteger as strings,
+	// so that JavaScript clients do not round them, e.g. large int64 VIDs
+	LargeIntsAsStrings bool
+	// Replaces the properties of the vertices and edges, e.g. to redact the PII properties
+	Masker FieldMasker
+}
+
+// AsInt32 converts the ValueWrapper to an int32, it fails if the int does not fit
+func (valWrap ValueWrapper) AsInt32() (int32, error) {
+	i, err := valWrap.AsInt()
+	if err != nil {
+		return -1, err
+	}
+	if i < math.MinInt32 || i > math.MaxInt32 {
+		return -1, fmt.Errorf("failed to convert value %d to int32: out of range", i)
+	}
+	return int32(i), nil
+}
+
+// AsSafeInt converts the ValueWrapper to an int64, it fails if the int can not be represented
+// exactly by a float64, e.g. a number of JavaScript
+func (valWrap ValueWrapper) AsSafeInt() (int64, error) {
+	i, err := valWrap.AsInt()
+	if err != nil {
+		return -1, err
+	}
+	if !isSafeInteger(i) {
+		return -1, fmt.Errorf("failed to convert value %d to safe int: out of range", i)
+	}
+	return i, nil
+}
+
+// AsBigInt converts the ValueWrapper to a big.Int
+func (valWrap ValueWrapper) AsBigInt() (*big.Int, error) {
+	i, err := valWrap.AsInt()
+	if err != nil {
+		return nil, err
+	}
+	return big.NewInt(i), nil
+}
+
+func isSafeInteger(i int64) bool {
+	return i >= MinSafeInteger && i <= MaxSafeInteger
+}
+
+// MarshalJSON encodes the value with the default JSONOptions
+func (valWrap ValueWrapper) MarshalJSON() ([]byte, error) {
+	return valWrap.MarshalJSONWithOptions(JSONOptions{})
+}
+
+// MarshalJSONWithOptions encodes the value as JSON. Lists and sets are encoded as arrays, maps as objects,
+// vertices, edges and paths as objects of their ids and properties, and dates, times, datetimes,
+// durations and geographies as their string representation.
+func (valWrap ValueWrapper) MarshalJSONWithOptions(opts JSONOptions) ([]byte, error) {
+	v, err := valWrap.jsonValue(opts)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+// MarshalJSON encodes the record as an object keyed by column name with the default JSONOptions
+func (record Record) MarshalJSON() ([]byte, error) {
+	return record.MarshalJSONWithOptions(JSONOptions{})
+}
+
+// MarshalJSONWithOptions encodes the record as an object keyed by column name
+func (record Record) MarshalJSONWithOptions(opts JSONOptions) ([]byte, error) {
+	obj := make(map[string]interface{}, len(record._record))
+	for i, name := range *record.columnNames {
+		v, err := record._record[i].jsonValue(opts)
+		if err != nil {
+			return nil, err
+		}
+		obj[name] = v
+	}
+	return json.Marshal(obj)
+}
+
+// jsonValue converts the value to the go value encoded as JSON
+func (valWrap ValueWrapper) jsonValue(opts JSONOptions) (interface{}, error) {
+	value := valWrap.value
+	switch {
+	case value == nil || valWrap.IsEmpty() || valWrap.IsNull():
+		return nil, nil
+	case value.IsSetBVal():
+		return value.GetBVal(), nil
+	case value.IsSetIVal():
+		i := value.GetIVal()
+		if opts.LargeIntsAsStrings && !isSafeInteger(i) {
+			return strconv.FormatInt(i, 10), nil
+		}
+		return i, nil
+	case value.IsSetFVal():
+		f := value.GetFVal()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return nil, fmt.Errorf("failed to encode value %v as JSON: unsupported float", f)
+		}
+		return f, nil
+	case value.IsSetSVal():
+		return string(value.GetSVal()), nil
+	case value.IsSetLVal(), value.IsSetUVal():
+		list, err := valWrap.AsList()
+		if valWrap.IsSet() {
+			list, err = valWrap.AsDedupList()
+		}
+		if err != nil {
+			return nil, err
+		}
+		return jsonValues(list, opts)
+	case value.IsSetMVal():
+		m, err := valWrap.AsMap()
+		if err != nil {
+			return nil, err
+		}
+		obj := make(map[string]interface{}, len(m))
+		for k, v := range m {
+			if obj[k], err = v.jsonValue(opts); err != nil {
+				return nil, err
+			}
+		}
+		return obj, nil
+	case value.IsSetVVal():
+		node, err := valWrap.AsNode()
+		if err != nil {
+			return nil, err
+		}
+		return nodeJSONValue(node, opts)
+	case value.IsSetEVal():
+		relationship, err := valWrap.AsRelationship()
+		if err != nil {
+			return nil, err
+		}
+		return relationshipJSONValue(relationship, opts)
+	case value.IsSetPVal():
+		path, err := valWrap.AsPath()
+		if err != nil {
+			return nil, err
+		}
+		nodes := make([]interface{}, 0, len(path.GetNodes()))
+		for _, node := range path.GetNodes() {
+			v, err := nodeJSONValue(node, opts)
+			if err != nil {
+				return nil, err
+			}
+			nodes = append(nodes, v)
+		}
+		relationships := make([]interface{}, 0, len(path.GetRelationships()))
+		for _, relationship := range path.GetRelationships() {
+			v, err := relationshipJSONValue(relationship, opts)
+			if err != nil {
+				return nil, err
+			}
+			relationships = append(relationships, v)
+		}
+		return map[string]interface{}{"nodes": nodes, "relationships": relationships}, nil
+	default:
+		return valWrap.String(), nil
+	}
+}
+
+func jsonValues(list []ValueWrapper, opts JSONOptions) ([]interface{}, error) {
+	values := make([]interface{}, len(list))
+	for i, v := range list {
+		var err error
+		if values[i], err = v.jsonValue(opts); err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
+
+// jsonProps encodes the properties of a vertex tag or an edge, field is the tag or the edge type
+func jsonProps(field MaskedField, props map[string]*ValueWrapper, opts JSONOptions) (map[string]interface{}, error) {
+	obj := make(map[string]interface{}, len(props))
+	for k, v := range props {
+		field.Prop = k
+		v, err := opts.Masker.Mask(field, v)
+		if err != nil {
+			return nil, err
+		}
+		if obj[k], err = v.jsonValue(opts); err != nil {
+			return nil, err
+		}
+	}
+	return obj, nil
+}
+
+func nodeJSONValue(node *Node, opts JSONOptions) (interface{}, error) {
+	vid, err := node.GetID().jsonValue(opts)
+	if err != nil {
+		return nil, err
+	}
+	tags := make(map[string]interface{}, len(node.GetTags()))
+	for _, tag := range node.GetTags() {
+		props, err := node.Properties(tag)
+		if err != nil {
+			return nil, err
+		}
+		if tags[tag], err = jsonProps(MaskedField{Tag: tag}, props, opts); err != nil {
+			return nil, err
+		}
+	}
+	return map[string]interface{}{"vid": vid, "tags": tags}, nil
+}
+
+func relationshipJSONValue(relationship *Relationship, opts JSONOptions) (interface{}, error) {
+	src, err := relationship.GetSrcVertexID().jsonValue(opts)
+	if err != nil {
+		return nil, err
+	}
+	dst, err := relationship.GetDstVertexID().jsonValue(opts)
+	if err != nil {
+		return nil, err
+	}
+	props, err := jsonProps(MaskedField{Edge: relationship.GetEdgeName()}, relationship.Properties(), opts)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"src":     src,
+		"dst":     dst,
+		"name":    relationship.GetEdgeName(),
+		"ranking": relationship.GetRanking(),
+		"props":   props,
+	}, nil
+}
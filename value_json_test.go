@@ -0,0 +1,68 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vesoft-inc/nebula-go/v3/nebula"
+)
+
+func TestOverflowCheckedAccessors(t *testing.T) {
+	small := ValueWrapper{setIVal(42), testTimezone}
+	large := ValueWrapper{setIVal(math.MaxInt64), testTimezone}
+
+	i32, err := small.AsInt32()
+	assert.Nil(t, err)
+	assert.Equal(t, int32(42), i32)
+	_, err = large.AsInt32()
+	assert.NotNil(t, err)
+
+	_, err = large.AsSafeInt()
+	assert.NotNil(t, err)
+	i, err := ValueWrapper{setIVal(MaxSafeInteger), testTimezone}.AsSafeInt()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(MaxSafeInteger), i)
+
+	b, err := large.AsBigInt()
+	assert.Nil(t, err)
+	assert.Equal(t, "9223372036854775807", b.String())
+}
+
+func TestValueMarshalJSON(t *testing.T) {
+	resp := newTestResultSet(t, []string{"vid", "name", "scores", "props", "none"},
+		[]interface{}{math.MaxInt64, "Bob", []interface{}{1, 2.5}, map[string]interface{}{"safe": MaxSafeInteger}, nil})
+	record, err := resp.GetRowValuesByIndex(0)
+	assert.Nil(t, err)
+
+	b, err := json.Marshal(record)
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"vid":9223372036854775807,"name":"Bob","scores":[1,2.5],"props":{"safe":9007199254740991},"none":null}`, string(b))
+
+	b, err = record.MarshalJSONWithOptions(JSONOptions{LargeIntsAsStrings: true})
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"vid":"9223372036854775807","name":"Bob","scores":[1,2.5],"props":{"safe":9007199254740991},"none":null}`, string(b))
+
+	vertex := ValueWrapper{&nebula.Value{VVal: getVertexInt(math.MaxInt64, 1, 1)}, testTimezone}
+	b, err = vertex.MarshalJSONWithOptions(JSONOptions{LargeIntsAsStrings: true})
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"vid":"9223372036854775807","tags":{"tag0":{"prop0":0}}}`, string(b))
+
+	edge := ValueWrapper{&nebula.Value{EVal: getEdge("Alice", "Bob", 1)}, testTimezone}
+	b, err = json.Marshal(edge)
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"src":"Alice","dst":"Bob","name":"classmate","ranking":100,"props":{"prop0":0}}`, string(b))
+
+	nan := math.NaN()
+	_, err = json.Marshal(ValueWrapper{&nebula.Value{FVal: &nan}, testTimezone})
+	assert.NotNil(t, err)
+}
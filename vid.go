@@ -0,0 +1,180 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// VIDType is the type of the vertex ID of a graph space
+type VIDType int
+
+const (
+	// VIDTypeUnknown means the vid type of the space has not been resolved
+	VIDTypeUnknown VIDType = iota
+	// VIDTypeInt64 is the vid type of spaces created with vid_type = INT64
+	VIDTypeInt64
+	// VIDTypeFixedString is the vid type of spaces created with vid_type = FIXED_STRING(N)
+	VIDTypeFixedString
+)
+
+func (t VIDType) String() string {
+	switch t {
+	case VIDTypeInt64:
+		return "INT64"
+	case VIDTypeFixedString:
+		return "FIXED_STRING"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// ParseVIDType parses the vid type shown by DESCRIBE SPACE, e.g. "INT64" or "FIXED_STRING(32)"
+func ParseVIDType(s string) (VIDType, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	switch {
+	case s == "INT64" || s == "INT":
+		return VIDTypeInt64, nil
+	case strings.HasPrefix(s, "FIXED_STRING"):
+		return VIDTypeFixedString, nil
+	default:
+		return VIDTypeUnknown, fmt.Errorf("unknown vid type: %s", s)
+	}
+}
+
+// VID is a vertex ID which can be interpolated into a statement
+// for spaces of both INT64 and FIXED_STRING vid types.
+// VID is comparable and can be used as a map key.
+type VID struct {
+	isInt  bool
+	intVal int64
+	strVal string
+}
+
+// IntVID returns a VID holding an integer vertex ID
+func IntVID(id int64) VID {
+	return VID{isInt: true, intVal: id}
+}
+
+// StringVID returns a VID holding a string vertex ID
+func StringVID(id string) VID {
+	return VID{strVal: id}
+}
+
+// IsInt returns true if the VID was constructed from an integer
+func (vid VID) IsInt() bool {
+	return vid.isInt
+}
+
+// Int returns the integer value of the VID.
+// String VIDs are parsed, and an error is returned if they are not valid integers.
+func (vid VID) Int() (int64, error) {
+	if vid.isInt {
+		return vid.intVal, nil
+	}
+	i, err := strconv.ParseInt(vid.strVal, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to convert vid %q to int64: %s", vid.strVal, err.Error())
+	}
+	return i, nil
+}
+
+// Raw returns the VID as an unquoted string
+func (vid VID) Raw() string {
+	if vid.isInt {
+		return strconv.FormatInt(vid.intVal, 10)
+	}
+	return vid.strVal
+}
+
+// String returns the VID formatted as a literal using its own type:
+// integers are unquoted and strings are quoted and escaped.
+func (vid VID) String() string {
+	if vid.isInt {
+		return vid.Raw()
+	}
+	return quoteString(vid.strVal)
+}
+
+// Format returns the VID formatted as a literal for a space of the given vid type.
+// Integer VIDs are quoted for FIXED_STRING spaces, string VIDs are converted for INT64
+// spaces and an error is returned if they do not hold a valid integer.
+func (vid VID) Format(vidType VIDType) (string, error) {
+	switch vidType {
+	case VIDTypeInt64:
+		i, err := vid.Int()
+		if err != nil {
+			return "", err
+		}
+		return strconv.FormatInt(i, 10), nil
+	case VIDTypeFixedString:
+		return quoteString(vid.Raw()), nil
+	default:
+		return "", fmt.Errorf("failed to format vid %s: unknown vid type", vid.String())
+	}
+}
+
+// FormatVIDs formats the given VIDs as a comma separated list for a space of the given vid type
+func FormatVIDs(vidType VIDType, vids ...VID) (string, error) {
+	formatted := make([]string, 0, len(vids))
+	for _, vid := range vids {
+		s, err := vid.Format(vidType)
+		if err != nil {
+			return "", err
+		}
+		formatted = append(formatted, s)
+	}
+	return strings.Join(formatted, ", "), nil
+}
+
+// AsVID converts the value to a VID, the value must be an int or a string
+func (valWrap ValueWrapper) AsVID() (VID, error) {
+	if valWrap.value.IsSetIVal() {
+		return IntVID(valWrap.value.GetIVal()), nil
+	}
+	if valWrap.value.IsSetSVal() {
+		return StringVID(string(valWrap.value.GetSVal())), nil
+	}
+	return VID{}, fmt.Errorf("failed to convert value %s to VID", valWrap.GetType())
+}
+
+// GetVIDType returns the vid type of the given space using DESCRIBE SPACE, or the schema cache of the pool
+func (session *Session) GetVIDType(space string) (VIDType, error) {
+	vidType, err := session.connPool.cachedSchema(schemaCacheKey{space: space, kind: "vid type"}, func() (interface{}, error) {
+		return session.describeVIDType(space)
+	})
+	if err != nil {
+		return VIDTypeUnknown, err
+	}
+	return vidType.(VIDType), nil
+}
+
+func (session *Session) describeVIDType(space string) (VIDType, error) {
+	resp, err := session.Execute(fmt.Sprintf("DESCRIBE SPACE %s", QuoteIdentifier(space)))
+	if err != nil {
+		return VIDTypeUnknown, err
+	}
+	if !resp.IsSucceed() {
+		return VIDTypeUnknown, fmt.Errorf("failed to describe space %s: %s", space, resp.GetErrorMsg())
+	}
+	vals, err := resp.GetValuesByColName("Vid Type")
+	if err != nil {
+		return VIDTypeUnknown, err
+	}
+	if len(vals) == 0 {
+		return VIDTypeUnknown, fmt.Errorf("failed to describe space %s: empty result", space)
+	}
+	s, err := vals[0].AsString()
+	if err != nil {
+		return VIDTypeUnknown, err
+	}
+	return ParseVIDType(s)
+}
@@ -0,0 +1,72 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vesoft-inc/nebula-go/v3/nebula"
+)
+
+func TestParseVIDType(t *testing.T) {
+	vidType, err := ParseVIDType("INT64")
+	assert.Nil(t, err)
+	assert.Equal(t, VIDTypeInt64, vidType)
+
+	vidType, err = ParseVIDType("FIXED_STRING(32)")
+	assert.Nil(t, err)
+	assert.Equal(t, VIDTypeFixedString, vidType)
+
+	_, err = ParseVIDType("BOOL")
+	assert.NotNil(t, err)
+}
+
+func TestVIDFormat(t *testing.T) {
+	s, err := IntVID(42).Format(VIDTypeInt64)
+	assert.Nil(t, err)
+	assert.Equal(t, "42", s)
+
+	s, err = IntVID(42).Format(VIDTypeFixedString)
+	assert.Nil(t, err)
+	assert.Equal(t, `"42"`, s)
+
+	s, err = StringVID("42").Format(VIDTypeInt64)
+	assert.Nil(t, err)
+	assert.Equal(t, "42", s)
+
+	_, err = StringVID("Tim").Format(VIDTypeInt64)
+	assert.NotNil(t, err)
+
+	s, err = StringVID(`Tim "Duncan"`).Format(VIDTypeFixedString)
+	assert.Nil(t, err)
+	assert.Equal(t, `"Tim \"Duncan\""`, s)
+
+	_, err = IntVID(1).Format(VIDTypeUnknown)
+	assert.NotNil(t, err)
+
+	s, err = FormatVIDs(VIDTypeFixedString, IntVID(1), StringVID("a"))
+	assert.Nil(t, err)
+	assert.Equal(t, `"1", "a"`, s)
+}
+
+func TestAsVID(t *testing.T) {
+	i := int64(7)
+	vid, err := ValueWrapper{&nebula.Value{IVal: &i}, testTimezone}.AsVID()
+	assert.Nil(t, err)
+	assert.Equal(t, IntVID(7), vid)
+
+	vid, err = ValueWrapper{&nebula.Value{SVal: []byte("Bob")}, testTimezone}.AsVID()
+	assert.Nil(t, err)
+	assert.Equal(t, StringVID("Bob"), vid)
+
+	b := true
+	_, err = ValueWrapper{&nebula.Value{BVal: &b}, testTimezone}.AsVID()
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,162 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/facebook/fbthrift/thrift/lib/go/thrift"
+)
+
+// DefaultWireDumpMaxBytes is the default number of bytes of a frame dumped by the wire dump
+const DefaultWireDumpMaxBytes = 4096
+
+// wireDumper writes the bytes exchanged with the graph service to a writer or a logger
+type wireDumper struct {
+	mu       sync.Mutex
+	writer   io.Writer
+	log      Logger
+	maxBytes int
+}
+
+func newWireDumper(conf PoolConfig, log Logger) *wireDumper {
+	if !conf.WireDump {
+		return nil
+	}
+	maxBytes := conf.WireDumpMaxBytes
+	if maxBytes <= 0 {
+		maxBytes = DefaultWireDumpMaxBytes
+	}
+	return &wireDumper{writer: conf.WireDumpWriter, log: log, maxBytes: maxBytes}
+}
+
+// dump writes a hex dump of the data sent to or received from the given address,
+// preceded by the decoded thrift frame header if the data starts with one.
+// size is the total size of the data, which may be larger than data when only its beginning was kept.
+func (d *wireDumper) dump(direction string, address HostAddress, data []byte, size int) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "wire %s %s:%d, %d bytes", direction, address.Host, address.Port, size)
+	if header := decodeFrameHeader(data); header != "" {
+		fmt.Fprintf(&b, ", %s", header)
+	}
+	b.WriteString("\n")
+	if frameMethod(data) == "authenticate" {
+		// the request holds the username and the password
+		b.WriteString("payload redacted\n")
+	} else if size > d.maxBytes {
+		b.WriteString(hex.Dump(data[:d.maxBytes]))
+		fmt.Fprintf(&b, "... %d bytes truncated\n", size-d.maxBytes)
+	} else {
+		b.WriteString(hex.Dump(data))
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.writer != nil {
+		io.WriteString(d.writer, b.String())
+		return
+	}
+	d.log.Info(b.String())
+}
+
+// decodeFrameHeader decodes the frame size and the binary protocol message header
+// at the beginning of data, it returns an empty string if data is not a frame.
+func decodeFrameHeader(data []byte) string {
+	if len(data) < 8 {
+		return ""
+	}
+	frameSize := binary.BigEndian.Uint32(data[:4])
+	version := binary.BigEndian.Uint32(data[4:8])
+	if version&thrift.VERSION_MASK != thrift.VERSION_1 {
+		return fmt.Sprintf("frame size: %d", frameSize)
+	}
+	msgType := messageTypeName(thrift.MessageType(version & 0xff))
+	if len(data) < 12 {
+		return fmt.Sprintf("frame size: %d, message type: %s", frameSize, msgType)
+	}
+	nameLen := int(binary.BigEndian.Uint32(data[8:12]))
+	if nameLen < 0 || len(data) < 12+nameLen+4 {
+		return fmt.Sprintf("frame size: %d, message type: %s", frameSize, msgType)
+	}
+	name := data[12 : 12+nameLen]
+	seqID := int32(binary.BigEndian.Uint32(data[12+nameLen : 16+nameLen]))
+	return fmt.Sprintf("frame size: %d, message type: %s, method: %s, seqid: %d", frameSize, msgType, name, seqID)
+}
+
+// frameMethod returns the method of the binary protocol message framed at the beginning of data,
+// it returns an empty string if data is not such a frame.
+func frameMethod(data []byte) string {
+	if len(data) < 12 || binary.BigEndian.Uint32(data[4:8])&thrift.VERSION_MASK != thrift.VERSION_1 {
+		return ""
+	}
+	nameLen := int(binary.BigEndian.Uint32(data[8:12]))
+	if nameLen < 0 || len(data) < 12+nameLen {
+		return ""
+	}
+	return string(data[12 : 12+nameLen])
+}
+
+func messageTypeName(t thrift.MessageType) string {
+	switch t {
+	case thrift.CALL:
+		return "CALL"
+	case thrift.REPLY:
+		return "REPLY"
+	case thrift.EXCEPTION:
+		return "EXCEPTION"
+	case thrift.ONEWAY:
+		return "ONEWAY"
+	default:
+		return fmt.Sprintf("UNKNOWN(%d)", t)
+	}
+}
+
+// wireDumpTransport dumps the frames flushed to and the chunks read from the underlying transport
+type wireDumpTransport struct {
+	thrift.Transport
+	dumper  *wireDumper
+	address HostAddress
+	// the beginning of the data written since the last flush, capped to the max dump size
+	wbuf  bytes.Buffer
+	wsize int
+}
+
+func (t *wireDumpTransport) Write(p []byte) (int, error) {
+	if remaining := t.dumper.maxBytes - t.wbuf.Len(); remaining > 0 {
+		if remaining > len(p) {
+			remaining = len(p)
+		}
+		t.wbuf.Write(p[:remaining])
+	}
+	t.wsize += len(p)
+	return t.Transport.Write(p)
+}
+
+func (t *wireDumpTransport) Flush() error {
+	if t.wsize > 0 {
+		t.dumper.dump("send to", t.address, t.wbuf.Bytes(), t.wsize)
+		t.wbuf.Reset()
+		t.wsize = 0
+	}
+	return t.Transport.Flush()
+}
+
+func (t *wireDumpTransport) Read(p []byte) (int, error) {
+	n, err := t.Transport.Read(p)
+	if n > 0 {
+		t.dumper.dump("receive from", t.address, p[:n], n)
+	}
+	return n, err
+}
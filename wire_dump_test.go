@@ -0,0 +1,69 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/facebook/fbthrift/thrift/lib/go/thrift"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWireDump(t *testing.T) {
+	var out bytes.Buffer
+	conf := NewPoolConf(WithWireDump(&out, 32))
+	dumper := newWireDumper(conf, DefaultLogger{})
+
+	mem := thrift.NewMemoryBuffer()
+	transport := &wireDumpTransport{
+		Transport: mem,
+		dumper:    dumper,
+		address:   HostAddress{"127.0.0.1", 9669},
+	}
+	framed := thrift.NewFramedTransport(transport)
+	proto := thrift.NewBinaryProtocol(framed, false, true)
+	assert.Nil(t, proto.WriteMessageBegin("execute", thrift.CALL, 7))
+	assert.Nil(t, proto.WriteString(strings.Repeat("x", 64)))
+	assert.Nil(t, proto.WriteMessageEnd())
+	assert.Nil(t, proto.Flush())
+
+	dump := out.String()
+	assert.Contains(t, dump, "wire send to 127.0.0.1:9669")
+	assert.Contains(t, dump, "message type: CALL, method: execute, seqid: 7")
+	assert.Contains(t, dump, "bytes truncated")
+}
+
+func TestWireDumpRedactsAuthenticate(t *testing.T) {
+	var out bytes.Buffer
+	conf := NewPoolConf(WithWireDump(&out, 0))
+	transport := &wireDumpTransport{
+		Transport: thrift.NewMemoryBuffer(),
+		dumper:    newWireDumper(conf, DefaultLogger{}),
+		address:   HostAddress{"127.0.0.1", 9669},
+	}
+	proto := thrift.NewBinaryProtocol(thrift.NewFramedTransport(transport), false, true)
+	assert.Nil(t, proto.WriteMessageBegin("authenticate", thrift.CALL, 1))
+	assert.Nil(t, proto.WriteBinary([]byte("root")))
+	assert.Nil(t, proto.WriteBinary([]byte("s3cr3t-password")))
+	assert.Nil(t, proto.WriteMessageEnd())
+	assert.Nil(t, proto.Flush())
+
+	dump := out.String()
+	assert.Contains(t, dump, "method: authenticate, seqid: 1")
+	assert.Contains(t, dump, "payload redacted")
+	assert.NotContains(t, dump, "s3cr3t")
+	// no hex dump of the payload follows the header
+	assert.Equal(t, 2, strings.Count(dump, "\n"))
+}
+
+func TestWireDumpDisabled(t *testing.T) {
+	assert.Nil(t, newWireDumper(GetDefaultConf(), DefaultLogger{}))
+}
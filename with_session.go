@@ -0,0 +1,86 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// DefaultRetryMaxBackoff is the max backoff of the retries of WithSession and of WithExecRetries
+const DefaultRetryMaxBackoff = 30 * time.Second
+
+// WithSessionOption is an option of WithSession
+type WithSessionOption func(*withSessionOptions)
+
+type withSessionOptions struct {
+	retries int
+	backoff time.Duration
+	retryIf func(error) bool
+}
+
+// WithRetries retries the callback up to n times on a new session when it fails,
+// waiting a random duration up to backoff before the first retry, doubled after every retry
+// up to DefaultRetryMaxBackoff
+func WithRetries(n int, backoff time.Duration) WithSessionOption {
+	return func(o *withSessionOptions) {
+		o.retries = n
+		o.backoff = backoff
+	}
+}
+
+// WithRetryIf retries only the errors for which retryIf returns true, by default every error is retried
+func WithRetryIf(retryIf func(error) bool) WithSessionOption {
+	return func(o *withSessionOptions) {
+		o.retryIf = retryIf
+	}
+}
+
+// WithSession acquires a session with the credentials of the pool config, calls fn with it
+// and releases it when fn returns or panics, so that the session can not be leaked.
+// The session must not be used after fn returns. If fn or the acquire fails, it is retried
+// according to the WithRetries option, the retries stop when the context is done.
+func (pool *ConnectionPool) WithSession(ctx context.Context, fn func(*Session) error, opts ...WithSessionOption) error {
+	var o withSessionOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	backoff := o.backoff
+	for attempt := 0; ; attempt++ {
+		err := pool.withSession(ctx, fn)
+		if err == nil || attempt >= o.retries || (o.retryIf != nil && !o.retryIf(err)) {
+			return err
+		}
+		// full jitter, like the retries of ExecuteDDL
+		wait := time.Duration(0)
+		if backoff > 0 {
+			wait = time.Duration(pool.randIntn(int(backoff))) + 1
+			if backoff *= 2; backoff > DefaultRetryMaxBackoff {
+				backoff = DefaultRetryMaxBackoff
+			}
+		}
+		timer := pool.conf.Clock.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("failed to retry: %s, last error: %s", ctx.Err().Error(), err.Error())
+		case <-timer.C():
+		}
+	}
+}
+
+func (pool *ConnectionPool) withSession(ctx context.Context, fn func(*Session) error) error {
+	session, err := pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer session.Release()
+	return fn(session)
+}
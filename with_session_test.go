@@ -0,0 +1,62 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithSession(t *testing.T) {
+	var waits []time.Duration
+	conf := NewPoolConf(WithLazyInit(), WithCredentials("root", "nebula"))
+	conf.Clock = &recordingClock{Clock: realClock{}, waits: &waits}
+	// nothing listens on port 1
+	pool, err := NewConnectionPool([]HostAddress{{"127.0.0.1", 1}}, conf, DefaultLogger{})
+	assert.Nil(t, err)
+	defer pool.Close()
+
+	called := false
+	fn := func(*Session) error {
+		called = true
+		return nil
+	}
+	err = pool.WithSession(context.Background(), fn, WithRetries(2, time.Millisecond))
+	assert.NotNil(t, err)
+	assert.False(t, called)
+	assert.Len(t, waits, 2)
+	assert.True(t, waits[1] <= 2*time.Millisecond)
+
+	waits = nil
+	err = pool.WithSession(context.Background(), fn, WithRetries(2, time.Millisecond),
+		WithRetryIf(func(error) bool { return false }))
+	assert.NotNil(t, err)
+	assert.Len(t, waits, 0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = pool.WithSession(ctx, fn, WithRetries(2, time.Hour))
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), fmt.Sprintf("failed to retry: %s", context.Canceled.Error()))
+	}
+}
+
+type recordingClock struct {
+	Clock
+	waits *[]time.Duration
+}
+
+func (c *recordingClock) NewTimer(d time.Duration) Timer {
+	*c.waits = append(*c.waits, d)
+	return c.Clock.NewTimer(d)
+}
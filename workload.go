@@ -0,0 +1,74 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import "context"
+
+type workloadKey struct{}
+
+// WithWorkload returns a context whose acquires take their connections from the partition of the workload,
+// see WithWorkloadPartition. The acquires of a workload without partition share the whole pool.
+func WithWorkload(ctx context.Context, workload string) context.Context {
+	return context.WithValue(ctx, workloadKey{}, workload)
+}
+
+// workloadFrom returns the workload of the context, empty if it has none
+func workloadFrom(ctx context.Context) string {
+	if ctx != nil {
+		if workload, ok := ctx.Value(workloadKey{}).(string); ok {
+			return workload
+		}
+	}
+	return ""
+}
+
+// WithWorkloadPartition limits the connections used at once by the acquires of a workload to size,
+// e.g. WithWorkloadPartition("batch", 5) so that batch jobs can not exhaust the connections
+// needed by the interactive requests. The acquires of the workload wait once it uses size connections,
+// even if the pool has free connections. The partitions are carved from one pool,
+// the connections not used by a workload are available to the others.
+func WithWorkloadPartition(workload string, size int) PoolConfOption {
+	return func(conf *PoolConfig) {
+		partitions := make(map[string]int, len(conf.WorkloadPartitions)+1)
+		for w, s := range conf.WorkloadPartitions {
+			partitions[w] = s
+		}
+		partitions[workload] = size
+		conf.WorkloadPartitions = partitions
+	}
+}
+
+// workloadFullLocked returns true if the workload can not take n more connections.
+// The caller must hold the lock.
+func (pool *ConnectionPool) workloadFullLocked(workload string, n int) bool {
+	size, ok := pool.conf.WorkloadPartitions[workload]
+	return ok && pool.workloadConns[workload]+n > size
+}
+
+// assignWorkloadLocked records the connection as used by the workload. The caller must hold the lock.
+func (pool *ConnectionPool) assignWorkloadLocked(conn *connection, workload string) {
+	if _, ok := pool.conf.WorkloadPartitions[workload]; !ok {
+		return
+	}
+	if pool.workloadConns == nil {
+		pool.workloadConns = make(map[string]int)
+	}
+	conn.workload = workload
+	pool.workloadConns[workload]++
+}
+
+// unassignWorkloadLocked records the connection as no longer used by its workload.
+// The caller must hold the lock.
+func (pool *ConnectionPool) unassignWorkloadLocked(conn *connection) {
+	if conn.workload == "" {
+		return
+	}
+	pool.workloadConns[conn.workload]--
+	conn.workload = ""
+}
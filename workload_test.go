@@ -0,0 +1,54 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkloadPartition(t *testing.T) {
+	conf := NewPoolConf(WithWorkloadPartition("batch", 2), WithWorkloadPartition("reports", 1))
+	assert.Equal(t, map[string]int{"batch": 2, "reports": 1}, conf.WorkloadPartitions)
+	assert.Nil(t, conf.Validate())
+	assert.NotNil(t, NewPoolConf(WithWorkloadPartition("batch", 0)).Validate())
+
+	conf.Clock = realClock{}
+	pool := &ConnectionPool{conf: conf}
+	batch := WithWorkload(context.Background(), "batch")
+	_, err := pool.AcquireN(batch, "root", "nebula", 3)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "the partition of workload batch is 2")
+	}
+
+	conns := []*connection{{}, {}}
+	for _, conn := range conns {
+		pool.assignWorkloadLocked(conn, "batch")
+	}
+	pool.assignWorkloadLocked(&connection{}, "oltp")
+	assert.True(t, pool.workloadFullLocked("batch", 1))
+	assert.False(t, pool.workloadFullLocked("reports", 1))
+	assert.False(t, pool.workloadFullLocked("oltp", 100))
+	assert.Equal(t, map[string]int{"batch": 2, "reports": 0}, pool.Stats().Workloads)
+
+	// the batch partition is full although the pool has free connections
+	ctx, cancel := context.WithTimeout(batch, 10*time.Millisecond)
+	defer cancel()
+	_, err = pool.AcquireN(ctx, "root", "nebula", 1)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), context.DeadlineExceeded.Error())
+	}
+
+	pool.unassignWorkloadLocked(conns[0])
+	assert.False(t, pool.workloadFullLocked("batch", 1))
+	assert.Equal(t, "", conns[0].workload)
+}
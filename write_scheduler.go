@@ -0,0 +1,238 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultWritePartitions is the number of queues of a WriteScheduler
+	DefaultWritePartitions = 16
+	// DefaultWriteBatchWindow is the time a WriteScheduler waits for more writes before executing a batch
+	DefaultWriteBatchWindow = 2 * time.Millisecond
+	// DefaultWriteBatchSize is the max number of statements of a batch of a WriteScheduler
+	DefaultWriteBatchSize = 64
+	// DefaultWriteBatchTimeout is the deadline of the execution of a batch of a WriteScheduler
+	DefaultWriteBatchTimeout = 30 * time.Second
+)
+
+// WriteScheduler batches the write statements of many goroutines into multi-statement requests
+// while preserving the order of the writes of every vertex: the writes are hash partitioned by vid
+// into queues whose batches are executed one after the other, so two writes of a vertex are executed
+// in the order they were scheduled, even if they belong to different batches.
+// A batch is executed as a single request with a session acquired from the pool, within the deadline set
+// with WithWriteBatchTimeout. If it fails, every write of the batch gets the error and the batch may have
+// been partially applied.
+type WriteScheduler struct {
+	clock    Clock
+	window   time.Duration
+	maxBatch int
+	timeout  time.Duration
+	exec     func(ctx context.Context, stmt string, params map[string]interface{}) error
+
+	mu     sync.RWMutex
+	queues []chan *scheduledWrite
+	closed bool
+	wg     sync.WaitGroup
+}
+
+type scheduledWrite struct {
+	stmt   string
+	params map[string]interface{}
+	err    error
+	done   chan struct{}
+}
+
+// NewWriteScheduler returns a WriteScheduler executing the batches with the sessions of the pool.
+// A batch is executed once window has elapsed since its first write, or as soon as it holds maxBatch writes.
+// Zero values default to DefaultWritePartitions, DefaultWriteBatchWindow and DefaultWriteBatchSize.
+func NewWriteScheduler(pool *ConnectionPool, partitions int, window time.Duration, maxBatch int) *WriteScheduler {
+	return newWriteScheduler(pool.conf.Clock, partitions, window, maxBatch, pool.conf.WriteBatchTimeout,
+		func(ctx context.Context, stmt string, params map[string]interface{}) error {
+			if hook := pool.conf.DryRun; hook != nil {
+				return hook(stmt, params)
+			}
+			return pool.WithSession(ctx, func(session *Session) error {
+				resp, err := session.ExecuteWithContext(ctx, stmt, params)
+				if err != nil {
+					return err
+				}
+				if !resp.IsSucceed() {
+					return fmt.Errorf("failed to execute batch: %s", resp.GetErrorMsg())
+				}
+				return nil
+			})
+		})
+}
+
+func newWriteScheduler(clock Clock, partitions int, window time.Duration, maxBatch int, timeout time.Duration,
+	exec func(context.Context, string, map[string]interface{}) error) *WriteScheduler {
+	if partitions <= 0 {
+		partitions = DefaultWritePartitions
+	}
+	if window <= 0 {
+		window = DefaultWriteBatchWindow
+	}
+	if maxBatch <= 0 {
+		maxBatch = DefaultWriteBatchSize
+	}
+	if timeout <= 0 {
+		timeout = DefaultWriteBatchTimeout
+	}
+	s := &WriteScheduler{clock: clock, window: window, maxBatch: maxBatch, timeout: timeout, exec: exec}
+	s.queues = make([]chan *scheduledWrite, partitions)
+	for i := range s.queues {
+		s.queues[i] = make(chan *scheduledWrite, maxBatch)
+		s.wg.Add(1)
+		go s.run(s.queues[i])
+	}
+	return s
+}
+
+// WithWriteBatchTimeout sets the deadline of the execution of a batch of the write schedulers of the pool,
+// 0 value means DefaultWriteBatchTimeout
+func WithWriteBatchTimeout(timeout time.Duration) PoolConfOption {
+	return func(conf *PoolConfig) {
+		conf.WriteBatchTimeout = timeout
+	}
+}
+
+// Write schedules the write statement of the vertex, e.g. an INSERT or an UPDATE of the vertex or of an edge
+// from it, and waits until its batch has been executed. The context only bounds the wait of the caller:
+// once scheduled, the write is executed even if the context is done.
+// If the context has a dry-run hook, the write is rendered to it right away instead, see WithDryRun.
+func (s *WriteScheduler) Write(ctx context.Context, vid VID, stmt string, params map[string]interface{}) error {
+	if hook := dryRunFrom(ctx); hook != nil {
+		return hook(stmt, params)
+	}
+	w, err := s.schedule(ctx, vid, stmt, params)
+	if err != nil {
+		return err
+	}
+	select {
+	case <-w.done:
+		return w.err
+	case <-ctx.Done():
+		return fmt.Errorf("failed to write: %s", ctx.Err().Error())
+	}
+}
+
+// Close executes the scheduled writes and stops the scheduler, later writes fail
+func (s *WriteScheduler) Close() {
+	s.mu.Lock()
+	if !s.closed {
+		s.closed = true
+		for _, queue := range s.queues {
+			close(queue)
+		}
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+}
+
+// schedule appends the write to the queue of the partition of the vertex
+func (s *WriteScheduler) schedule(ctx context.Context, vid VID, stmt string, params map[string]interface{}) (*scheduledWrite, error) {
+	w := &scheduledWrite{stmt: stmt, params: params, done: make(chan struct{})}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return nil, fmt.Errorf("failed to write: the scheduler has been closed")
+	}
+	select {
+	case s.queues[writePartition(vid, len(s.queues))] <- w:
+		return w, nil
+	case <-ctx.Done():
+		return nil, fmt.Errorf("failed to write: %s", ctx.Err().Error())
+	}
+}
+
+func writePartition(vid VID, partitions int) int {
+	h := fnv.New32a()
+	h.Write([]byte(vid.Raw()))
+	return int(h.Sum32() % uint32(partitions))
+}
+
+// run executes the batches of a queue one after the other
+func (s *WriteScheduler) run(queue chan *scheduledWrite) {
+	defer s.wg.Done()
+	for first := range queue {
+		batch := []*scheduledWrite{first}
+		timer := s.clock.NewTimer(s.window)
+	collect:
+		for len(batch) < s.maxBatch {
+			select {
+			case w, ok := <-queue:
+				if !ok {
+					break collect
+				}
+				batch = append(batch, w)
+			case <-timer.C():
+				break collect
+			}
+		}
+		timer.Stop()
+		s.execute(batch)
+	}
+}
+
+// execute executes the batch in order, split where the parameters of a write conflict with the previous ones
+func (s *WriteScheduler) execute(batch []*scheduledWrite) {
+	for len(batch) > 0 {
+		n, stmts, params := 0, make([]string, 0, len(batch)), map[string]interface{}{}
+		for ; n < len(batch) && mergeParams(params, batch[n].params); n++ {
+			stmts = append(stmts, trimStatement(batch[n].stmt))
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+		err := s.exec(ctx, strings.Join(stmts, "; "), params)
+		cancel()
+		for _, w := range batch[:n] {
+			w.err = err
+			close(w.done)
+		}
+		batch = batch[n:]
+	}
+}
+
+// trimStatement strips the trailing whitespace, comments and semicolons of the statement,
+// so that a line comment ending it does not swallow the next statements of the batch
+func trimStatement(stmt string) string {
+	tokens := lexStatement(stmt)
+	for len(tokens) > 0 {
+		last := tokens[len(tokens)-1]
+		if last.kind != tokenSpace && (last.kind != tokenPunct || last.text != ";") {
+			break
+		}
+		tokens = tokens[:len(tokens)-1]
+	}
+	var sb strings.Builder
+	for _, t := range tokens {
+		sb.WriteString(t.text)
+	}
+	return strings.TrimSpace(sb.String())
+}
+
+// mergeParams adds the parameters to merged, unless one of them has another value in merged
+func mergeParams(merged, params map[string]interface{}) bool {
+	for k, v := range params {
+		if m, ok := merged[k]; ok && !reflect.DeepEqual(m, v) {
+			return false
+		}
+	}
+	for k, v := range params {
+		merged[k] = v
+	}
+	return true
+}
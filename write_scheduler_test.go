@@ -0,0 +1,118 @@
+/*
+ *
+ * Copyright (c) 2022 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ *
+ */
+
+package nebula_go
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type execRecorder struct {
+	mu      sync.Mutex
+	batches []string
+	params  []map[string]interface{}
+}
+
+func (r *execRecorder) exec(ctx context.Context, stmt string, params map[string]interface{}) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.batches = append(r.batches, stmt)
+	r.params = append(r.params, params)
+	if stmt == "fail" {
+		return fmt.Errorf("failed to execute batch: syntax error")
+	}
+	return nil
+}
+
+func TestWriteSchedulerOrder(t *testing.T) {
+	recorder := &execRecorder{}
+	s := newWriteScheduler(NewManualClock(time.Now()), 1, time.Second, 3, 0, recorder.exec)
+	var writes []*scheduledWrite
+	for i := 0; i < 5; i++ {
+		w, err := s.schedule(context.Background(), StringVID("p1"), fmt.Sprintf("UPDATE VERTEX ON player 'p1' SET age = %d;", i), nil)
+		assert.Nil(t, err)
+		writes = append(writes, w)
+	}
+	// the first batch is full, the second one is executed by Close
+	<-writes[2].done
+	s.Close()
+	for _, w := range writes {
+		assert.Nil(t, w.err)
+	}
+	assert.Equal(t, []string{
+		"UPDATE VERTEX ON player 'p1' SET age = 0; UPDATE VERTEX ON player 'p1' SET age = 1; UPDATE VERTEX ON player 'p1' SET age = 2",
+		"UPDATE VERTEX ON player 'p1' SET age = 3; UPDATE VERTEX ON player 'p1' SET age = 4",
+	}, recorder.batches)
+
+	assert.EqualError(t, s.Write(context.Background(), StringVID("p1"), "YIELD 1", nil),
+		"failed to write: the scheduler has been closed")
+}
+
+func TestWriteSchedulerParams(t *testing.T) {
+	recorder := &execRecorder{}
+	s := newWriteScheduler(NewManualClock(time.Now()), 1, time.Second, 10, 0, recorder.exec)
+	for _, p := range []map[string]interface{}{{"a": 1}, {"a": 1, "b": 2}, {"a": 3}} {
+		_, err := s.schedule(context.Background(), IntVID(1), "INSERT", p)
+		assert.Nil(t, err)
+	}
+	s.Close()
+	assert.Equal(t, []string{"INSERT; INSERT", "INSERT"}, recorder.batches)
+	assert.Equal(t, []map[string]interface{}{{"a": 1, "b": 2}, {"a": 3}}, recorder.params)
+}
+
+func TestWriteSchedulerError(t *testing.T) {
+	recorder := &execRecorder{}
+	s := newWriteScheduler(realClock{}, 4, time.Millisecond, 10, 0, recorder.exec)
+	defer s.Close()
+	assert.EqualError(t, s.Write(context.Background(), IntVID(1), "fail", nil), "failed to execute batch: syntax error")
+	assert.Nil(t, s.Write(context.Background(), IntVID(1), "INSERT", nil))
+}
+
+func TestWritePartition(t *testing.T) {
+	assert.Equal(t, writePartition(StringVID("p1"), 16), writePartition(StringVID("p1"), 16))
+	partitions := map[int]bool{}
+	for i := 0; i < 100; i++ {
+		partitions[writePartition(IntVID(int64(i)), 16)] = true
+	}
+	assert.True(t, len(partitions) > 1)
+}
+
+func TestWriteSchedulerTimeout(t *testing.T) {
+	var deadline time.Time
+	s := newWriteScheduler(realClock{}, 1, time.Millisecond, 10, time.Hour,
+		func(ctx context.Context, stmt string, params map[string]interface{}) error {
+			deadline, _ = ctx.Deadline()
+			return nil
+		})
+	defer s.Close()
+	assert.Nil(t, s.Write(context.Background(), IntVID(1), "INSERT", nil))
+	assert.WithinDuration(t, time.Now().Add(time.Hour), deadline, time.Minute)
+}
+
+func TestWriteSchedulerLineComments(t *testing.T) {
+	recorder := &execRecorder{}
+	s := newWriteScheduler(NewManualClock(time.Now()), 1, time.Second, 10, 0, recorder.exec)
+	for _, stmt := range []string{
+		"INSERT VERTEX player(age) VALUES 'p1':(1) # first",
+		"INSERT VERTEX player(age) VALUES 'p2':(2); -- second\n",
+		"INSERT VERTEX player(age) VALUES 'p3':(3) // third;",
+		"INSERT VERTEX player(age) VALUES 'p4':(4) /* fourth */",
+	} {
+		_, err := s.schedule(context.Background(), IntVID(1), stmt, nil)
+		assert.Nil(t, err)
+	}
+	s.Close()
+	assert.Equal(t, []string{"INSERT VERTEX player(age) VALUES 'p1':(1); INSERT VERTEX player(age) VALUES 'p2':(2); " +
+		"INSERT VERTEX player(age) VALUES 'p3':(3); INSERT VERTEX player(age) VALUES 'p4':(4)"}, recorder.batches)
+}